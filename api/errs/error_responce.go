@@ -1,15 +1,22 @@
 package errs
 
 const (
-	USER_EXISTS     = "USER_EXISTS"
-	TEAM_EXISTS     = "TEAM_EXISTS"
-	PR_EXISTS       = "PR_EXISTS"
-	PR_MERGED       = "PR_MERGED"
-	NOT_ASSIGNED    = "NOT_ASSIGNED"
-	NO_CANDIDATE    = "NO_CANDIDATE"
-	NOT_FOUND       = "NOT_FOUND"
-	INTERNAL_SERVER = "INTERNAL_SERVER"
-	INVALID_INPUT   = "INVALID_INPUT"
+	USER_EXISTS       = "USER_EXISTS"
+	TEAM_EXISTS       = "TEAM_EXISTS"
+	PR_EXISTS         = "PR_EXISTS"
+	PR_MERGED         = "PR_MERGED"
+	PR_CLOSED         = "PR_CLOSED"
+	PR_ARCHIVED       = "PR_ARCHIVED"
+	PR_DRAFT          = "PR_DRAFT"
+	NOT_ASSIGNED      = "NOT_ASSIGNED"
+	NOT_APPROVED      = "NOT_APPROVED"
+	NO_CANDIDATE      = "NO_CANDIDATE"
+	NOT_FOUND         = "NOT_FOUND"
+	INTERNAL_SERVER   = "INTERNAL_SERVER"
+	INVALID_INPUT     = "INVALID_INPUT"
+	TEAM_HAS_OPEN_PRS = "TEAM_HAS_OPEN_PRS"
+	UNAUTHORIZED      = "UNAUTHORIZED"
+	FORBIDDEN         = "FORBIDDEN"
 )
 
 type ResponceError struct {
@@ -17,6 +24,19 @@ type ResponceError struct {
 	Message string `json:"message"`
 }
 
+// ответ TEAM_HAS_OPEN_PRS со списком pull request, которые мешают удалению команды
+type TeamHasOpenPRsResponce struct {
+	ResponceError
+	PullRequests []string `json:"pull_requests"`
+}
+
+func NewTeamHasOpenPRsResponce(prs []string) TeamHasOpenPRsResponce {
+	return TeamHasOpenPRsResponce{
+		ResponceError: ErrorTeamHasOpenPRs,
+		PullRequests:  prs,
+	}
+}
+
 var (
 
 	// INVALID_INPUT
@@ -30,6 +50,16 @@ var (
 		Message: "invalid input format",
 	}
 
+	ErrorInvalidTeamSettings = ResponceError{
+		Code:    INVALID_INPUT,
+		Message: "min_reviewers must not exceed max_reviewers",
+	}
+
+	ErrorInvalidCursor = ResponceError{
+		Code:    INVALID_INPUT,
+		Message: "invalid cursor",
+	}
+
 	// USER_EXISTS
 	ErrorUserAlreadyExists = ResponceError{
 		Code:    USER_EXISTS,
@@ -57,12 +87,28 @@ var (
 		Message: "pr not found",
 	}
 
+	ErrorUserNotInTeam = ResponceError{
+		Code:    NOT_FOUND,
+		Message: "user is not a member of the team",
+	}
+
+	ErrorWebhookNotFound = ResponceError{
+		Code:    NOT_FOUND,
+		Message: "webhook subscription not found",
+	}
+
 	// TEAM_EXISTS
 	ErrorTeamAlreadyExists = ResponceError{
 		Code:    TEAM_EXISTS,
 		Message: "team already exists",
 	}
 
+	// TEAM_HAS_OPEN_PRS
+	ErrorTeamHasOpenPRs = ResponceError{
+		Code:    TEAM_HAS_OPEN_PRS,
+		Message: "team members have open pull requests or reviews",
+	}
+
 	// INTERNAL_SERVER
 	ErrorInternal = ResponceError{
 		Code:    INTERNAL_SERVER,
@@ -81,15 +127,71 @@ var (
 		Message: "cannot reassign on merged PR",
 	}
 
+	ErrorPRMergedClose = ResponceError{
+		Code:    PR_MERGED,
+		Message: "cannot close or reopen merged PR",
+	}
+
+	ErrorPRMergedReview = ResponceError{
+		Code:    PR_MERGED,
+		Message: "cannot review merged PR",
+	}
+
+	// PR_CLOSED
+	ErrorPRClosed = ResponceError{
+		Code:    PR_CLOSED,
+		Message: "cannot merge, reassign or review closed PR, reopen it first",
+	}
+
+	// PR_ARCHIVED
+	ErrorPRArchived = ResponceError{
+		Code:    PR_ARCHIVED,
+		Message: "PR is archived after its team was deleted",
+	}
+
+	// PR_DRAFT
+	ErrorPRDraft = ResponceError{
+		Code:    PR_DRAFT,
+		Message: "cannot merge draft PR, mark it ready first",
+	}
+
 	// NO_CANDIDATE
 	ErrorNoCandidateToReassign = ResponceError{
 		Code:    NO_CANDIDATE,
 		Message: "no active replacement candidate in team",
 	}
 
+	ErrorNotEnoughReviewers = ResponceError{
+		Code:    NO_CANDIDATE,
+		Message: "not enough active reviewers in team",
+	}
+
 	// NOT_ASSIGNED
 	ErrorUserNotAssigned = ResponceError{
 		Code:    NOT_ASSIGNED,
 		Message: "reviewer is not assigned to this PR",
 	}
+
+	// NOT_APPROVED
+	ErrorNotEnoughApprovals = ResponceError{
+		Code:    NOT_APPROVED,
+		Message: "not enough approvals to merge PR",
+	}
+
+	// UNAUTHORIZED
+	ErrorUnauthorized = ResponceError{
+		Code:    UNAUTHORIZED,
+		Message: "missing or invalid bearer token",
+	}
+
+	// FORBIDDEN
+	ErrorForbidden = ResponceError{
+		Code:    FORBIDDEN,
+		Message: "token does not allow this action",
+	}
+
+	ErrorRoleForbidden = ResponceError{
+		Code:    FORBIDDEN,
+		Message: "user role does not allow this action",
+	}
 )
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+type MetricsHandler struct {
+	handler fiber.Handler
+}
+
+// конструктор принимает http.Handler, который отдает метрики в формате Prometheus
+func NewMetricsHandler(h http.Handler) *MetricsHandler {
+	return &MetricsHandler{
+		handler: adaptor.HTTPHandler(h),
+	}
+}
+
+func (mh *MetricsHandler) GetMetrics(c *fiber.Ctx) error {
+	return mh.handler(c)
+}
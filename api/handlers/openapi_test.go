@@ -0,0 +1,1018 @@
+package handlers
+
+import (
+	"avito_intern/api/middleware"
+	"avito_intern/api/openapi"
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/service"
+	"avito_intern/mocks"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type contractMocks struct {
+	user    *mocks.MockUserService
+	team    *mocks.MockTeamService
+	pr      *mocks.MockPRService
+	stats   *mocks.MockStatsService
+	webhook *mocks.MockWebhookService
+	role    *mocks.MockRoleService
+}
+
+// приложение со всеми хэндлерами и проверкой запросов и ответов по спецификации
+func newContractApp(t *testing.T, ctrl *gomock.Controller) (*fiber.App, *contractMocks) {
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	specJSON, err := openapi.JSON(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator, err := middleware.OpenAPI(spec, logger, middleware.OpenAPIOptions{
+		ValidateRequests:  true,
+		ValidateResponses: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &contractMocks{
+		user:    mocks.NewMockUserService(ctrl),
+		team:    mocks.NewMockTeamService(ctrl),
+		pr:      mocks.NewMockPRService(ctrl),
+		stats:   mocks.NewMockStatsService(ctrl),
+		webhook: mocks.NewMockWebhookService(ctrl),
+		role:    mocks.NewMockRoleService(ctrl),
+	}
+
+	userHandler := NewUserHandler(logger, m.user)
+	teamHandler := NewTeamHandler(logger, m.team)
+	prHandler := NewPRHandler(logger, m.pr)
+	statsHandler := NewStatsHandler(logger, m.stats)
+	webhookHandler := NewWebhookHandler(logger, m.webhook)
+	roleHandler := NewRoleHandler(logger, m.role)
+	docsHandler := NewDocsHandler(specJSON)
+
+	app := fiber.New()
+	app.Use(validator)
+
+	app.Post("/team/add", teamHandler.CreateTeam)
+	app.Get("/team/get", teamHandler.GetTeam)
+	app.Get("/team/settings", teamHandler.GetTeamSettings)
+	app.Post("/team/settings", teamHandler.UpdateTeamSettings)
+	app.Post("/team/addMembers", teamHandler.AddMembers)
+	app.Post("/team/removeMembers", teamHandler.RemoveMembers)
+	app.Post("/team/moveMember", teamHandler.MoveMember)
+	app.Post("/team/delete", teamHandler.DeleteTeam)
+	app.Post("/team/deactivateUsers", teamHandler.DeactivateUsers)
+	app.Post("/users/setIsActive", userHandler.SetIsActive)
+	app.Get("/users/getReview", userHandler.GetReview)
+	app.Post("/users/setUsername", userHandler.SetUsername)
+	app.Post("/pullRequest/create", prHandler.CreatePR)
+	app.Post("/pullRequest/markReady", prHandler.MarkReady)
+	app.Post("/pullRequest/merge", prHandler.MergePR)
+	app.Post("/pullRequest/close", prHandler.ClosePR)
+	app.Post("/pullRequest/reopen", prHandler.ReopenPR)
+	app.Post("/pullRequest/reassign", prHandler.ReassignPR)
+	app.Post("/pullRequest/review", prHandler.SubmitReview)
+	app.Get("/pullRequest/get", prHandler.GetPR)
+	app.Get("/pullRequest/list", prHandler.ListPRs)
+	app.Get("/pullRequest/history", prHandler.GetHistory)
+	app.Get("/stats/reviewers", statsHandler.GetReviewerStats)
+	app.Get("/stats/pullRequests", statsHandler.GetPullRequestStats)
+	app.Post("/webhooks/add", webhookHandler.CreateSubscription)
+	app.Get("/webhooks/list", webhookHandler.GetSubscriptions)
+	app.Post("/webhooks/delete", webhookHandler.DeleteSubscription)
+	app.Get("/webhooks/deliveries", webhookHandler.GetDeliveries)
+	app.Post("/roles/grant", roleHandler.GrantRole)
+	app.Post("/roles/revoke", roleHandler.RevokeRole)
+	app.Get("/roles/list", roleHandler.ListRoles)
+	app.Get("/openapi.json", docsHandler.GetSpec)
+	app.Get("/docs", docsHandler.GetSwaggerUI)
+
+	return app, m
+}
+
+func TestOpenAPI_Contract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app, m := newContractApp(t, ctrl)
+
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	mergedAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	median := 3600.0
+
+	tests := []struct {
+		Name         string
+		Method       string
+		Path         string
+		Body         string
+		ExpectedCode int
+		MockSetup    func(m *contractMocks)
+	}{
+		{
+			Name:         "team_add_inactive_member",
+			Method:       "POST",
+			Path:         "/team/add",
+			Body:         `{"team_name": "backend", "members": [{"user_id": "u1", "username": "name1", "is_active": false}]}`,
+			ExpectedCode: 201,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).Return(&enteties.Team{
+					TeamName: "backend",
+					Members: []enteties.TeamMember{
+						{UserID: "u1", UserName: "name1", IsActive: false},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "team_add_error_missing_is_active",
+			Method:       "POST",
+			Path:         "/team/add",
+			Body:         `{"team_name": "backend", "members": [{"user_id": "u1", "username": "name1"}]}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "team_add_error_invalid_json",
+			Method:       "POST",
+			Path:         "/team/add",
+			Body:         `{"team_name": `,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "team_add_error_team_exists",
+			Method:       "POST",
+			Path:         "/team/add",
+			Body:         `{"team_name": "backend", "members": []}`,
+			ExpectedCode: 400,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).Return(nil, service.ErrorTeamExists)
+			},
+		},
+		{
+			Name:         "team_get",
+			Method:       "GET",
+			Path:         "/team/get?team_name=backend",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().GetTeam(gomock.Any(), "backend").Return(&enteties.Team{
+					TeamName: "backend",
+					Members:  []enteties.TeamMember{},
+				}, nil)
+			},
+		},
+		{
+			Name:         "team_get_error_missing_query",
+			Method:       "GET",
+			Path:         "/team/get",
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "team_get_settings",
+			Method:       "GET",
+			Path:         "/team/settings?team_name=backend",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().GetTeamSettings(gomock.Any(), "backend").Return(&enteties.TeamSettings{
+					TeamName:     "backend",
+					MinReviewers: 1,
+					MaxReviewers: 2,
+				}, nil)
+			},
+		},
+		{
+			Name:         "team_update_settings_error_negative",
+			Method:       "POST",
+			Path:         "/team/settings",
+			Body:         `{"team_name": "backend", "min_reviewers": -1, "max_reviewers": 2}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "team_add_members",
+			Method:       "POST",
+			Path:         "/team/addMembers",
+			Body:         `{"team_name": "backend", "members": [{"user_id": "u2", "username": "name2", "is_active": true}]}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().AddMembers(gomock.Any(), gomock.Any()).Return(&enteties.Team{
+					TeamName: "backend",
+					Members: []enteties.TeamMember{
+						{UserID: "u2", UserName: "name2", IsActive: true},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "team_remove_members",
+			Method:       "POST",
+			Path:         "/team/removeMembers",
+			Body:         `{"team_name": "backend", "user_ids": ["u2"]}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().RemoveMembers(gomock.Any(), gomock.Any()).Return(&enteties.RemoveTeamMembersResponce{
+					Team: enteties.Team{
+						TeamName: "backend",
+						Members:  []enteties.TeamMember{},
+					},
+					Reassigned: []enteties.ReviewerReplacement{
+						{PullRequestID: "pr1", OldUserID: "u2", ReplacedBy: "u3"},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "team_move_member",
+			Method:       "POST",
+			Path:         "/team/moveMember",
+			Body:         `{"user_id": "u2", "team_name": "frontend"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().MoveMember(gomock.Any(), gomock.Any()).Return(&enteties.MoveTeamMemberResponce{
+					User: enteties.User{
+						UserID:   "u2",
+						UserName: "name2",
+						TeamName: "frontend",
+						IsActive: true,
+					},
+					Reassigned: []enteties.ReviewerReplacement{},
+				}, nil)
+			},
+		},
+		{
+			Name:         "team_delete_error_unknown_mode",
+			Method:       "POST",
+			Path:         "/team/delete",
+			Body:         `{"team_name": "backend", "mode": "drop"}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "team_delete_error_open_prs",
+			Method:       "POST",
+			Path:         "/team/delete",
+			Body:         `{"team_name": "backend"}`,
+			ExpectedCode: 409,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().DeleteTeam(gomock.Any(), gomock.Any()).Return(nil,
+					&service.TeamHasOpenPRsError{PRs: []string{"pr1"}})
+			},
+		},
+		{
+			Name:         "team_deactivate_users",
+			Method:       "POST",
+			Path:         "/team/deactivateUsers",
+			Body:         `{"user_ids": ["u1"]}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().DeactivateUsers(gomock.Any(), gomock.Any()).Return(&enteties.DeactivateUsersResponce{
+					DeactivatedUsers: []string{"u1"},
+					Reassigned:       []enteties.ReviewerReplacement{},
+					WithoutCandidate: []enteties.ReviewerReplacement{
+						{PullRequestID: "pr1", OldUserID: "u1"},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "users_set_is_active",
+			Method:       "POST",
+			Path:         "/users/setIsActive",
+			Body:         `{"user_id": "u1", "is_active": false}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.user.EXPECT().SetIsActive(gomock.Any(), "u1", false).Return(&enteties.User{
+					UserID:   "u1",
+					UserName: "name1",
+					TeamName: "backend",
+					IsActive: false,
+				}, nil)
+			},
+		},
+		{
+			Name:         "users_set_is_active_error_wrong_type",
+			Method:       "POST",
+			Path:         "/users/setIsActive",
+			Body:         `{"user_id": "u1", "is_active": "no"}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "users_get_review",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.user.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{UserID: "u1"}).Return(&enteties.UserReviews{
+					UserID: "u1",
+					PullRequests: []enteties.PullRequestShort{
+						{PullRequestID: "pr1", PulRequestName: "name", AuthorID: "u2", Status: enteties.PullRequestStatusOpen},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "users_get_review_include_drafts",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1&include_drafts=true",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.user.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{UserID: "u1", IncludeDrafts: true}).Return(&enteties.UserReviews{
+					UserID: "u1",
+					PullRequests: []enteties.PullRequestShort{
+						{PullRequestID: "pr1", PulRequestName: "name", AuthorID: "u2", Status: enteties.PullRequestStatusOpen, IsDraft: true},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "users_get_review_page",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1&status=OPEN&sort=desc&limit=1&cursor=c1",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.user.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{
+					UserID: "u1",
+					Status: enteties.PullRequestStatusOpen,
+					Sort:   enteties.SortOrderDesc,
+					Limit:  1,
+					Cursor: "c1",
+				}).Return(&enteties.UserReviews{
+					UserID: "u1",
+					PullRequests: []enteties.PullRequestShort{
+						{PullRequestID: "pr1", PulRequestName: "name", AuthorID: "u2", Status: enteties.PullRequestStatusOpen, CreatedAt: &createdAt},
+					},
+					NextCursor: "c2",
+				}, nil)
+			},
+		},
+		{
+			Name:         "users_get_review_error_invalid_cursor",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1&cursor=broken",
+			ExpectedCode: 400,
+			MockSetup: func(m *contractMocks) {
+				m.user.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{UserID: "u1", Cursor: "broken"}).Return(nil, service.ErrorInvalidCursor)
+			},
+		},
+		{
+			Name:         "users_get_review_error_invalid_limit",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1&limit=abc",
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "users_set_username_error_not_found",
+			Method:       "POST",
+			Path:         "/users/setUsername",
+			Body:         `{"user_id": "u1", "username": "new"}`,
+			ExpectedCode: 404,
+			MockSetup: func(m *contractMocks) {
+				m.user.EXPECT().SetUsername(gomock.Any(), "u1", "new").Return(nil, service.ErrorUserNotFound)
+			},
+		},
+		{
+			Name:         "pr_create",
+			Method:       "POST",
+			Path:         "/pullRequest/create",
+			Body:         `{"pull_request_id": "pr1", "pull_request_name": "name", "author_id": "u1"}`,
+			ExpectedCode: 201,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().CreatePR(gomock.Any(), gomock.Any()).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusOpen,
+					AssignedReviewers: []string{"u2", "u3"},
+					CreatedAt:         &createdAt,
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_create_error_no_candidate",
+			Method:       "POST",
+			Path:         "/pullRequest/create",
+			Body:         `{"pull_request_id": "pr1", "pull_request_name": "name", "author_id": "u1"}`,
+			ExpectedCode: 409,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().CreatePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorNotEnoughReviewers)
+			},
+		},
+		{
+			Name:         "pr_merge",
+			Method:       "POST",
+			Path:         "/pullRequest/merge",
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().MergePR(gomock.Any(), gomock.Any()).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusMerged,
+					AssignedReviewers: []string{},
+					CreatedAt:         &createdAt,
+					MergedAt:          &mergedAt,
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_merge_error_closed",
+			Method:       "POST",
+			Path:         "/pullRequest/merge",
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().MergePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsClosed)
+			},
+		},
+		{
+			Name:         "pr_merge_error_not_approved",
+			Method:       "POST",
+			Path:         "/pullRequest/merge",
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().MergePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorNotEnoughApprovals)
+			},
+		},
+		{
+			Name:         "pr_review",
+			Method:       "POST",
+			Path:         "/pullRequest/review",
+			Body:         `{"pull_request_id": "pr1", "user_id": "u2", "state": "APPROVED"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().SubmitReview(gomock.Any(), &enteties.ReviewPullRequest{
+					PullRequestID: "pr1",
+					UserID:        "u2",
+					State:         enteties.ReviewStateApproved,
+				}).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusOpen,
+					AssignedReviewers: []string{"u2", "u3"},
+					Reviews: []enteties.ReviewerReview{
+						{UserID: "u2", State: enteties.ReviewStateApproved, ReviewedAt: &mergedAt},
+						{UserID: "u3", State: enteties.ReviewStatePending},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_review_error_invalid_state",
+			Method:       "POST",
+			Path:         "/pullRequest/review",
+			Body:         `{"pull_request_id": "pr1", "user_id": "u2", "state": "PENDING"}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "pr_review_error_not_assigned",
+			Method:       "POST",
+			Path:         "/pullRequest/review",
+			Body:         `{"pull_request_id": "pr1", "user_id": "u4", "state": "CHANGES_REQUESTED"}`,
+			ExpectedCode: 409,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().SubmitReview(gomock.Any(), gomock.Any()).Return(nil, service.ErrorUserNotAssigned)
+			},
+		},
+		{
+			Name:         "pr_merge_error_draft",
+			Method:       "POST",
+			Path:         "/pullRequest/merge",
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().MergePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsDraft)
+			},
+		},
+		{
+			Name:         "pr_mark_ready",
+			Method:       "POST",
+			Path:         "/pullRequest/markReady",
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().MarkReady(gomock.Any(), gomock.Any()).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusOpen,
+					AssignedReviewers: []string{"u2", "u3"},
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_close",
+			Method:       "POST",
+			Path:         "/pullRequest/close",
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().ClosePR(gomock.Any(), gomock.Any()).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusClosed,
+					AssignedReviewers: []string{"u2"},
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_reopen",
+			Method:       "POST",
+			Path:         "/pullRequest/reopen",
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().ReopenPR(gomock.Any(), gomock.Any()).Return(&enteties.ReopenPullRequestResponce{
+					PR: enteties.PullRequest{
+						PullRequestID:     "pr1",
+						PulRequestName:    "name",
+						AuthorID:          "u1",
+						Status:            enteties.PullRequestStatusOpen,
+						AssignedReviewers: []string{"u3"},
+					},
+					Reassigned:       []enteties.ReviewerReplacement{{PullRequestID: "pr1", OldUserID: "u2", ReplacedBy: "u3"}},
+					WithoutCandidate: []enteties.ReviewerReplacement{},
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_reassign_error_reason_too_long",
+			Method:       "POST",
+			Path:         "/pullRequest/reassign",
+			Body:         `{"pull_request_id": "pr1", "old_user_id": "u2", "reason": "` + strings.Repeat("a", 256) + `"}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "pr_reassign",
+			Method:       "POST",
+			Path:         "/pullRequest/reassign",
+			Body:         `{"pull_request_id": "pr1", "old_user_id": "u2"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().ReassignPR(gomock.Any(), gomock.Any()).Return(&enteties.ReassignPullRequestResponce{
+					PR: enteties.PullRequest{
+						PullRequestID:     "pr1",
+						PulRequestName:    "name",
+						AuthorID:          "u1",
+						Status:            enteties.PullRequestStatusOpen,
+						AssignedReviewers: []string{"u4"},
+					},
+					ReplacedBy: "u4",
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_history",
+			Method:       "GET",
+			Path:         "/pullRequest/history?pull_request_id=pr1",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().GetHistory(gomock.Any(), "pr1").Return(&enteties.PullRequestHistory{
+					PullRequestID: "pr1",
+					Events: []enteties.AssignmentEvent{
+						{
+							EventID:       1,
+							PullRequestID: "pr1",
+							EventType:     enteties.AssignmentEventAssign,
+							NewUserID:     "u2",
+							Actor:         service.ActorSystem,
+							Reason:        enteties.AssignmentReasonPRCreated,
+							CreatedAt:     createdAt,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_get",
+			Method:       "GET",
+			Path:         "/pullRequest/get?pull_request_id=pr1",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().GetPR(gomock.Any(), "pr1").Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusMerged,
+					AssignedReviewers: []string{"u2"},
+					Reviews: []enteties.ReviewerReview{
+						{UserID: "u2", State: enteties.ReviewStateApproved, ReviewedAt: &createdAt},
+					},
+					MergedAt: &mergedAt,
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_get_error_not_found",
+			Method:       "GET",
+			Path:         "/pullRequest/get?pull_request_id=unknown",
+			ExpectedCode: 404,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().GetPR(gomock.Any(), "unknown").Return(nil, service.ErrorPRNotFound)
+			},
+		},
+		{
+			Name:         "pr_list",
+			Method:       "GET",
+			Path:         "/pullRequest/list?status=MERGED&team_name=backend&reviewer_id=u2&name=fix&merged_from=2025-01-01T00:00:00Z&limit=1",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+				m.pr.EXPECT().ListPRs(gomock.Any(), &enteties.PullRequestListFilter{
+					Status:     enteties.PullRequestStatusMerged,
+					TeamName:   "backend",
+					ReviewerID: "u2",
+					Name:       "fix",
+					MergedFrom: &from,
+					Limit:      1,
+				}).Return(&enteties.PullRequestList{
+					PullRequests: []enteties.PullRequestShort{
+						{PullRequestID: "pr1", PulRequestName: "bugfix", AuthorID: "u1", Status: enteties.PullRequestStatusMerged, CreatedAt: &createdAt},
+					},
+					NextCursor: "c2",
+				}, nil)
+			},
+		},
+		{
+			Name:         "pr_list_error_invalid_range",
+			Method:       "GET",
+			Path:         "/pullRequest/list?created_from=2025-01-02T00:00:00Z&created_to=2025-01-01T00:00:00Z",
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "pr_list_error_invalid_cursor",
+			Method:       "GET",
+			Path:         "/pullRequest/list?cursor=broken",
+			ExpectedCode: 400,
+			MockSetup: func(m *contractMocks) {
+				m.pr.EXPECT().ListPRs(gomock.Any(), &enteties.PullRequestListFilter{Cursor: "broken"}).Return(nil, service.ErrorInvalidCursor)
+			},
+		},
+		{
+			Name:         "stats_reviewers_error_invalid_time",
+			Method:       "GET",
+			Path:         "/stats/reviewers?from=yesterday",
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "stats_reviewers",
+			Method:       "GET",
+			Path:         "/stats/reviewers",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.stats.EXPECT().GetReviewerStats(gomock.Any(), gomock.Any()).Return(&enteties.ReviewerStats{
+					Reviewers: []enteties.ReviewerStat{
+						{UserID: "u1", UserName: "name1", TeamName: "backend", TotalAssignments: 1, OpenReviews: 1},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "stats_pull_requests",
+			Method:       "GET",
+			Path:         "/stats/pullRequests?group_by=week",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.stats.EXPECT().GetPullRequestStats(gomock.Any(), gomock.Any()).Return(&enteties.PullRequestStats{
+					PullRequestStatsFilter: enteties.PullRequestStatsFilter{
+						GroupBy: enteties.PullRequestStatsGroupByWeek,
+					},
+					StatusCounts: map[enteties.PullRequestStatus]int{
+						enteties.PullRequestStatusOpen:   1,
+						enteties.PullRequestStatusMerged: 1,
+					},
+					Groups: []enteties.PullRequestGroupStats{
+						{
+							Group: "2024-12-30",
+							Total: 2,
+							StatusCounts: map[enteties.PullRequestStatus]int{
+								enteties.PullRequestStatusOpen:   1,
+								enteties.PullRequestStatusMerged: 1,
+							},
+							MedianTimeToMerge: &median,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "stats_pull_requests_csv",
+			Method:       "GET",
+			Path:         "/stats/pullRequests?format=csv",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.stats.EXPECT().GetPullRequestStats(gomock.Any(), gomock.Any()).Return(&enteties.PullRequestStats{
+					PullRequestStatsFilter: enteties.PullRequestStatsFilter{
+						GroupBy: enteties.PullRequestStatsGroupByTeam,
+					},
+					StatusCounts: map[enteties.PullRequestStatus]int{},
+					Groups:       []enteties.PullRequestGroupStats{},
+				}, nil)
+			},
+		},
+		{
+			Name:         "stats_pull_requests_error_unknown_group",
+			Method:       "GET",
+			Path:         "/stats/pullRequests?group_by=month",
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "webhooks_add",
+			Method:       "POST",
+			Path:         "/webhooks/add",
+			Body:         `{"team_name": "backend", "url": "https://example.com/hook", "secret": "0123456789abcdef", "event_types": ["pr.created", "reviewer.reassigned"]}`,
+			ExpectedCode: 201,
+			MockSetup: func(m *contractMocks) {
+				m.webhook.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).Return(&enteties.WebhookSubscription{
+					SubscriptionID: 1,
+					TeamName:       "backend",
+					URL:            "https://example.com/hook",
+					Secret:         "0123456789abcdef",
+					EventTypes: []enteties.WebhookEventType{
+						enteties.WebhookEventPRCreated,
+						enteties.WebhookEventReviewerReassigned,
+					},
+					CreatedAt: createdAt,
+				}, nil)
+			},
+		},
+		{
+			Name:         "webhooks_add_error_unknown_event_type",
+			Method:       "POST",
+			Path:         "/webhooks/add",
+			Body:         `{"team_name": "backend", "url": "https://example.com/hook", "secret": "0123456789abcdef", "event_types": ["pr.closed"]}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "webhooks_add_error_team_not_found",
+			Method:       "POST",
+			Path:         "/webhooks/add",
+			Body:         `{"team_name": "unknown", "url": "https://example.com/hook", "secret": "0123456789abcdef", "event_types": ["pr.merged"]}`,
+			ExpectedCode: 404,
+			MockSetup: func(m *contractMocks) {
+				m.webhook.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).Return(nil, service.ErrorTeamNotFound)
+			},
+		},
+		{
+			Name:         "webhooks_list",
+			Method:       "GET",
+			Path:         "/webhooks/list?team_name=backend",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.webhook.EXPECT().GetSubscriptions(gomock.Any(), "backend").Return(&enteties.TeamWebhooks{
+					TeamName: "backend",
+					Subscriptions: []enteties.WebhookSubscription{
+						{
+							SubscriptionID: 1,
+							TeamName:       "backend",
+							URL:            "https://example.com/hook",
+							EventTypes:     []enteties.WebhookEventType{enteties.WebhookEventPRMerged},
+							CreatedAt:      createdAt,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "webhooks_delete",
+			Method:       "POST",
+			Path:         "/webhooks/delete",
+			Body:         `{"subscription_id": 1}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.webhook.EXPECT().DeleteSubscription(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			Name:         "webhooks_deliveries",
+			Method:       "GET",
+			Path:         "/webhooks/deliveries?subscription_id=1&limit=10",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.webhook.EXPECT().GetDeliveries(gomock.Any(), int64(1), 10).Return(&enteties.WebhookDeliveries{
+					SubscriptionID: 1,
+					Deliveries: []enteties.WebhookDelivery{
+						{
+							DeliveryID:     1,
+							SubscriptionID: 1,
+							EventID:        "e1",
+							EventType:      enteties.WebhookEventPRMerged,
+							Attempt:        1,
+							Error:          "connection refused",
+							CreatedAt:      mergedAt,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "webhooks_deliveries_error_not_found",
+			Method:       "GET",
+			Path:         "/webhooks/deliveries?subscription_id=7",
+			ExpectedCode: 404,
+			MockSetup: func(m *contractMocks) {
+				m.webhook.EXPECT().GetDeliveries(gomock.Any(), int64(7), 0).Return(nil, service.ErrorWebhookNotFound)
+			},
+		},
+		{
+			Name:         "roles_grant",
+			Method:       "POST",
+			Path:         "/roles/grant",
+			Body:         `{"user_id": "u1", "role": "team_lead", "team_name": "backend"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.role.EXPECT().GrantRole(gomock.Any(), gomock.Any()).Return(&enteties.UserRole{
+					UserID:    "u1",
+					Role:      enteties.RoleTeamLead,
+					TeamName:  "backend",
+					GrantedBy: "admin",
+					GrantedAt: &createdAt,
+				}, nil)
+			},
+		},
+		{
+			Name:         "roles_grant_error_unknown_role",
+			Method:       "POST",
+			Path:         "/roles/grant",
+			Body:         `{"user_id": "u1", "role": "owner"}`,
+			ExpectedCode: 400,
+		},
+		{
+			Name:         "roles_grant_error_user_not_found",
+			Method:       "POST",
+			Path:         "/roles/grant",
+			Body:         `{"user_id": "u404", "role": "admin"}`,
+			ExpectedCode: 404,
+			MockSetup: func(m *contractMocks) {
+				m.role.EXPECT().GrantRole(gomock.Any(), gomock.Any()).Return(nil, service.ErrorUserNotFound)
+			},
+		},
+		{
+			Name:         "roles_revoke",
+			Method:       "POST",
+			Path:         "/roles/revoke",
+			Body:         `{"user_id": "u1"}`,
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.role.EXPECT().RevokeRole(gomock.Any(), gomock.Any()).Return(&enteties.UserRole{
+					UserID: "u1",
+					Role:   enteties.RoleMember,
+				}, nil)
+			},
+		},
+		{
+			Name:         "roles_list",
+			Method:       "GET",
+			Path:         "/roles/list?team_name=backend",
+			ExpectedCode: 200,
+			MockSetup: func(m *contractMocks) {
+				m.role.EXPECT().ListRoles(gomock.Any(), "backend").Return(&enteties.UserRoles{
+					Roles: []enteties.UserRole{
+						{UserID: "u1", Role: enteties.RoleTeamLead, TeamName: "backend", GrantedBy: "admin", GrantedAt: &createdAt},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "team_add_error_role_forbidden",
+			Method:       "POST",
+			Path:         "/team/add",
+			Body:         `{"team_name": "backend", "members": [{"user_id": "u1", "username": "Alice", "is_active": true}]}`,
+			ExpectedCode: 403,
+			MockSetup: func(m *contractMocks) {
+				m.team.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).Return(nil, service.ErrorForbidden)
+			},
+		},
+		{
+			Name:         "route_outside_spec",
+			Method:       "GET",
+			Path:         "/docs",
+			ExpectedCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			var body io.Reader
+			if tt.Body != "" {
+				body = strings.NewReader(tt.Body)
+			}
+
+			req := httptest.NewRequest(tt.Method, tt.Path, body)
+			if tt.Body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(m)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestOpenAPI_ResponseDrift(t *testing.T) {
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator, err := middleware.OpenAPI(spec, logger, middleware.OpenAPIOptions{
+		ValidateResponses: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Use(validator)
+
+	// ответ без обязательного поля members не соответствует спецификации
+	app.Get("/team/get", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(map[string]string{"team_name": "backend"})
+	})
+
+	req := httptest.NewRequest("GET", "/team/get?team_name=backend", nil)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, 500, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.JSONEq(t, `{
+		"code": "INTERNAL_SERVER",
+		"message": "internal server error"
+	}`, string(body))
+}
+
+func TestOpenAPI_GetSpec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app, _ := newContractApp(t, ctrl)
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var spec struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+	assert.Contains(t, spec.Paths, "/pullRequest/create")
+}
@@ -7,6 +7,7 @@ import (
 	"avito_intern/internal/utils"
 	"errors"
 	"log/slog"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -49,10 +50,14 @@ func (prh *PRHandler) CreatePR(c *fiber.Ctx) error {
 		// обработка ошибок
 		slog.Error("failed create pr", "error", err, "input", pr)
 		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
 		case errors.Is(err, service.ErrorUserNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
 		case errors.Is(err, service.ErrorPRAlreadyExists):
 			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRAlreadyExists)
+		case errors.Is(err, service.ErrorNotEnoughReviewers):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorNotEnoughReviewers)
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
 		}
@@ -63,6 +68,52 @@ func (prh *PRHandler) CreatePR(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(respPR)
 }
 
+func (prh *PRHandler) MarkReady(c *fiber.Ctx) error {
+
+	var readyPR enteties.MarkReadyPullRequest
+
+	// парсинг json request
+	err := c.BodyParser(&readyPR)
+	if err != nil {
+		prh.Logger.Error("failed parse pr to mark ready", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&readyPR)
+	if err != nil {
+		prh.Logger.Error("failed validate pr to mark ready", "error", err, "request", readyPR)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	respPR, err := prh.Service.MarkReady(ctx, &readyPR)
+	if err != nil {
+		slog.Error("failed mark pr ready", "error", err, "input", readyPR)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorPRNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
+		case errors.Is(err, service.ErrorPRIsMerged):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRMerged)
+		case errors.Is(err, service.ErrorPRIsClosed):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRClosed)
+		case errors.Is(err, service.ErrorPRIsArchived):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRArchived)
+		case errors.Is(err, service.ErrorNotEnoughReviewers):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorNotEnoughReviewers)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success PR marked ready", "input", readyPR, "responce", respPR)
+	return c.Status(fiber.StatusOK).JSON(respPR)
+}
+
 func (prh *PRHandler) MergePR(c *fiber.Ctx) error {
 
 	var mergePR enteties.MergePullRequest
@@ -89,8 +140,18 @@ func (prh *PRHandler) MergePR(c *fiber.Ctx) error {
 		// обработка ошибок
 		slog.Error("failed merge pr", "error", err, "input", mergePR)
 		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
 		case errors.Is(err, service.ErrorPRNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
+		case errors.Is(err, service.ErrorPRIsClosed):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRClosed)
+		case errors.Is(err, service.ErrorPRIsArchived):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRArchived)
+		case errors.Is(err, service.ErrorPRIsDraft):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRDraft)
+		case errors.Is(err, service.ErrorNotEnoughApprovals):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorNotEnoughApprovals)
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
 		}
@@ -100,6 +161,90 @@ func (prh *PRHandler) MergePR(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(respPR)
 }
 
+func (prh *PRHandler) ClosePR(c *fiber.Ctx) error {
+
+	var closePR enteties.ClosePullRequest
+
+	// парсинг json request
+	err := c.BodyParser(&closePR)
+	if err != nil {
+		prh.Logger.Error("failed parse pr to close", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&closePR)
+	if err != nil {
+		prh.Logger.Error("failed validate pr to close", "error", err, "request", closePR)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	respPR, err := prh.Service.ClosePR(ctx, &closePR)
+	if err != nil {
+		slog.Error("failed close pr", "error", err, "input", closePR)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorPRNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
+		case errors.Is(err, service.ErrorPRIsMerged):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRMergedClose)
+		case errors.Is(err, service.ErrorPRIsArchived):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRArchived)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success PR closed", "input", closePR, "responce", respPR)
+	return c.Status(fiber.StatusOK).JSON(respPR)
+}
+
+func (prh *PRHandler) ReopenPR(c *fiber.Ctx) error {
+
+	var reopenPR enteties.ReopenPullRequest
+
+	// парсинг json request
+	err := c.BodyParser(&reopenPR)
+	if err != nil {
+		prh.Logger.Error("failed parse pr to reopen", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&reopenPR)
+	if err != nil {
+		prh.Logger.Error("failed validate pr to reopen", "error", err, "request", reopenPR)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	resp, err := prh.Service.ReopenPR(ctx, &reopenPR)
+	if err != nil {
+		slog.Error("failed reopen pr", "error", err, "input", reopenPR)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorPRNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
+		case errors.Is(err, service.ErrorPRIsMerged):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRMergedClose)
+		case errors.Is(err, service.ErrorPRIsArchived):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRArchived)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success PR reopened", "input", reopenPR, "responce", resp)
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
 func (prh *PRHandler) ReassignPR(c *fiber.Ctx) error {
 
 	var reassignPR enteties.ReassignPullRequest
@@ -125,12 +270,18 @@ func (prh *PRHandler) ReassignPR(c *fiber.Ctx) error {
 	if err != nil {
 		slog.Error("failed reassign pr", "error", err, "input", reassignPR)
 		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
 		case errors.Is(err, service.ErrorPRNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
 		case errors.Is(err, service.ErrorUserNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
 		case errors.Is(err, service.ErrorPRIsMerged):
 			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRMerged)
+		case errors.Is(err, service.ErrorPRIsClosed):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRClosed)
+		case errors.Is(err, service.ErrorPRIsArchived):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRArchived)
 		case errors.Is(err, service.ErrorUserNotAssigned):
 			return c.Status(fiber.StatusConflict).JSON(errs.ErrorUserNotAssigned)
 		case errors.Is(err, service.ErrorNoCandidateToReassign):
@@ -140,6 +291,169 @@ func (prh *PRHandler) ReassignPR(c *fiber.Ctx) error {
 		}
 	}
 
-	slog.Info("success PR reassigned", "input", reassignPR, "responce", resp)
+	slog.Info("success PR reassigned", "input", reassignPR, "responce", resp, "actor", service.ActorFromContext(ctx))
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
+
+func (prh *PRHandler) SubmitReview(c *fiber.Ctx) error {
+
+	var reviewPR enteties.ReviewPullRequest
+
+	// парсинг json request
+	err := c.BodyParser(&reviewPR)
+	if err != nil {
+		prh.Logger.Error("failed parse pr review", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&reviewPR)
+	if err != nil {
+		prh.Logger.Error("failed validate pr review", "error", err, "request", reviewPR)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	respPR, err := prh.Service.SubmitReview(ctx, &reviewPR)
+	if err != nil {
+		slog.Error("failed submit pr review", "error", err, "input", reviewPR)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorPRNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
+		case errors.Is(err, service.ErrorUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		case errors.Is(err, service.ErrorPRIsMerged):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRMergedReview)
+		case errors.Is(err, service.ErrorPRIsClosed):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRClosed)
+		case errors.Is(err, service.ErrorPRIsArchived):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorPRArchived)
+		case errors.Is(err, service.ErrorUserNotAssigned):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorUserNotAssigned)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success PR review submitted", "input", reviewPR, "responce", respPR)
+	return c.Status(fiber.StatusOK).JSON(respPR)
+}
+
+func (prh *PRHandler) GetHistory(c *fiber.Ctx) error {
+
+	prID := c.Query("pull_request_id", "")
+	if prID == "" {
+		slog.Error("failed get pr history", "query", prID)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	history, err := prh.Service.GetHistory(ctx, prID)
+	if err != nil {
+		slog.Error("failed get pr history", "error", err, "input", prID)
+		switch {
+		case errors.Is(err, service.ErrorPRNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success got PR history", "input", prID, "events", len(history.Events))
+	return c.Status(fiber.StatusOK).JSON(history)
+}
+
+func (prh *PRHandler) GetPR(c *fiber.Ctx) error {
+
+	prID := c.Query("pull_request_id", "")
+	if prID == "" {
+		slog.Error("failed get pr", "query", prID)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	respPR, err := prh.Service.GetPR(ctx, prID)
+	if err != nil {
+		slog.Error("failed get pr", "error", err, "input", prID)
+		switch {
+		case errors.Is(err, service.ErrorPRNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorPRNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success got PR", "input", prID, "responce", respPR)
+	return c.Status(fiber.StatusOK).JSON(respPR)
+}
+
+func (prh *PRHandler) ListPRs(c *fiber.Ctx) error {
+
+	createdFrom, createdTo, err := parseTimeRangeQuery(c, "created_from", "created_to")
+	if err != nil {
+		slog.Error("failed parse created_at range", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	mergedFrom, mergedTo, err := parseTimeRangeQuery(c, "merged_from", "merged_to")
+	if err != nil {
+		slog.Error("failed parse merged_at range", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	filter := enteties.PullRequestListFilter{
+		Status:      enteties.PullRequestStatus(c.Query("status", "")),
+		AuthorID:    c.Query("author_id", ""),
+		TeamName:    c.Query("team_name", ""),
+		ReviewerID:  c.Query("reviewer_id", ""),
+		Name:        c.Query("name", ""),
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		MergedFrom:  mergedFrom,
+		MergedTo:    mergedTo,
+		Sort:        enteties.SortOrder(c.Query("sort", "")),
+		Cursor:      c.Query("cursor", ""),
+	}
+
+	if raw := c.Query("limit", ""); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			slog.Error("failed parse limit", "error", err, "query", raw)
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+		}
+
+		filter.Limit = limit
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&filter)
+	if err != nil {
+		slog.Error("failed validate pr list filter", "error", err, "input", filter)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	list, err := prh.Service.ListPRs(ctx, &filter)
+	if err != nil {
+		slog.Error("failed list PRs", "error", err, "input", filter)
+		switch {
+		case errors.Is(err, service.ErrorInvalidCursor):
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvalidCursor)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success listed PRs", "input", filter, "count", len(list.PullRequests))
+	return c.Status(fiber.StatusOK).JSON(list)
+}
@@ -4,12 +4,14 @@ import (
 	"avito_intern/internal/enteties"
 	"avito_intern/internal/service"
 	"avito_intern/mocks"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
@@ -129,6 +131,25 @@ func TestHander_CreatePR(t *testing.T) {
 				ms.EXPECT().CreatePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRAlreadyExists)
 			},
 		},
+		{
+			Name: "error_not_enough_reviewers",
+			RequestBody: `{
+			"pull_request_id": "id",
+			"pull_request_name": "name",
+			"author_id": "id1"
+			}`,
+			ExistingPRs:       nil,
+			Teams:             nil,
+			ExpectedReviewers: nil,
+			ExpectedCode:      409,
+			ExpectedBody: `{
+			"code":  "NO_CANDIDATE",
+			"message": "not enough active reviewers in team"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().CreatePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorNotEnoughReviewers)
+			},
+		},
 		{
 			Name: "success_assigned_0_reviewers",
 			RequestBody: `{
@@ -282,6 +303,18 @@ func TestHander_CreatePR(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			Name:         "error_forbidden",
+			RequestBody:  `{"pull_request_id": "pr1", "pull_request_name": "name", "author_id": "u1"}`,
+			ExpectedCode: 403,
+			ExpectedBody: `{
+			"code":  "FORBIDDEN",
+			"message": "user role does not allow this action"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().CreatePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorForbidden)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -312,3 +345,931 @@ func TestHander_CreatePR(t *testing.T) {
 	}
 
 }
+
+func TestHander_GetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockPRService(ctrl)
+	prHandler := NewPRHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Get("/pullRequest/history", prHandler.GetHistory)
+
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		RequestID    string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockPRService)
+	}{
+		{
+			Name:         "error_invalid_pr_id",
+			RequestID:    "",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_pr_not_found",
+			RequestID:    "pr1",
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "pr not found"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().GetHistory(gomock.Any(), "pr1").Return(nil, service.ErrorPRNotFound)
+			},
+		},
+		{
+			Name:         "success_timeline",
+			RequestID:    "pr1",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pull_request_id": "pr1",
+			"events": [
+				{
+					"event_id": 1,
+					"pull_request_id": "pr1",
+					"event_type": "ASSIGN",
+					"new_user_id": "u2",
+					"actor": "u1",
+					"reason": "pr_created",
+					"created_at": "2025-01-01T10:00:00Z"
+				},
+				{
+					"event_id": 2,
+					"pull_request_id": "pr1",
+					"event_type": "REASSIGN",
+					"old_user_id": "u2",
+					"new_user_id": "u3",
+					"actor": "system",
+					"reason": "user_deactivated",
+					"created_at": "2025-01-01T10:00:00Z"
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().GetHistory(gomock.Any(), "pr1").Return(&enteties.PullRequestHistory{
+					PullRequestID: "pr1",
+					Events: []enteties.AssignmentEvent{
+						{
+							EventID:       1,
+							PullRequestID: "pr1",
+							EventType:     enteties.AssignmentEventAssign,
+							NewUserID:     "u2",
+							Actor:         "u1",
+							Reason:        enteties.AssignmentReasonPRCreated,
+							CreatedAt:     createdAt,
+						},
+						{
+							EventID:       2,
+							PullRequestID: "pr1",
+							EventType:     enteties.AssignmentEventReassign,
+							OldUserID:     "u2",
+							NewUserID:     "u3",
+							Actor:         service.ActorSystem,
+							Reason:        enteties.AssignmentReasonUserDeactivated,
+							CreatedAt:     createdAt,
+						},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", "/pullRequest/history?pull_request_id="+tt.RequestID, nil)
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHander_ClosePR(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockPRService(ctrl)
+	prHandler := NewPRHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/pullRequest/close", prHandler.ClosePR)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockPRService)
+	}{
+		{
+			Name:         "error_invalid_input",
+			RequestBody:  `{}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_pr_not_found",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "pr not found"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ClosePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRNotFound)
+			},
+		},
+		{
+			Name:         "error_pr_merged",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "PR_MERGED",
+			"message": "cannot close or reopen merged PR"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ClosePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsMerged)
+			},
+		},
+		{
+			Name:         "error_pr_archived",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "PR_ARCHIVED",
+			"message": "PR is archived after its team was deleted"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ClosePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsArchived)
+			},
+		},
+		{
+			Name:         "success",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pull_request_id":  "pr1",
+			"pull_request_name": "name",
+			"author_id": "u1",
+			"status": "CLOSED",
+			"assigned_reviewers": ["u2"]
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ClosePR(gomock.Any(), &enteties.ClosePullRequest{PullRequestID: "pr1"}).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusClosed,
+					AssignedReviewers: []string{"u2"},
+				}, nil)
+			},
+		},
+		{
+			Name:         "error_forbidden",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 403,
+			ExpectedBody: `{
+			"code":  "FORBIDDEN",
+			"message": "user role does not allow this action"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ClosePR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorForbidden)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/pullRequest/close", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHander_MarkReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockPRService(ctrl)
+	prHandler := NewPRHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/pullRequest/markReady", prHandler.MarkReady)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockPRService)
+	}{
+		{
+			Name:         "error_invalid_input",
+			RequestBody:  `{}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_pr_not_found",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "pr not found"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().MarkReady(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRNotFound)
+			},
+		},
+		{
+			Name:         "error_pr_merged",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "PR_MERGED",
+			"message": "cannot reassign on merged PR"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().MarkReady(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsMerged)
+			},
+		},
+		{
+			Name:         "error_pr_archived",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "PR_ARCHIVED",
+			"message": "PR is archived after its team was deleted"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().MarkReady(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsArchived)
+			},
+		},
+		{
+			Name:         "error_not_enough_reviewers",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "NO_CANDIDATE",
+			"message": "not enough active reviewers in team"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().MarkReady(gomock.Any(), gomock.Any()).Return(nil, service.ErrorNotEnoughReviewers)
+			},
+		},
+		{
+			Name:         "success",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pull_request_id":  "pr1",
+			"pull_request_name": "name",
+			"author_id": "u1",
+			"status": "OPEN",
+			"assigned_reviewers": ["u2", "u3"]
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().MarkReady(gomock.Any(), &enteties.MarkReadyPullRequest{PullRequestID: "pr1"}).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusOpen,
+					AssignedReviewers: []string{"u2", "u3"},
+				}, nil)
+			},
+		},
+		{
+			Name:         "error_forbidden",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 403,
+			ExpectedBody: `{
+			"code":  "FORBIDDEN",
+			"message": "user role does not allow this action"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().MarkReady(gomock.Any(), gomock.Any()).Return(nil, service.ErrorForbidden)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/pullRequest/markReady", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHander_ReopenPR(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockPRService(ctrl)
+	prHandler := NewPRHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/pullRequest/reopen", prHandler.ReopenPR)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockPRService)
+	}{
+		{
+			Name:         "error_invalid_input_format",
+			RequestBody:  "invalid_input_format",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input format"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_pr_merged",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "PR_MERGED",
+			"message": "cannot close or reopen merged PR"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ReopenPR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsMerged)
+			},
+		},
+		{
+			Name:         "success_inactive_reviewer_replaced",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pr": {
+				"pull_request_id":  "pr1",
+				"pull_request_name": "name",
+				"author_id": "u1",
+				"status": "OPEN",
+				"assigned_reviewers": ["u3"]
+			},
+			"reassigned": [{"pull_request_id": "pr1", "old_user_id": "u2", "replaced_by": "u3"}],
+			"without_candidate": []
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ReopenPR(gomock.Any(), &enteties.ReopenPullRequest{PullRequestID: "pr1"}).Return(&enteties.ReopenPullRequestResponce{
+					PR: enteties.PullRequest{
+						PullRequestID:     "pr1",
+						PulRequestName:    "name",
+						AuthorID:          "u1",
+						Status:            enteties.PullRequestStatusOpen,
+						AssignedReviewers: []string{"u3"},
+					},
+					Reassigned:       []enteties.ReviewerReplacement{{PullRequestID: "pr1", OldUserID: "u2", ReplacedBy: "u3"}},
+					WithoutCandidate: []enteties.ReviewerReplacement{},
+				}, nil)
+			},
+		},
+		{
+			Name:         "error_forbidden",
+			RequestBody:  `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 403,
+			ExpectedBody: `{
+			"code":  "FORBIDDEN",
+			"message": "user role does not allow this action"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ReopenPR(gomock.Any(), gomock.Any()).Return(nil, service.ErrorForbidden)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/pullRequest/reopen", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHander_SubmitReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockPRService(ctrl)
+	prHandler := NewPRHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/pullRequest/review", prHandler.SubmitReview)
+
+	reviewedAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockPRService)
+	}{
+		{
+			Name:         "error_invalid_state",
+			RequestBody:  `{"pull_request_id": "pr1", "user_id": "u2", "state": "LGTM"}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_pr_not_found",
+			RequestBody:  `{"pull_request_id": "pr1", "user_id": "u2", "state": "APPROVED"}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "pr not found"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().SubmitReview(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRNotFound)
+			},
+		},
+		{
+			Name:         "error_pr_merged",
+			RequestBody:  `{"pull_request_id": "pr1", "user_id": "u2", "state": "APPROVED"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "PR_MERGED",
+			"message": "cannot review merged PR"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().SubmitReview(gomock.Any(), gomock.Any()).Return(nil, service.ErrorPRIsMerged)
+			},
+		},
+		{
+			Name:         "error_not_assigned",
+			RequestBody:  `{"pull_request_id": "pr1", "user_id": "u4", "state": "APPROVED"}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "NOT_ASSIGNED",
+			"message": "reviewer is not assigned to this PR"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().SubmitReview(gomock.Any(), gomock.Any()).Return(nil, service.ErrorUserNotAssigned)
+			},
+		},
+		{
+			Name:         "success",
+			RequestBody:  `{"pull_request_id": "pr1", "user_id": "u2", "state": "CHANGES_REQUESTED"}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pull_request_id":  "pr1",
+			"pull_request_name": "name",
+			"author_id": "u1",
+			"status": "OPEN",
+			"assigned_reviewers": ["u2", "u3"],
+			"reviews": [
+				{"user_id": "u2", "state": "CHANGES_REQUESTED", "reviewed_at": "2025-01-02T10:00:00Z"},
+				{"user_id": "u3", "state": "PENDING"}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().SubmitReview(gomock.Any(), &enteties.ReviewPullRequest{
+					PullRequestID: "pr1",
+					UserID:        "u2",
+					State:         enteties.ReviewStateChangesRequested,
+				}).Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusOpen,
+					AssignedReviewers: []string{"u2", "u3"},
+					Reviews: []enteties.ReviewerReview{
+						{UserID: "u2", State: enteties.ReviewStateChangesRequested, ReviewedAt: &reviewedAt},
+						{UserID: "u3", State: enteties.ReviewStatePending},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "error_forbidden",
+			RequestBody:  `{"pull_request_id": "pr1", "user_id": "u2", "state": "APPROVED"}`,
+			ExpectedCode: 403,
+			ExpectedBody: `{
+			"code":  "FORBIDDEN",
+			"message": "user role does not allow this action"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().SubmitReview(gomock.Any(), gomock.Any()).Return(nil, service.ErrorForbidden)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/pullRequest/review", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHander_GetPR(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockPRService(ctrl)
+	prHandler := NewPRHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Get("/pullRequest/get", prHandler.GetPR)
+
+	reviewedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		RequestID    string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockPRService)
+	}{
+		{
+			Name:         "error_invalid_pr_id",
+			RequestID:    "",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_pr_not_found",
+			RequestID:    "pr1",
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "pr not found"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().GetPR(gomock.Any(), "pr1").Return(nil, service.ErrorPRNotFound)
+			},
+		},
+		{
+			Name:         "error_internal",
+			RequestID:    "pr1",
+			ExpectedCode: 500,
+			ExpectedBody: `{
+			"code":  "INTERNAL_SERVER",
+			"message": "internal server error"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().GetPR(gomock.Any(), "pr1").Return(nil, errors.New("db error"))
+			},
+		},
+		{
+			Name:         "success",
+			RequestID:    "pr1",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pull_request_id": "pr1",
+			"pull_request_name": "name",
+			"author_id": "u1",
+			"status": "OPEN",
+			"assigned_reviewers": ["u2", "u3"],
+			"reviews": [
+				{"user_id": "u2", "state": "APPROVED", "reviewed_at": "2025-01-01T10:00:00Z"},
+				{"user_id": "u3", "state": "PENDING"}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().GetPR(gomock.Any(), "pr1").Return(&enteties.PullRequest{
+					PullRequestID:     "pr1",
+					PulRequestName:    "name",
+					AuthorID:          "u1",
+					Status:            enteties.PullRequestStatusOpen,
+					AssignedReviewers: []string{"u2", "u3"},
+					Reviews: []enteties.ReviewerReview{
+						{UserID: "u2", State: enteties.ReviewStateApproved, ReviewedAt: &reviewedAt},
+						{UserID: "u3", State: enteties.ReviewStatePending},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", "/pullRequest/get?pull_request_id="+tt.RequestID, nil)
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHander_ListPRs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockPRService(ctrl)
+	prHandler := NewPRHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Get("/pullRequest/list", prHandler.ListPRs)
+
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	createdFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdTo := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		Query        string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockPRService)
+	}{
+		{
+			Name:         "error_invalid_time",
+			Query:        "created_from=yesterday",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_invalid_limit",
+			Query:        "limit=-1",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_unknown_sort",
+			Query:        "sort=random",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_invalid_cursor",
+			Query:        "cursor=broken",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid cursor"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ListPRs(gomock.Any(), &enteties.PullRequestListFilter{
+					Cursor: "broken",
+				}).Return(nil, service.ErrorInvalidCursor)
+			},
+		},
+		{
+			Name:         "success_empty",
+			Query:        "",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pull_requests": []
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ListPRs(gomock.Any(), &enteties.PullRequestListFilter{}).Return(&enteties.PullRequestList{
+					PullRequests: []enteties.PullRequestShort{},
+				}, nil)
+			},
+		},
+		{
+			Name: "success_filters",
+			Query: "status=OPEN&author_id=u1&team_name=backend&reviewer_id=u2&name=Fix" +
+				"&created_from=2025-01-01T00:00:00Z&created_to=2025-02-01T00:00:00Z&sort=asc&limit=10&cursor=c1",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"pull_requests": [
+				{
+					"pull_request_id": "pr1",
+					"pull_request_name": "bugfix",
+					"author_id": "u1",
+					"status": "OPEN",
+					"created_at": "2025-01-01T10:00:00Z"
+				}
+			],
+			"next_cursor": "c2"
+			}`,
+			MockSetup: func(ms *mocks.MockPRService) {
+				ms.EXPECT().ListPRs(gomock.Any(), &enteties.PullRequestListFilter{
+					Status:      enteties.PullRequestStatusOpen,
+					AuthorID:    "u1",
+					TeamName:    "backend",
+					ReviewerID:  "u2",
+					Name:        "Fix",
+					CreatedFrom: &createdFrom,
+					CreatedTo:   &createdTo,
+					Sort:        enteties.SortOrderAsc,
+					Limit:       10,
+					Cursor:      "c1",
+				}).Return(&enteties.PullRequestList{
+					PullRequests: []enteties.PullRequestShort{
+						{
+							PullRequestID:  "pr1",
+							PulRequestName: "bugfix",
+							AuthorID:       "u1",
+							Status:         enteties.PullRequestStatusOpen,
+							CreatedAt:      &createdAt,
+						},
+					},
+					NextCursor: "c2",
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", "/pullRequest/list?"+tt.Query, nil)
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"avito_intern/api/errs"
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/service"
+	"avito_intern/internal/utils"
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler struct {
+	Logger  *slog.Logger
+	Service service.RoleService
+}
+
+func NewRoleHandler(log *slog.Logger, service service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		Logger:  log,
+		Service: service,
+	}
+}
+
+func (rh *RoleHandler) GrantRole(c *fiber.Ctx) error {
+
+	var request enteties.GrantRole
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		rh.Logger.Error("failed parse role to grant", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры (team_name обязателен только для team_lead)
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		rh.Logger.Error("failed validate role to grant", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	role, err := rh.Service.GrantRole(ctx, &request)
+	if err != nil {
+		slog.Error("failed grant role", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		case errors.Is(err, service.ErrorUserNotInTeam):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotInTeam)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success role granted", "input", request, "responce", role, "actor", service.ActorFromContext(ctx))
+	return c.Status(fiber.StatusOK).JSON(role)
+}
+
+func (rh *RoleHandler) RevokeRole(c *fiber.Ctx) error {
+
+	var request enteties.RevokeRole
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		rh.Logger.Error("failed parse role to revoke", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		rh.Logger.Error("failed validate role to revoke", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	role, err := rh.Service.RevokeRole(ctx, &request)
+	if err != nil {
+		slog.Error("failed revoke role", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success role revoked", "input", request, "actor", service.ActorFromContext(ctx))
+	return c.Status(fiber.StatusOK).JSON(role)
+}
+
+func (rh *RoleHandler) ListRoles(c *fiber.Ctx) error {
+
+	teamName := c.Query("team_name", "")
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	roles, err := rh.Service.ListRoles(ctx, teamName)
+	if err != nil {
+		slog.Error("failed list roles", "error", err, "input", teamName)
+		switch {
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success listed roles", "input", teamName, "count", len(roles.Roles))
+	return c.Status(fiber.StatusOK).JSON(roles)
+}
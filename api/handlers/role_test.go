@@ -0,0 +1,362 @@
+package handlers
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/service"
+	"avito_intern/mocks"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GrantRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockRoleService(ctrl)
+	roleHandler := NewRoleHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/roles/grant", roleHandler.GrantRole)
+
+	grantedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockRoleService)
+	}{
+		{
+			Name:         "error_invalid_input_format",
+			RequestBody:  "invaid_input_format",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input format"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_team_lead_without_team",
+			RequestBody:  `{"user_id": "u1", "role": "team_lead"}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_admin_with_team",
+			RequestBody:  `{"user_id": "u1", "role": "admin", "team_name": "backend"}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_unknown_role",
+			RequestBody:  `{"user_id": "u1", "role": "member"}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_user_not_found",
+			RequestBody:  `{"user_id": "u404", "role": "admin"}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "user not found"
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().GrantRole(gomock.Any(), &enteties.GrantRole{
+					UserID: "u404",
+					Role:   enteties.RoleAdmin,
+				}).Return(nil, service.ErrorUserNotFound)
+			},
+		},
+		{
+			Name:         "error_team_not_found",
+			RequestBody:  `{"user_id": "u1", "role": "team_lead", "team_name": "unknown"}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "team not found"
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().GrantRole(gomock.Any(), &enteties.GrantRole{
+					UserID:   "u1",
+					Role:     enteties.RoleTeamLead,
+					TeamName: "unknown",
+				}).Return(nil, service.ErrorTeamNotFound)
+			},
+		},
+		{
+			Name:         "error_user_not_in_team",
+			RequestBody:  `{"user_id": "f1", "role": "team_lead", "team_name": "backend"}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "user is not a member of the team"
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().GrantRole(gomock.Any(), &enteties.GrantRole{
+					UserID:   "f1",
+					Role:     enteties.RoleTeamLead,
+					TeamName: "backend",
+				}).Return(nil, service.ErrorUserNotInTeam)
+			},
+		},
+		{
+			Name:         "success",
+			RequestBody:  `{"user_id": "u1", "role": "team_lead", "team_name": "backend"}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"user_id": "u1",
+			"role": "team_lead",
+			"team_name": "backend",
+			"granted_by": "admin",
+			"granted_at": "2025-01-01T10:00:00Z"
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().GrantRole(gomock.Any(), &enteties.GrantRole{
+					UserID:   "u1",
+					Role:     enteties.RoleTeamLead,
+					TeamName: "backend",
+				}).Return(&enteties.UserRole{
+					UserID:    "u1",
+					Role:      enteties.RoleTeamLead,
+					TeamName:  "backend",
+					GrantedBy: "admin",
+					GrantedAt: &grantedAt,
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/roles/grant", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHandler_RevokeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockRoleService(ctrl)
+	roleHandler := NewRoleHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/roles/revoke", roleHandler.RevokeRole)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockRoleService)
+	}{
+		{
+			Name:         "error_invalid_input",
+			RequestBody:  `{"user_id": ""}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_user_not_found",
+			RequestBody:  `{"user_id": "u404"}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "user not found"
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().RevokeRole(gomock.Any(), &enteties.RevokeRole{UserID: "u404"}).
+					Return(nil, service.ErrorUserNotFound)
+			},
+		},
+		{
+			Name:         "success",
+			RequestBody:  `{"user_id": "u1"}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"user_id": "u1",
+			"role": "member"
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().RevokeRole(gomock.Any(), &enteties.RevokeRole{UserID: "u1"}).
+					Return(&enteties.UserRole{UserID: "u1", Role: enteties.RoleMember}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/roles/revoke", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHandler_ListRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockRoleService(ctrl)
+	roleHandler := NewRoleHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Get("/roles/list", roleHandler.ListRoles)
+
+	tests := []struct {
+		Name         string
+		Query        string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockRoleService)
+	}{
+		{
+			Name:         "error_team_not_found",
+			Query:        "?team_name=unknown",
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "team not found"
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().ListRoles(gomock.Any(), "unknown").Return(nil, service.ErrorTeamNotFound)
+			},
+		},
+		{
+			Name:         "success_all",
+			Query:        "",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"roles": [
+				{"user_id": "u1", "role": "admin"},
+				{"user_id": "u2", "role": "team_lead", "team_name": "backend"}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().ListRoles(gomock.Any(), "").Return(&enteties.UserRoles{
+					Roles: []enteties.UserRole{
+						{UserID: "u1", Role: enteties.RoleAdmin},
+						{UserID: "u2", Role: enteties.RoleTeamLead, TeamName: "backend"},
+					},
+				}, nil)
+			},
+		},
+		{
+			Name:         "success_team_empty",
+			Query:        "?team_name=backend",
+			ExpectedCode: 200,
+			ExpectedBody: `{"roles": []}`,
+			MockSetup: func(ms *mocks.MockRoleService) {
+				ms.EXPECT().ListRoles(gomock.Any(), "backend").Return(&enteties.UserRoles{
+					Roles: []enteties.UserRole{},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", "/roles/list"+tt.Query, nil)
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
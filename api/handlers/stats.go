@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"avito_intern/api/errs"
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/service"
+	"avito_intern/internal/utils"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatsHandler struct {
+	Logger  *slog.Logger
+	Service service.StatsService
+}
+
+func NewStatsHandler(log *slog.Logger, service service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		Logger:  log,
+		Service: service,
+	}
+}
+
+func (sh *StatsHandler) GetReviewerStats(c *fiber.Ctx) error {
+
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		slog.Error("failed parse reviewer stats time range", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	filter := enteties.ReviewerStatsFilter{
+		TeamName: c.Query("team_name", ""),
+		From:     from,
+		To:       to,
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	stats, err := sh.Service.GetReviewerStats(ctx, &filter)
+	if err != nil {
+		slog.Error("failed get reviewer stats", "error", err, "input", filter)
+		switch {
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("Success got reviewer stats", "input", filter)
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
+
+func (sh *StatsHandler) GetPullRequestStats(c *fiber.Ctx) error {
+
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		slog.Error("failed parse pull request stats time range", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	filter := enteties.PullRequestStatsFilter{
+		GroupBy:  enteties.PullRequestStatsGroupBy(c.Query("group_by", string(enteties.PullRequestStatsGroupByTeam))),
+		TeamName: c.Query("team_name", ""),
+		From:     from,
+		To:       to,
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&filter)
+	if err != nil {
+		slog.Error("failed validate pull request stats filter", "error", err, "input", filter)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		slog.Error("unknown pull request stats format", "format", format)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	stats, err := sh.Service.GetPullRequestStats(ctx, &filter)
+	if err != nil {
+		slog.Error("failed get pull request stats", "error", err, "input", filter)
+		switch {
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("Success got pull request stats", "input", filter, "format", format)
+
+	if format == "csv" {
+		report, err := pullRequestStatsCSV(stats)
+		if err != nil {
+			slog.Error("failed write pull request stats csv", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="pull_request_stats.csv"`)
+		return c.Status(fiber.StatusOK).Send(report)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
+
+// вспомогательная функция формирует CSV отчет: строка на группу, по колонке на каждый статус
+func pullRequestStatsCSV(stats *enteties.PullRequestStats) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{string(stats.GroupBy), "total"}
+	for _, status := range enteties.PullRequestStatuses {
+		header = append(header, string(status))
+	}
+	header = append(header, "median_time_to_merge_seconds", "p90_time_to_merge_seconds")
+
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, group := range stats.Groups {
+		record := []string{csvCell(group.Group), strconv.Itoa(group.Total)}
+		for _, status := range enteties.PullRequestStatuses {
+			record = append(record, strconv.Itoa(group.StatusCounts[status]))
+		}
+		record = append(record, formatSeconds(group.MedianTimeToMerge), formatSeconds(group.P90TimeToMerge))
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// вспомогательная функция экранирует значение, которое табличный редактор выполнил бы как формулу
+// (начинается с =, +, -, @, табуляции или перевода каретки), добавляя в начало апостроф
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// вспомогательная функция выводит время в секундах, пустая строка если значения нет
+func formatSeconds(seconds *float64) string {
+	if seconds == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*seconds, 'f', 0, 64)
+}
+
+// вспомогательная функция разбирает необязательные query параметры from и to в формате RFC3339
+func parseTimeRange(c *fiber.Ctx) (*time.Time, *time.Time, error) {
+	return parseTimeRangeQuery(c, "from", "to")
+}
+
+// вспомогательная функция разбирает необязательную пару query параметров с границами
+// полуинтервала в формате RFC3339
+func parseTimeRangeQuery(c *fiber.Ctx, fromKey, toKey string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+
+	if value := c.Query(fromKey, ""); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &t
+	}
+
+	if value := c.Query(toKey, ""); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		to = &t
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, errors.New(fromKey + " must be before " + toKey)
+	}
+
+	return from, to, nil
+}
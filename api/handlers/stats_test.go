@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/service"
+	"avito_intern/mocks"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetReviewerStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockStatsService(ctrl)
+	statsHandler := NewStatsHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Get("/stats/reviewers", statsHandler.GetReviewerStats)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		Query        string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockStatsService)
+	}{
+		{
+			Name:         "error_invalid_time_format",
+			Query:        "?from=yesterday",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_from_after_to",
+			Query:        "?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_team_not_found",
+			Query:        "?team_name=unknown",
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "team not found"
+			}`,
+			MockSetup: func(ms *mocks.MockStatsService) {
+				ms.EXPECT().GetReviewerStats(gomock.Any(), &enteties.ReviewerStatsFilter{
+					TeamName: "unknown",
+				}).Return(nil, service.ErrorTeamNotFound)
+			},
+		},
+		{
+			Name:         "success_with_filters",
+			Query:        "?team_name=backend&from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"team_name": "backend",
+			"from": "2025-01-01T00:00:00Z",
+			"to": "2025-02-01T00:00:00Z",
+			"reviewers": [
+				{
+					"user_id": "u1",
+					"username": "name1",
+					"team_name": "backend",
+					"total_assignments": 5,
+					"open": 2,
+					"merged": 2,
+					"reassigned_away": 1
+				},
+				{
+					"user_id": "u2",
+					"username": "name2",
+					"team_name": "backend",
+					"total_assignments": 0,
+					"open": 0,
+					"merged": 0,
+					"reassigned_away": 0
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockStatsService) {
+				filter := enteties.ReviewerStatsFilter{
+					TeamName: "backend",
+					From:     &from,
+					To:       &to,
+				}
+
+				ms.EXPECT().GetReviewerStats(gomock.Any(), &filter).Return(&enteties.ReviewerStats{
+					ReviewerStatsFilter: filter,
+					Reviewers: []enteties.ReviewerStat{
+						{
+							UserID:           "u1",
+							UserName:         "name1",
+							TeamName:         "backend",
+							TotalAssignments: 5,
+							OpenReviews:      2,
+							MergedReviews:    2,
+							ReassignedAway:   1,
+						},
+						{
+							UserID:   "u2",
+							UserName: "name2",
+							TeamName: "backend",
+						},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", "/stats/reviewers"+tt.Query, nil)
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHandler_GetPullRequestStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockStatsService(ctrl)
+	statsHandler := NewStatsHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Get("/stats/pullRequests", statsHandler.GetPullRequestStats)
+
+	median := 3600.0
+	p90 := 7200.0
+
+	stats := &enteties.PullRequestStats{
+		PullRequestStatsFilter: enteties.PullRequestStatsFilter{
+			GroupBy: enteties.PullRequestStatsGroupByAuthor,
+		},
+		StatusCounts: map[enteties.PullRequestStatus]int{
+			enteties.PullRequestStatusOpen:     1,
+			enteties.PullRequestStatusMerged:   2,
+			enteties.PullRequestStatusArchived: 0,
+		},
+		Groups: []enteties.PullRequestGroupStats{
+			{
+				Group: "u1",
+				Total: 2,
+				StatusCounts: map[enteties.PullRequestStatus]int{
+					enteties.PullRequestStatusOpen:     0,
+					enteties.PullRequestStatusMerged:   2,
+					enteties.PullRequestStatusArchived: 0,
+				},
+				MedianTimeToMerge: &median,
+				P90TimeToMerge:    &p90,
+			},
+			{
+				Group: "u2",
+				Total: 1,
+				StatusCounts: map[enteties.PullRequestStatus]int{
+					enteties.PullRequestStatusOpen:     1,
+					enteties.PullRequestStatusMerged:   0,
+					enteties.PullRequestStatusArchived: 0,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		Name         string
+		Query        string
+		ExpectedCode int
+		ExpectedBody string
+		IsCSV        bool
+		MockSetup    func(ms *mocks.MockStatsService)
+	}{
+		{
+			Name:         "error_invalid_group_by",
+			Query:        "?group_by=month",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_invalid_format",
+			Query:        "?format=xml",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "success_json",
+			Query:        "?group_by=author",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"group_by": "author",
+			"status_counts": {"OPEN": 1, "MERGED": 2, "ARCHIVED": 0},
+			"groups": [
+				{
+					"group": "u1",
+					"total": 2,
+					"status_counts": {"OPEN": 0, "MERGED": 2, "ARCHIVED": 0},
+					"median_time_to_merge_seconds": 3600,
+					"p90_time_to_merge_seconds": 7200
+				},
+				{
+					"group": "u2",
+					"total": 1,
+					"status_counts": {"OPEN": 1, "MERGED": 0, "ARCHIVED": 0},
+					"median_time_to_merge_seconds": null,
+					"p90_time_to_merge_seconds": null
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockStatsService) {
+				ms.EXPECT().GetPullRequestStats(gomock.Any(), &enteties.PullRequestStatsFilter{
+					GroupBy: enteties.PullRequestStatsGroupByAuthor,
+				}).Return(stats, nil)
+			},
+		},
+		{
+			Name:         "success_csv",
+			Query:        "?group_by=author&format=csv",
+			ExpectedCode: 200,
+			ExpectedBody: "author,total,OPEN,MERGED,CLOSED,ARCHIVED,median_time_to_merge_seconds,p90_time_to_merge_seconds\n" +
+				"u1,2,0,2,0,0,3600,7200\n" +
+				"u2,1,1,0,0,0,,\n",
+			IsCSV: true,
+			MockSetup: func(ms *mocks.MockStatsService) {
+				ms.EXPECT().GetPullRequestStats(gomock.Any(), &enteties.PullRequestStatsFilter{
+					GroupBy: enteties.PullRequestStatsGroupByAuthor,
+				}).Return(stats, nil)
+			},
+		},
+		{
+			Name:         "success_csv_escapes_formulas",
+			Query:        "?group_by=team&format=csv",
+			ExpectedCode: 200,
+			ExpectedBody: "team,total,OPEN,MERGED,CLOSED,ARCHIVED,median_time_to_merge_seconds,p90_time_to_merge_seconds\n" +
+				"'=1+2,0,0,0,0,0,,\n" +
+				"'+1,0,0,0,0,0,,\n" +
+				"'-1,0,0,0,0,0,,\n" +
+				"'@SUM(A1),0,0,0,0,0,,\n" +
+				"'\tcmd,0,0,0,0,0,,\n" +
+				"\"'\rcmd\",0,0,0,0,0,,\n" +
+				"back=end,0,0,0,0,0,,\n",
+			IsCSV: true,
+			MockSetup: func(ms *mocks.MockStatsService) {
+				// названия команд задают пользователи, ячейки не должны выполняться как формулы
+				teams := &enteties.PullRequestStats{
+					PullRequestStatsFilter: enteties.PullRequestStatsFilter{
+						GroupBy: enteties.PullRequestStatsGroupByTeam,
+					},
+				}
+				for _, name := range []string{"=1+2", "+1", "-1", "@SUM(A1)", "\tcmd", "\rcmd", "back=end"} {
+					teams.Groups = append(teams.Groups, enteties.PullRequestGroupStats{Group: name})
+				}
+
+				ms.EXPECT().GetPullRequestStats(gomock.Any(), &enteties.PullRequestStatsFilter{
+					GroupBy: enteties.PullRequestStatsGroupByTeam,
+				}).Return(teams, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", "/stats/pullRequests"+tt.Query, nil)
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.IsCSV {
+				assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+				assert.Equal(t, tt.ExpectedBody, string(body))
+				return
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
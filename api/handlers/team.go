@@ -57,6 +57,8 @@ func (th *TeamHandler) CreateTeam(c *fiber.Ctx) error {
 	if err != nil {
 		slog.Error("failed create team", "error", err, "input", team)
 		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
 		case errors.Is(err, service.ErrorUserAlreadyExists):
 			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorUserAlreadyExists)
 		case errors.Is(err, service.ErrorUserAlreadyExistsByUserName):
@@ -87,6 +89,8 @@ func (th *TeamHandler) GetTeam(c *fiber.Ctx) error {
 	if err != nil {
 		slog.Error("failed get team", "error", err, "input", teamName)
 		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
 		case errors.Is(err, service.ErrorTeamNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
 		default:
@@ -97,3 +101,281 @@ func (th *TeamHandler) GetTeam(c *fiber.Ctx) error {
 	slog.Info("Success got team", "input", teamName, "responce", team)
 	return c.Status(fiber.StatusOK).JSON(team)
 }
+
+func (th *TeamHandler) GetTeamSettings(c *fiber.Ctx) error {
+
+	teamName := c.Query("team_name", "")
+	if teamName == "" {
+		slog.Error("failed get team settings", "query", teamName)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	settings, err := th.Service.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		slog.Error("failed get team settings", "error", err, "input", teamName)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("Success got team settings", "input", teamName, "responce", settings)
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
+
+func (th *TeamHandler) UpdateTeamSettings(c *fiber.Ctx) error {
+
+	var request enteties.UpdateTeamSettings
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		th.Logger.Error("failed parse team settings", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		th.Logger.Error("failed validate team settings", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	settings, err := th.Service.UpdateTeamSettings(ctx, &request)
+	if err != nil {
+		slog.Error("failed update team settings", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorInvalidTeamSettings):
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvalidTeamSettings)
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success team settings updated", "input", request, "responce", settings)
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
+
+func (th *TeamHandler) AddMembers(c *fiber.Ctx) error {
+
+	var request enteties.AddTeamMembers
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		th.Logger.Error("failed parse members to add", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		th.Logger.Error("failed validate members to add", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// провалидируем каждого пользователя
+	for _, tm := range request.Members {
+		err = utils.ValidateStruct(&tm)
+		if err != nil {
+			th.Logger.Error("failed validate members to add", "error", err, "request", request)
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+		}
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	respTeam, err := th.Service.AddMembers(ctx, &request)
+	if err != nil {
+		slog.Error("failed add members", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		case errors.Is(err, service.ErrorUserAlreadyExists):
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorUserAlreadyExists)
+		case errors.Is(err, service.ErrorUserAlreadyExistsByUserName):
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorUserAlreadyExistsByUserName)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success members added", "input", request, "responce", respTeam)
+	return c.Status(fiber.StatusOK).JSON(respTeam)
+}
+
+func (th *TeamHandler) RemoveMembers(c *fiber.Ctx) error {
+
+	var request enteties.RemoveTeamMembers
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		th.Logger.Error("failed parse members to remove", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		th.Logger.Error("failed validate members to remove", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	resp, err := th.Service.RemoveMembers(ctx, &request)
+	if err != nil {
+		slog.Error("failed remove members", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		case errors.Is(err, service.ErrorUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		case errors.Is(err, service.ErrorUserNotInTeam):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotInTeam)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success members removed", "input", request, "responce", resp)
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (th *TeamHandler) MoveMember(c *fiber.Ctx) error {
+
+	var request enteties.MoveTeamMember
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		th.Logger.Error("failed parse member to move", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		th.Logger.Error("failed validate member to move", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	resp, err := th.Service.MoveMember(ctx, &request)
+	if err != nil {
+		slog.Error("failed move member", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		case errors.Is(err, service.ErrorUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success member moved", "input", request, "responce", resp)
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (th *TeamHandler) DeleteTeam(c *fiber.Ctx) error {
+
+	var request enteties.DeleteTeam
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		th.Logger.Error("failed parse team to delete", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		th.Logger.Error("failed validate team to delete", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	resp, err := th.Service.DeleteTeam(ctx, &request)
+	if err != nil {
+		slog.Error("failed delete team", "error", err, "input", request)
+
+		// в ответе перечисляются pull request, которые мешают удалению
+		var openPRsErr *service.TeamHasOpenPRsError
+		switch {
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		case errors.As(err, &openPRsErr):
+			return c.Status(fiber.StatusConflict).JSON(errs.NewTeamHasOpenPRsResponce(openPRsErr.PRs))
+		case errors.Is(err, service.ErrorTeamHasOpenPRs):
+			return c.Status(fiber.StatusConflict).JSON(errs.ErrorTeamHasOpenPRs)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success team deleted", "input", request, "responce", resp)
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (th *TeamHandler) DeactivateUsers(c *fiber.Ctx) error {
+
+	var request enteties.DeactivateUsers
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		th.Logger.Error("failed parse users to deactivate", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		th.Logger.Error("failed validate users to deactivate", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	resp, err := th.Service.DeactivateUsers(ctx, &request)
+	if err != nil {
+		slog.Error("failed deactivate users", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success users deactivated", "input", request, "responce", resp)
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -100,6 +100,31 @@ func TestHanlder_CreateTeam(t *testing.T) {
 				ms.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).Return(nil, service.ErrorTeamExists)
 			},
 		},
+		{
+			Name: "error_role_forbidden",
+			RequestBody: `{
+			"team_name": "name",
+			"members": [
+				{
+					"user_id": "id",
+					"username": "name",
+					"is_active": true
+				}
+			]
+			}`,
+			ExistedTeams:     []string{},
+			ExistedUsersID:   []string{},
+			ExistedUserNames: []string{},
+			ExpectedCode:     403,
+			ExpectedBody: `{
+			"code":  "FORBIDDEN",
+			"message": "user role does not allow this action"
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).Return(nil, service.ErrorForbidden)
+			},
+		},
 		{
 			Name: "error_user_already_exist",
 			RequestBody: `{
@@ -337,3 +362,640 @@ func TestHanlder_GetTeam(t *testing.T) {
 		})
 	}
 }
+
+func TestHanlder_UpdateTeamSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockTeamService(ctrl)
+	teamHandler := NewTeamHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/team/settings", teamHandler.UpdateTeamSettings)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockTeamService)
+	}{
+		{
+			Name:         "error_invalid_input_format",
+			RequestBody:  "invaid_input_format",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input format"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_invalid_input",
+			RequestBody: `{
+			"team_name": "name",
+			"min_reviewers": 1
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_min_greater_than_max",
+			RequestBody: `{
+			"team_name": "name",
+			"min_reviewers": 3,
+			"max_reviewers": 1
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "min_reviewers must not exceed max_reviewers"
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().UpdateTeamSettings(gomock.Any(), gomock.Any()).Return(nil, service.ErrorInvalidTeamSettings)
+			},
+		},
+		{
+			Name: "error_team_not_found",
+			RequestBody: `{
+			"team_name": "name",
+			"min_reviewers": 0,
+			"max_reviewers": 2
+			}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "team not found"
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().UpdateTeamSettings(gomock.Any(), gomock.Any()).Return(nil, service.ErrorTeamNotFound)
+			},
+		},
+		{
+			Name: "success_updated",
+			RequestBody: `{
+			"team_name": "name",
+			"min_reviewers": 1,
+			"max_reviewers": 3
+			}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"team_name": "name",
+			"min_reviewers": 1,
+			"max_reviewers": 3
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().UpdateTeamSettings(gomock.Any(), gomock.Any()).Return(&enteties.TeamSettings{
+					TeamName:     "name",
+					MinReviewers: 1,
+					MaxReviewers: 3,
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/team/settings", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHanlder_MoveMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockTeamService(ctrl)
+	teamHandler := NewTeamHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/team/moveMember", teamHandler.MoveMember)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockTeamService)
+	}{
+		{
+			Name: "error_invalid_input",
+			RequestBody: `{
+			"user_id": "u1"
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_team_not_found",
+			RequestBody: `{
+			"user_id": "u1",
+			"team_name": "name2"
+			}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "team not found"
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().MoveMember(gomock.Any(), gomock.Any()).Return(nil, service.ErrorTeamNotFound)
+			},
+		},
+		{
+			Name: "success_moved",
+			RequestBody: `{
+			"user_id": "u1",
+			"team_name": "name2"
+			}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"user": {
+				"user_id": "u1",
+				"username": "name1",
+				"team_name": "name2",
+				"is_active": true
+			},
+			"reassigned": [
+				{
+					"pull_request_id": "pr1",
+					"old_user_id": "u1",
+					"replaced_by": "u3"
+				},
+				{
+					"pull_request_id": "pr2",
+					"old_user_id": "u1",
+					"replaced_by": ""
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().MoveMember(gomock.Any(), &enteties.MoveTeamMember{
+					UserID:   "u1",
+					TeamName: "name2",
+				}).Return(&enteties.MoveTeamMemberResponce{
+					User: enteties.User{
+						UserID:   "u1",
+						UserName: "name1",
+						TeamName: "name2",
+						IsActive: true,
+					},
+					Reassigned: []enteties.ReviewerReplacement{
+						{PullRequestID: "pr1", OldUserID: "u1", ReplacedBy: "u3"},
+						{PullRequestID: "pr2", OldUserID: "u1", ReplacedBy: ""},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/team/moveMember", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHanlder_DeleteTeam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockTeamService(ctrl)
+	teamHandler := NewTeamHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/team/delete", teamHandler.DeleteTeam)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockTeamService)
+	}{
+		{
+			Name: "error_invalid_mode",
+			RequestBody: `{
+			"team_name": "name",
+			"mode": "drop"
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_team_has_open_prs",
+			RequestBody: `{
+			"team_name": "name"
+			}`,
+			ExpectedCode: 409,
+			ExpectedBody: `{
+			"code":  "TEAM_HAS_OPEN_PRS",
+			"message": "team members have open pull requests or reviews",
+			"pull_requests": ["pr1", "pr2"]
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().DeleteTeam(gomock.Any(), &enteties.DeleteTeam{TeamName: "name"}).Return(nil,
+					fmt.Errorf("[TeamService | DeleteTeam]: %w", &service.TeamHasOpenPRsError{PRs: []string{"pr1", "pr2"}}))
+			},
+		},
+		{
+			Name: "success_deleted_archive",
+			RequestBody: `{
+			"team_name": "name",
+			"mode": "archive"
+			}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"team_name": "name",
+			"mode": "archive",
+			"removed_members": ["u1", "u2"],
+			"archived_pull_requests": ["pr1"],
+			"reassigned": [
+				{
+					"pull_request_id": "pr2",
+					"old_user_id": "u1",
+					"replaced_by": ""
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().DeleteTeam(gomock.Any(), &enteties.DeleteTeam{
+					TeamName: "name",
+					Mode:     enteties.DeleteTeamModeArchive,
+				}).Return(&enteties.DeleteTeamResponce{
+					TeamName:       "name",
+					Mode:           enteties.DeleteTeamModeArchive,
+					RemovedMembers: []string{"u1", "u2"},
+					ArchivedPRs:    []string{"pr1"},
+					Reassigned: []enteties.ReviewerReplacement{
+						{PullRequestID: "pr2", OldUserID: "u1", ReplacedBy: ""},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/team/delete", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHanlder_DeactivateUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockTeamService(ctrl)
+	teamHandler := NewTeamHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/team/deactivateUsers", teamHandler.DeactivateUsers)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockTeamService)
+	}{
+		{
+			Name: "error_empty_users",
+			RequestBody: `{
+			"user_ids": []
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_duplicate_users",
+			RequestBody: `{
+			"user_ids": ["u1", "u2", "u1"]
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_user_not_found",
+			RequestBody: `{
+			"user_ids": ["u1", "u9"]
+			}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "user not found"
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().DeactivateUsers(gomock.Any(), gomock.Any()).Return(nil, service.ErrorUserNotFound)
+			},
+		},
+		{
+			Name: "success_deactivated",
+			RequestBody: `{
+			"user_ids": ["u1", "u2"]
+			}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"deactivated_users": ["u1", "u2"],
+			"reassigned": [
+				{
+					"pull_request_id": "pr1",
+					"old_user_id": "u1",
+					"replaced_by": "u3"
+				}
+			],
+			"without_candidate": [
+				{
+					"pull_request_id": "pr2",
+					"old_user_id": "u2",
+					"replaced_by": ""
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().DeactivateUsers(gomock.Any(), &enteties.DeactivateUsers{
+					UsersID: []string{"u1", "u2"},
+				}).Return(&enteties.DeactivateUsersResponce{
+					DeactivatedUsers: []string{"u1", "u2"},
+					Reassigned: []enteties.ReviewerReplacement{
+						{PullRequestID: "pr1", OldUserID: "u1", ReplacedBy: "u3"},
+					},
+					WithoutCandidate: []enteties.ReviewerReplacement{
+						{PullRequestID: "pr2", OldUserID: "u2", ReplacedBy: ""},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/team/deactivateUsers", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHanlder_RemoveMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockTeamService(ctrl)
+	teamHandler := NewTeamHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/team/removeMembers", teamHandler.RemoveMembers)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockTeamService)
+	}{
+		{
+			Name: "error_empty_users",
+			RequestBody: `{
+			"team_name": "backend",
+			"user_ids": []
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_duplicate_users",
+			RequestBody: `{
+			"team_name": "backend",
+			"user_ids": ["u2", "u2"]
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_user_not_in_team",
+			RequestBody: `{
+			"team_name": "backend",
+			"user_ids": ["u2", "f1"]
+			}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "user is not a member of the team"
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().RemoveMembers(gomock.Any(), gomock.Any()).Return(nil, service.ErrorUserNotInTeam)
+			},
+		},
+		{
+			Name: "success_removed",
+			RequestBody: `{
+			"team_name": "backend",
+			"user_ids": ["u2"]
+			}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"team": {
+				"team_name": "backend",
+				"members": [
+					{
+						"user_id": "u1",
+						"username": "name1",
+						"is_active": true
+					}
+				]
+			},
+			"reassigned": [
+				{
+					"pull_request_id": "pr1",
+					"old_user_id": "u2",
+					"replaced_by": ""
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockTeamService) {
+
+				ms.EXPECT().RemoveMembers(gomock.Any(), &enteties.RemoveTeamMembers{
+					TeamName: "backend",
+					UsersID:  []string{"u2"},
+				}).Return(&enteties.RemoveTeamMembersResponce{
+					Team: enteties.Team{
+						TeamName: "backend",
+						Members: []enteties.TeamMember{
+							{UserID: "u1", UserName: "name1", IsActive: true},
+						},
+					},
+					Reassigned: []enteties.ReviewerReplacement{
+						{PullRequestID: "pr1", OldUserID: "u2", ReplacedBy: ""},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/team/removeMembers", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"avito_intern/internal/utils"
 	"errors"
 	"log/slog"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -43,20 +44,24 @@ func (uh *UserHandler) SetIsActive(c *fiber.Ctx) error {
 	// используем контекст от fiber для всех операций (он уже правильно настроен)
 	ctx := c.Context()
 
-	userResp, err := uh.Service.SetIsActive(ctx, request.UserID, request.IsActive)
+	userResp, err := uh.Service.SetIsActive(ctx, request.UserID, *request.IsActive)
 	if err != nil {
 
 		slog.Error("failed set status", "error", err, "input", request)
 		switch {
+		case errors.Is(err, service.ErrorForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(errs.ErrorRoleForbidden)
 		case errors.Is(err, service.ErrorUserNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		case errors.Is(err, service.ErrorInvalidCursor):
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvalidCursor)
 
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
 		}
 	}
 
-	slog.Info("success status set", "input", request, "responce", userResp)
+	slog.Info("success status set", "input", request, "responce", userResp, "actor", service.ActorFromContext(ctx))
 	return c.Status(fiber.StatusOK).JSON(userResp)
 
 }
@@ -69,17 +74,64 @@ func (uh *UserHandler) GetReview(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
 	}
 
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		slog.Error("failed parse reviews time range", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	filter := enteties.UserReviewsFilter{
+		UserID:   userID,
+		Status:   enteties.PullRequestStatus(c.Query("status", "")),
+		AuthorID: c.Query("author_id", ""),
+		From:     from,
+		To:       to,
+		Sort:     enteties.SortOrder(c.Query("sort", "")),
+		Cursor:   c.Query("cursor", ""),
+	}
+
+	// черновики возвращаются только по явному запросу
+	if raw := c.Query("include_drafts", ""); raw != "" {
+		includeDrafts, err := strconv.ParseBool(raw)
+		if err != nil {
+			slog.Error("failed parse include_drafts", "error", err, "query", raw)
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+		}
+
+		filter.IncludeDrafts = includeDrafts
+	}
+
+	if raw := c.Query("limit", ""); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			slog.Error("failed parse limit", "error", err, "query", raw)
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+		}
+
+		filter.Limit = limit
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&filter)
+	if err != nil {
+		slog.Error("failed validate reviews filter", "error", err, "input", filter)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
 	// используем контекст от fiber для всех операций (он уже правильно настроен)
 	ctx := c.Context()
 
-	userReviews, err := uh.Service.GetReviews(ctx, userID)
+	userReviews, err := uh.Service.GetReviews(ctx, &filter)
 	if err != nil {
 
-		slog.Error("failed get reviews", "error", err, "input", userID)
+		slog.Error("failed get reviews", "error", err, "input", filter)
 		switch {
 		case errors.Is(err, service.ErrorUserNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
 
+		case errors.Is(err, service.ErrorInvalidCursor):
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvalidCursor)
+
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
 		}
@@ -88,3 +140,42 @@ func (uh *UserHandler) GetReview(c *fiber.Ctx) error {
 	slog.Info("success got reviews", "input", userID, "responce", userReviews)
 	return c.Status(fiber.StatusOK).JSON(userReviews)
 }
+
+func (uh *UserHandler) SetUsername(c *fiber.Ctx) error {
+	var request enteties.RequestUserToSetUsername
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		uh.Logger.Error("failed parse request user to set username", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		uh.Logger.Error("failed validate request user to set username", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	userResp, err := uh.Service.SetUsername(ctx, request.UserID, request.UserName)
+	if err != nil {
+
+		slog.Error("failed set username", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorUserNotFound)
+		case errors.Is(err, service.ErrorUserAlreadyExistsByUserName):
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorUserAlreadyExistsByUserName)
+
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success username set", "input", request, "responce", userResp)
+	return c.Status(fiber.StatusOK).JSON(userResp)
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
@@ -95,6 +96,28 @@ func TestHander_SetIsActive(t *testing.T) {
 				ms.EXPECT().SetIsActive(gomock.Any(), "u2", false).Return(nil, service.ErrorUserNotFound)
 			},
 		},
+		{
+			Name: "Error_role_forbidden",
+			RequestBody: `{
+				"user_id": "u3",
+				"is_active": false
+			}`,
+			User: enteties.User{
+				UserID:   "u1",
+				UserName: "name",
+				TeamName: "team",
+				IsActive: true,
+			},
+			ExpectedCode: 403,
+			ExpectedBody: `{
+			"code":  "FORBIDDEN",
+			"message": "user role does not allow this action"
+			}`,
+			MockSetup: func(ms *mocks.MockUserService) {
+
+				ms.EXPECT().SetIsActive(gomock.Any(), "u3", false).Return(nil, service.ErrorForbidden)
+			},
+		},
 		{
 			Name: "succes_changed",
 			RequestBody: `{
@@ -172,6 +195,7 @@ func TestHandler_GetReview(t *testing.T) {
 	tests := []struct {
 		Name               string
 		RequestID          string
+		Query              string
 		Assigend_reviewers map[string][]string
 		ExpectedCode       int
 		ExpectedBody       string
@@ -200,7 +224,7 @@ func TestHandler_GetReview(t *testing.T) {
 			"message": "user not found"
 			}`,
 			MockSetup: func(ms *mocks.MockUserService) {
-				ms.EXPECT().GetReviews(gomock.Any(), "u1").Return(nil, service.ErrorUserNotFound)
+				ms.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{UserID: "u1"}).Return(nil, service.ErrorUserNotFound)
 			},
 		},
 		{
@@ -222,7 +246,7 @@ func TestHandler_GetReview(t *testing.T) {
 			]
 			}`,
 			MockSetup: func(ms *mocks.MockUserService) {
-				ms.EXPECT().GetReviews(gomock.Any(), "u1").Return(&enteties.UserReviews{
+				ms.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{UserID: "u1"}).Return(&enteties.UserReviews{
 					UserID: "u1",
 					PullRequests: []enteties.PullRequestShort{
 						{
@@ -260,7 +284,7 @@ func TestHandler_GetReview(t *testing.T) {
 			]
 			}`,
 			MockSetup: func(ms *mocks.MockUserService) {
-				ms.EXPECT().GetReviews(gomock.Any(), "u1").Return(&enteties.UserReviews{
+				ms.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{UserID: "u1"}).Return(&enteties.UserReviews{
 					UserID: "u1",
 					PullRequests: []enteties.PullRequestShort{
 						{
@@ -279,12 +303,212 @@ func TestHandler_GetReview(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			Name:               "error_limit_too_large",
+			RequestID:          "u1",
+			Query:              "&limit=500",
+			Assigend_reviewers: map[string][]string{},
+			ExpectedCode:       400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:               "error_unknown_status",
+			RequestID:          "u1",
+			Query:              "&status=DONE",
+			Assigend_reviewers: map[string][]string{},
+			ExpectedCode:       400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:               "error_invalid_cursor",
+			RequestID:          "u1",
+			Query:              "&cursor=broken",
+			Assigend_reviewers: map[string][]string{},
+			ExpectedCode:       400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid cursor"
+			}`,
+			MockSetup: func(ms *mocks.MockUserService) {
+				ms.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{
+					UserID: "u1",
+					Cursor: "broken",
+				}).Return(nil, service.ErrorInvalidCursor)
+			},
+		},
+		{
+			Name:      "success_filters_and_next_cursor",
+			RequestID: "u1",
+			Query:     "&status=MERGED&author_id=author&from=2025-01-01T00:00:00Z&sort=asc&limit=1",
+			Assigend_reviewers: map[string][]string{
+				"u1": {"pr1", "pr2"},
+			},
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"user_id": "u1",
+			"pull_requests": [
+				{
+					"pull_request_id": "pr1",
+					"pull_request_name": "name",
+					"author_id": "author",
+					"status": "MERGED",
+					"created_at": "2025-01-02T10:00:00Z"
+				}
+			],
+			"next_cursor": "next"
+			}`,
+			MockSetup: func(ms *mocks.MockUserService) {
+				from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				createdAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+				ms.EXPECT().GetReviews(gomock.Any(), &enteties.UserReviewsFilter{
+					UserID:   "u1",
+					Status:   enteties.PullRequestStatusMerged,
+					AuthorID: "author",
+					From:     &from,
+					Sort:     enteties.SortOrderAsc,
+					Limit:    1,
+				}).Return(&enteties.UserReviews{
+					UserID: "u1",
+					PullRequests: []enteties.PullRequestShort{
+						{
+							PullRequestID:  "pr1",
+							PulRequestName: "name",
+							AuthorID:       "author",
+							Status:         enteties.PullRequestStatusMerged,
+							CreatedAt:      &createdAt,
+						},
+					},
+					NextCursor: "next",
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", fmt.Sprintf("/users/getReview?user_id=%s%s", tt.RequestID, tt.Query), nil)
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHander_SetUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockUserService(ctrl)
+	userHandler := NewUserHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/users/setUsername", userHandler.SetUsername)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockUserService)
+	}{
+		{
+			Name:         "Error_invalid_input_format",
+			RequestBody:  "invalid input",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input format"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "Error_not_enough_fileds",
+			RequestBody: `{
+				"user_id": "u1"
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "Error_username_exists",
+			RequestBody: `{
+				"user_id": "u1",
+				"username": "taken"
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "USER_EXISTS",
+			"message": "user with username already exists"
+			}`,
+			MockSetup: func(ms *mocks.MockUserService) {
+
+				ms.EXPECT().SetUsername(gomock.Any(), "u1", "taken").Return(nil, service.ErrorUserAlreadyExistsByUserName)
+			},
+		},
+		{
+			Name: "succes_changed",
+			RequestBody: `{
+				"user_id": "u1",
+				"username": "new_name"
+			}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"user_id":  "u1",
+			"username": "new_name",
+			"team_name": "team",
+			"is_active": true
+			}`,
+			MockSetup: func(ms *mocks.MockUserService) {
+
+				ms.EXPECT().SetUsername(gomock.Any(), "u1", "new_name").Return(&enteties.User{
+					UserID:   "u1",
+					UserName: "new_name",
+					TeamName: "team",
+					IsActive: true,
+				}, nil)
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
 
-			req := httptest.NewRequest("GET", fmt.Sprintf("/users/getReview?user_id=%s", tt.RequestID), nil)
+			req := httptest.NewRequest("POST", "/users/setUsername", strings.NewReader(tt.RequestBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			if tt.MockSetup != nil {
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"avito_intern/api/errs"
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/service"
+	"avito_intern/internal/utils"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// максимальный размер журнала доставок в одном ответе
+const maxDeliveriesLimit = 100
+
+type WebhookHandler struct {
+	Logger  *slog.Logger
+	Service service.WebhookService
+}
+
+func NewWebhookHandler(log *slog.Logger, service service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		Logger:  log,
+		Service: service,
+	}
+}
+
+func (wh *WebhookHandler) CreateSubscription(c *fiber.Ctx) error {
+
+	var request enteties.CreateWebhookSubscription
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		wh.Logger.Error("failed parse webhook subscription", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры (секрет в логи не попадает)
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		wh.Logger.Error("failed validate webhook subscription", "error", err, "team_name", request.TeamName,
+			"url", request.URL)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	subscription, err := wh.Service.CreateSubscription(ctx, &request)
+	if err != nil {
+		slog.Error("failed create webhook subscription", "error", err, "team_name", request.TeamName,
+			"url", request.URL)
+		switch {
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success webhook subscription created", "responce", subscription)
+	return c.Status(fiber.StatusCreated).JSON(subscription)
+}
+
+func (wh *WebhookHandler) DeleteSubscription(c *fiber.Ctx) error {
+
+	var request enteties.DeleteWebhookSubscription
+
+	// парсинг json request
+	err := c.BodyParser(&request)
+	if err != nil {
+		wh.Logger.Error("failed parse webhook subscription to delete", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+	}
+
+	// валидация полученной структуры
+	err = utils.ValidateStruct(&request)
+	if err != nil {
+		wh.Logger.Error("failed validate webhook subscription to delete", "error", err, "request", request)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	err = wh.Service.DeleteSubscription(ctx, &request)
+	if err != nil {
+		slog.Error("failed delete webhook subscription", "error", err, "input", request)
+		switch {
+		case errors.Is(err, service.ErrorWebhookNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorWebhookNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success webhook subscription deleted", "input", request)
+	return c.Status(fiber.StatusOK).JSON(request)
+}
+
+func (wh *WebhookHandler) GetSubscriptions(c *fiber.Ctx) error {
+
+	teamName := c.Query("team_name", "")
+	if teamName == "" {
+		slog.Error("failed get webhook subscriptions", "query", teamName)
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	webhooks, err := wh.Service.GetSubscriptions(ctx, teamName)
+	if err != nil {
+		slog.Error("failed get webhook subscriptions", "error", err, "input", teamName)
+		switch {
+		case errors.Is(err, service.ErrorTeamNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorTeamNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success got webhook subscriptions", "input", teamName, "count", len(webhooks.Subscriptions))
+	return c.Status(fiber.StatusOK).JSON(webhooks)
+}
+
+func (wh *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+
+	subscriptionID, err := strconv.ParseInt(c.Query("subscription_id", ""), 10, 64)
+	if err != nil || subscriptionID <= 0 {
+		slog.Error("failed parse subscription_id", "error", err, "query", c.Query("subscription_id", ""))
+		return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+	}
+
+	limit := 0
+	if raw := c.Query("limit", ""); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 0 || limit > maxDeliveriesLimit {
+			slog.Error("failed parse limit", "error", err, "query", raw)
+			return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+		}
+	}
+
+	// используем контекст от fiber для всех операций (он уже правильно настроен)
+	ctx := c.Context()
+
+	deliveries, err := wh.Service.GetDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		slog.Error("failed get webhook deliveries", "error", err, "input", subscriptionID)
+		switch {
+		case errors.Is(err, service.ErrorWebhookNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(errs.ErrorWebhookNotFound)
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+	}
+
+	slog.Info("success got webhook deliveries", "input", subscriptionID, "count", len(deliveries.Deliveries))
+	return c.Status(fiber.StatusOK).JSON(deliveries)
+}
@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/service"
+	"avito_intern/mocks"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_CreateWebhookSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockWebhookService(ctrl)
+	webhookHandler := NewWebhookHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/webhooks/add", webhookHandler.CreateSubscription)
+
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockWebhookService)
+	}{
+		{
+			Name:         "error_invalid_input_format",
+			RequestBody:  "invaid_input_format",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input format"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_unknown_event_type",
+			RequestBody: `{
+			"team_name": "backend",
+			"url": "https://example.com/hook",
+			"secret": "0123456789abcdef",
+			"event_types": ["pr.closed"]
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_short_secret",
+			RequestBody: `{
+			"team_name": "backend",
+			"url": "https://example.com/hook",
+			"secret": "short",
+			"event_types": ["pr.created"]
+			}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name: "error_team_not_found",
+			RequestBody: `{
+			"team_name": "unknown",
+			"url": "https://example.com/hook",
+			"secret": "0123456789abcdef",
+			"event_types": ["pr.created"]
+			}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "team not found"
+			}`,
+			MockSetup: func(ms *mocks.MockWebhookService) {
+				ms.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).Return(nil, service.ErrorTeamNotFound)
+			},
+		},
+		{
+			Name: "success",
+			RequestBody: `{
+			"team_name": "backend",
+			"url": "https://example.com/hook",
+			"secret": "0123456789abcdef",
+			"event_types": ["pr.created", "pr.merged"]
+			}`,
+			ExpectedCode: 201,
+			ExpectedBody: `{
+			"subscription_id": 1,
+			"team_name": "backend",
+			"url": "https://example.com/hook",
+			"event_types": ["pr.created", "pr.merged"],
+			"created_at": "2025-01-01T10:00:00Z"
+			}`,
+			MockSetup: func(ms *mocks.MockWebhookService) {
+				ms.EXPECT().CreateSubscription(gomock.Any(), &enteties.CreateWebhookSubscription{
+					TeamName: "backend",
+					URL:      "https://example.com/hook",
+					Secret:   "0123456789abcdef",
+					EventTypes: []enteties.WebhookEventType{
+						enteties.WebhookEventPRCreated,
+						enteties.WebhookEventPRMerged,
+					},
+				}).Return(&enteties.WebhookSubscription{
+					SubscriptionID: 1,
+					TeamName:       "backend",
+					URL:            "https://example.com/hook",
+					Secret:         "0123456789abcdef",
+					EventTypes: []enteties.WebhookEventType{
+						enteties.WebhookEventPRCreated,
+						enteties.WebhookEventPRMerged,
+					},
+					CreatedAt: createdAt,
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/webhooks/add", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHandler_DeleteWebhookSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockWebhookService(ctrl)
+	webhookHandler := NewWebhookHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Post("/webhooks/delete", webhookHandler.DeleteSubscription)
+
+	tests := []struct {
+		Name         string
+		RequestBody  string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockWebhookService)
+	}{
+		{
+			Name:         "error_invalid_input",
+			RequestBody:  `{"subscription_id": 0}`,
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_not_found",
+			RequestBody:  `{"subscription_id": 7}`,
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "webhook subscription not found"
+			}`,
+			MockSetup: func(ms *mocks.MockWebhookService) {
+				ms.EXPECT().DeleteSubscription(gomock.Any(), &enteties.DeleteWebhookSubscription{SubscriptionID: 7}).
+					Return(service.ErrorWebhookNotFound)
+			},
+		},
+		{
+			Name:         "success",
+			RequestBody:  `{"subscription_id": 1}`,
+			ExpectedCode: 200,
+			ExpectedBody: `{"subscription_id": 1}`,
+			MockSetup: func(ms *mocks.MockWebhookService) {
+				ms.EXPECT().DeleteSubscription(gomock.Any(), &enteties.DeleteWebhookSubscription{SubscriptionID: 1}).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("POST", "/webhooks/delete", strings.NewReader(tt.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
+
+func TestHandler_GetWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+	mockService := mocks.NewMockWebhookService(ctrl)
+	webhookHandler := NewWebhookHandler(logger, mockService)
+
+	app := fiber.New()
+	app.Get("/webhooks/deliveries", webhookHandler.GetDeliveries)
+
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name         string
+		Query        string
+		ExpectedCode int
+		ExpectedBody string
+		MockSetup    func(ms *mocks.MockWebhookService)
+	}{
+		{
+			Name:         "error_invalid_subscription_id",
+			Query:        "?subscription_id=abc",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_limit_too_large",
+			Query:        "?subscription_id=1&limit=101",
+			ExpectedCode: 400,
+			ExpectedBody: `{
+			"code":  "INVALID_INPUT",
+			"message": "invalid input"
+			}`,
+			MockSetup: nil,
+		},
+		{
+			Name:         "error_not_found",
+			Query:        "?subscription_id=7",
+			ExpectedCode: 404,
+			ExpectedBody: `{
+			"code":  "NOT_FOUND",
+			"message": "webhook subscription not found"
+			}`,
+			MockSetup: func(ms *mocks.MockWebhookService) {
+				ms.EXPECT().GetDeliveries(gomock.Any(), int64(7), 0).Return(nil, service.ErrorWebhookNotFound)
+			},
+		},
+		{
+			Name:         "success",
+			Query:        "?subscription_id=1&limit=2",
+			ExpectedCode: 200,
+			ExpectedBody: `{
+			"subscription_id": 1,
+			"deliveries": [
+				{
+					"delivery_id": 2,
+					"subscription_id": 1,
+					"event_id": "e1",
+					"event_type": "pr.created",
+					"attempt": 2,
+					"status_code": 204,
+					"success": true,
+					"created_at": "2025-01-01T10:00:00Z"
+				},
+				{
+					"delivery_id": 1,
+					"subscription_id": 1,
+					"event_id": "e1",
+					"event_type": "pr.created",
+					"attempt": 1,
+					"error": "connection refused",
+					"success": false,
+					"created_at": "2025-01-01T10:00:00Z"
+				}
+			]
+			}`,
+			MockSetup: func(ms *mocks.MockWebhookService) {
+				ms.EXPECT().GetDeliveries(gomock.Any(), int64(1), 2).Return(&enteties.WebhookDeliveries{
+					SubscriptionID: 1,
+					Deliveries: []enteties.WebhookDelivery{
+						{
+							DeliveryID:     2,
+							SubscriptionID: 1,
+							EventID:        "e1",
+							EventType:      enteties.WebhookEventPRCreated,
+							Attempt:        2,
+							StatusCode:     204,
+							Success:        true,
+							CreatedAt:      createdAt,
+						},
+						{
+							DeliveryID:     1,
+							SubscriptionID: 1,
+							EventID:        "e1",
+							EventType:      enteties.WebhookEventPRCreated,
+							Attempt:        1,
+							Error:          "connection refused",
+							CreatedAt:      createdAt,
+						},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			req := httptest.NewRequest("GET", "/webhooks/deliveries"+tt.Query, nil)
+
+			if tt.MockSetup != nil {
+				tt.MockSetup(mockService)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.JSONEq(t, tt.ExpectedBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"avito_intern/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// заголовок, в котором клиент передает user_id инициатора действия
+const ActorHeader = "X-Actor-ID"
+
+// Actor кладет инициатора действия из заголовка X-Actor-ID в контекст запроса, чтобы сервисы
+// записывали его в историю назначений. Заголовок учитывается только при allowHeader
+// (AUTH_ALLOW_ACTOR_HEADER), иначе с токеном администратора или при выключенной аутентификации
+// можно было бы назваться кем угодно. Без заголовка инициатором считается system
+func Actor(allowHeader bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if actor := c.Get(ActorHeader); allowHeader && actor != "" {
+			c.Context().SetUserValue(service.ActorKey, actor)
+		}
+
+		return c.Next()
+	}
+}
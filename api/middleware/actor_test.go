@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"avito_intern/internal/service"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_Actor(t *testing.T) {
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+
+	tokens, err := NewTokenStore([]string{"admin-token"}, map[string]string{"user-token": "u1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name          string
+		AllowHeader   bool
+		AuthEnabled   bool
+		Token         string
+		Actor         string
+		ExpectedActor string
+	}{
+		{
+			Name:          "success_without_header",
+			AllowHeader:   true,
+			AuthEnabled:   true,
+			Token:         "admin-token",
+			ExpectedActor: service.ActorSystem,
+		},
+		{
+			Name:          "header_ignored_for_admin_token",
+			AuthEnabled:   true,
+			Token:         "admin-token",
+			Actor:         "u2",
+			ExpectedActor: service.ActorSystem,
+		},
+		{
+			Name:          "header_ignored_with_auth_disabled",
+			Actor:         "u2",
+			ExpectedActor: service.ActorSystem,
+		},
+		{
+			Name:          "header_allowed_for_admin_token",
+			AllowHeader:   true,
+			AuthEnabled:   true,
+			Token:         "admin-token",
+			Actor:         "u2",
+			ExpectedActor: "u2",
+		},
+		{
+			Name:          "header_allowed_with_auth_disabled",
+			AllowHeader:   true,
+			Actor:         "u2",
+			ExpectedActor: "u2",
+		},
+		{
+			// пользователь не может выдать себя за другого даже при разрешенном заголовке
+			Name:          "header_ignored_for_user_token",
+			AllowHeader:   true,
+			AuthEnabled:   true,
+			Token:         "user-token",
+			Actor:         "u2",
+			ExpectedActor: "u1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(Actor(tt.AllowHeader))
+			app.Use(Auth(tokens, logger, AuthOptions{Enabled: tt.AuthEnabled}))
+			app.Get("/actor", func(c *fiber.Ctx) error {
+				return c.SendString(service.ActorFromContext(c.Context()))
+			})
+
+			req := httptest.NewRequest("GET", "/actor", nil)
+			if tt.Token != "" {
+				req.Header.Set(fiber.HeaderAuthorization, "Bearer "+tt.Token)
+			}
+			if tt.Actor != "" {
+				req.Header.Set(ActorHeader, tt.Actor)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, 200, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tt.ExpectedActor, string(body))
+		})
+	}
+}
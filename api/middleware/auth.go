@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"avito_intern/api/errs"
+	"avito_intern/internal/service"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// роли владельцев токенов
+const (
+	// администратор может вызывать любые методы
+	RoleAdmin = "admin"
+	// права пользователя определяются его ролью в базе данных (см. service.Policy)
+	RoleUser = "user"
+)
+
+var ErrorInvalidTokenConfig = errors.New("invalid token config")
+
+// ключ fiber.Locals, по которому хранится владелец токена запроса
+const principalKey = "auth_principal"
+
+// Principal владелец токена запроса. UserID задан у пользовательских токенов и у JWT
+type Principal struct {
+	Role   string
+	UserID string
+}
+
+// TokenStore сопоставляет токены с их владельцами. Токены хранятся в виде sha256, поэтому время
+// поиска не зависит от того, сколько символов токена совпало
+type TokenStore struct {
+	principals map[[sha256.Size]byte]Principal
+}
+
+// NewTokenStore создает хранилище из токенов администраторов и токенов пользователей
+// (токен -> user_id). Пустые и повторяющиеся токены считаются ошибкой конфигурации
+func NewTokenStore(adminTokens []string, userTokens map[string]string) (*TokenStore, error) {
+	store := &TokenStore{
+		principals: make(map[[sha256.Size]byte]Principal, len(adminTokens)+len(userTokens)),
+	}
+
+	add := func(token string, principal Principal) error {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return fmt.Errorf("[NewTokenStore]: %w: empty %s token", ErrorInvalidTokenConfig, principal.Role)
+		}
+
+		key := sha256.Sum256([]byte(token))
+		if _, ok := store.principals[key]; ok {
+			return fmt.Errorf("[NewTokenStore]: %w: duplicate token", ErrorInvalidTokenConfig)
+		}
+
+		store.principals[key] = principal
+		return nil
+	}
+
+	for _, token := range adminTokens {
+		err := add(token, Principal{Role: RoleAdmin})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for token, userID := range userTokens {
+		userID = strings.TrimSpace(userID)
+		if userID == "" {
+			return nil, fmt.Errorf("[NewTokenStore]: %w: empty user_id", ErrorInvalidTokenConfig)
+		}
+
+		err := add(token, Principal{Role: RoleUser, UserID: userID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// Empty возвращает true, если не задано ни одного токена
+func (ts *TokenStore) Empty() bool {
+	return len(ts.principals) == 0
+}
+
+// Lookup возвращает владельца токена
+func (ts *TokenStore) Lookup(token string) (Principal, bool) {
+	principal, ok := ts.principals[sha256.Sum256([]byte(token))]
+	return principal, ok
+}
+
+// настройки аутентификации
+type AuthOptions struct {
+	// при выключенной аутентификации каждый запрос выполняется с правами администратора
+	Enabled bool
+	// пути, доступные без токена (документация)
+	PublicPaths []string
+	// проверка JWT для токенов, которых нет среди статических (nil - JWT не принимаются)
+	JWT *JWTVerifier
+	// роли пользователей: пользователь с ролью admin получает права администратора
+	Roles RoleResolver
+}
+
+// RoleResolver определяет, выдана ли пользователю роль admin
+type RoleResolver interface {
+	IsAdmin(ctx context.Context, userID string) (bool, error)
+}
+
+// Auth проверяет bearer токен из заголовка Authorization (статический токен или JWT) и сохраняет
+// его владельца для проверок доступа в маршрутах. Запрос без токена или с неизвестным токеном
+// отклоняется с UNAUTHORIZED. Если токен определяет пользователя (пользовательский токен или JWT),
+// инициатором действий становится его user_id, X-Actor-ID (если его разрешает Actor) учитывается только
+// у статического токена администратора. Инициатор запроса сохраняется в контексте для проверки прав в сервисах.
+// Должен подключаться после Actor
+func Auth(store *TokenStore, log *slog.Logger, opts AuthOptions) fiber.Handler {
+	public := make(map[string]struct{}, len(opts.PublicPaths))
+	for _, path := range opts.PublicPaths {
+		public[path] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !opts.Enabled {
+			c.Locals(principalKey, Principal{Role: RoleAdmin})
+			c.Context().SetUserValue(service.SubjectKey, service.Subject{Admin: true})
+			return c.Next()
+		}
+
+		if _, ok := public[c.Path()]; ok {
+			return c.Next()
+		}
+
+		token, ok := bearerToken(c.Get(fiber.HeaderAuthorization))
+		if !ok {
+			log.Warn("request without bearer token", "path", c.Path())
+			return unauthorized(c)
+		}
+
+		principal, ok := store.Lookup(token)
+		if !ok && opts.JWT != nil && strings.Count(token, ".") == 2 {
+			var err error
+
+			principal, err = opts.JWT.Verify(token)
+			if err != nil {
+				log.Warn("request with invalid jwt", "path", c.Path(), "error", err)
+				return unauthorized(c)
+			}
+
+			ok = true
+		}
+
+		if !ok {
+			log.Warn("request with unknown token", "path", c.Path())
+			return unauthorized(c)
+		}
+
+		if principal.Role == RoleUser && opts.Roles != nil {
+			isAdmin, err := opts.Roles.IsAdmin(c.Context(), principal.UserID)
+			if err != nil {
+				log.Error("failed to resolve user role", "user_id", principal.UserID, "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+			}
+
+			if isAdmin {
+				principal.Role = RoleAdmin
+			}
+		}
+
+		c.Locals(principalKey, principal)
+		c.Context().SetUserValue(service.SubjectKey, service.Subject{
+			UserID: principal.UserID,
+			Admin:  principal.Role == RoleAdmin,
+		})
+
+		if principal.UserID != "" {
+			c.Context().SetUserValue(service.ActorKey, principal.UserID)
+		}
+
+		return c.Next()
+	}
+}
+
+// PrincipalFrom возвращает владельца токена запроса, сохраненного Auth
+func PrincipalFrom(c *fiber.Ctx) (Principal, bool) {
+	principal, ok := c.Locals(principalKey).(Principal)
+	return principal, ok
+}
+
+// AdminOnly пропускает только администраторов
+func AdminOnly() fiber.Handler {
+	return SelfOrAdmin(nil)
+}
+
+// SelfOrAdmin пропускает администраторов и пользователя, чей user_id совпадает с user_id,
+// который userID извлекает из запроса. При userID == nil пропускает только администраторов
+func SelfOrAdmin(userID func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := PrincipalFrom(c)
+		if !ok {
+			return unauthorized(c)
+		}
+
+		if principal.Role == RoleAdmin {
+			return c.Next()
+		}
+
+		if userID != nil && principal.Role == RoleUser {
+			if target := userID(c); target != "" && target == principal.UserID {
+				return c.Next()
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(errs.ErrorForbidden)
+	}
+}
+
+// QueryUserID извлекает user_id из параметра запроса key
+func QueryUserID(key string) func(c *fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		return c.Query(key)
+	}
+}
+
+// вспомогательная функция извлекает токен из заголовка "Authorization: Bearer <token>"
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return c.Status(fiber.StatusUnauthorized).JSON(errs.ErrorUnauthorized)
+}
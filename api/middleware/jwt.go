@@ -0,0 +1,279 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrorInvalidJWTConfig = errors.New("invalid jwt config")
+	ErrorInvalidJWT       = errors.New("invalid jwt")
+)
+
+// настройки проверки JWT
+type JWTOptions struct {
+	// общие секреты HS256 (kid -> секрет). Несколько ключей действуют одновременно, что позволяет
+	// выпускать токены новым ключом, пока старые токены еще не истекли
+	HMACKeys map[string]string
+	// путь к локальному JWKS файлу с открытыми ключами RS256
+	JWKSPath string
+	// как часто проверять, изменился ли JWKS файл (0 - файл читается только при запуске)
+	JWKSRefreshInterval time.Duration
+	// ожидаемые iss и aud, пустое значение не проверяется
+	Issuer   string
+	Audience string
+	// допустимое расхождение часов при проверке exp, nbf и iat
+	Leeway time.Duration
+}
+
+// claims JWT: sub - user_id, role - роль (admin или user)
+type jwtClaims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// JWTVerifier проверяет подпись и срок действия JWT и извлекает из него владельца токена.
+// Ключ выбирается по kid из заголовка токена, токен без kid проверяется всеми ключами алгоритма
+type JWTVerifier struct {
+	hmacKeys map[string][]byte
+	parser   *jwt.Parser
+	opts     JWTOptions
+
+	mu          sync.RWMutex
+	rsaKeys     map[string]*rsa.PublicKey
+	jwksModTime time.Time
+	jwksChecked time.Time
+}
+
+// NewJWTVerifier создает проверку JWT и загружает JWKS файл. Должен быть задан хотя бы один ключ
+func NewJWTVerifier(opts JWTOptions) (*JWTVerifier, error) {
+	verifier := &JWTVerifier{
+		hmacKeys: make(map[string][]byte, len(opts.HMACKeys)),
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+		opts:     opts,
+	}
+
+	for kid, secret := range opts.HMACKeys {
+		kid, secret = strings.TrimSpace(kid), strings.TrimSpace(secret)
+		if kid == "" || secret == "" {
+			return nil, fmt.Errorf("[NewJWTVerifier]: %w: empty HS256 kid or secret", ErrorInvalidJWTConfig)
+		}
+
+		verifier.hmacKeys[kid] = []byte(secret)
+	}
+
+	if opts.JWKSPath != "" {
+		err := verifier.loadJWKS()
+		if err != nil {
+			return nil, fmt.Errorf("[NewJWTVerifier]: %w", err)
+		}
+	}
+
+	if len(verifier.hmacKeys) == 0 && len(verifier.rsaKeys) == 0 {
+		return nil, fmt.Errorf("[NewJWTVerifier]: %w: no keys", ErrorInvalidJWTConfig)
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(opts.Leeway),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
+	verifier.parser = jwt.NewParser(parserOpts...)
+
+	return verifier, nil
+}
+
+// Verify проверяет токен и возвращает его владельца
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	var claims jwtClaims
+
+	_, err := v.parser.ParseWithClaims(token, &claims, v.keyFunc)
+	if err != nil {
+		return Principal{}, fmt.Errorf("[JWTVerifier | Verify]: %w: %w", ErrorInvalidJWT, err)
+	}
+
+	userID := strings.TrimSpace(claims.Subject)
+	if userID == "" {
+		return Principal{}, fmt.Errorf("[JWTVerifier | Verify]: %w: empty sub", ErrorInvalidJWT)
+	}
+
+	switch claims.Role {
+	case RoleAdmin, RoleUser:
+	default:
+		return Principal{}, fmt.Errorf("[JWTVerifier | Verify]: %w: unknown role %q", ErrorInvalidJWT, claims.Role)
+	}
+
+	return Principal{Role: claims.Role, UserID: userID}, nil
+}
+
+// keyFunc выбирает ключи для проверки подписи по алгоритму и kid токена
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if kid != "" {
+			key, ok := v.hmacKeys[kid]
+			if !ok {
+				return nil, fmt.Errorf("unknown kid %q", kid)
+			}
+			return key, nil
+		}
+
+		keys := jwt.VerificationKeySet{}
+		for _, key := range v.hmacKeys {
+			keys.Keys = append(keys.Keys, key)
+		}
+		return keys, nil
+
+	case jwt.SigningMethodRS256.Alg():
+		v.refreshJWKS()
+
+		v.mu.RLock()
+		defer v.mu.RUnlock()
+
+		if kid != "" {
+			key, ok := v.rsaKeys[kid]
+			if !ok {
+				return nil, fmt.Errorf("unknown kid %q", kid)
+			}
+			return key, nil
+		}
+
+		keys := jwt.VerificationKeySet{}
+		for _, key := range v.rsaKeys {
+			keys.Keys = append(keys.Keys, key)
+		}
+		return keys, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// refreshJWKS перечитывает JWKS файл, если прошло JWKSRefreshInterval и файл изменился. При
+// ошибке чтения продолжают действовать ранее загруженные ключи
+func (v *JWTVerifier) refreshJWKS() {
+	if v.opts.JWKSPath == "" || v.opts.JWKSRefreshInterval <= 0 {
+		return
+	}
+
+	v.mu.RLock()
+	due := time.Since(v.jwksChecked) >= v.opts.JWKSRefreshInterval
+	v.mu.RUnlock()
+
+	if due {
+		_ = v.loadJWKS()
+	}
+}
+
+// loadJWKS загружает открытые ключи RS256 из JWKS файла, если файл изменился с прошлой загрузки
+func (v *JWTVerifier) loadJWKS() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.jwksChecked = time.Now()
+
+	info, err := os.Stat(v.opts.JWKSPath)
+	if err != nil {
+		return fmt.Errorf("[JWTVerifier | loadJWKS]: %w", err)
+	}
+
+	if info.ModTime().Equal(v.jwksModTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(v.opts.JWKSPath)
+	if err != nil {
+		return fmt.Errorf("[JWTVerifier | loadJWKS]: %w", err)
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("[JWTVerifier | loadJWKS]: %w", err)
+	}
+
+	v.rsaKeys = keys
+	v.jwksModTime = info.ModTime()
+
+	return nil
+}
+
+// ключ JWKS (RFC 7517), используются только поля ключей RSA
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// ParseJWKS извлекает из JWKS открытые ключи RSA для подписи (kid -> ключ). Ключи других типов
+// и ключи для шифрования пропускаются
+func ParseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	err := json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("[ParseJWKS]: %w: %w", ErrorInvalidJWTConfig, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") ||
+			(key.Alg != "" && key.Alg != jwt.SigningMethodRS256.Alg()) {
+			continue
+		}
+
+		if key.Kid == "" {
+			return nil, fmt.Errorf("[ParseJWKS]: %w: RSA key without kid", ErrorInvalidJWTConfig)
+		}
+
+		if _, ok := keys[key.Kid]; ok {
+			return nil, fmt.Errorf("[ParseJWKS]: %w: duplicate kid %q", ErrorInvalidJWTConfig, key.Kid)
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("[ParseJWKS]: %w: kid %q: %w", ErrorInvalidJWTConfig, key.Kid, err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("[ParseJWKS]: %w: kid %q: %w", ErrorInvalidJWTConfig, key.Kid, err)
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, fmt.Errorf("[ParseJWKS]: %w: kid %q: bad modulus or exponent", ErrorInvalidJWTConfig, key.Kid)
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claims токена платформы со сроком действия ttl (отрицательный ttl - истекший токен)
+func testClaims(sub, role, issuer string, ttl time.Duration) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub":  sub,
+		"role": role,
+		"iss":  issuer,
+		"aud":  "avito",
+		"iat":  now.Add(-time.Hour).Unix(),
+		"exp":  now.Add(ttl).Unix(),
+	}
+}
+
+// подписывает токен методом method ключом key, kid задается, если не пустой
+func signTestToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "rsa1",
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+	}}})
+	require.NoError(t, err)
+
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksPath, jwks, 0o600))
+
+	verifier, err := NewJWTVerifier(JWTOptions{
+		HMACKeys: map[string]string{"hs1": "secret"},
+		JWKSPath: jwksPath,
+		Issuer:   "platform",
+		Audience: "avito",
+		Leeway:   30 * time.Second,
+	})
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	tests := []struct {
+		Name              string
+		Token             string
+		ExpectedPrincipal Principal
+		ExpectedErr       error
+	}{
+		{
+			Name:              "success_hs256",
+			Token:             signTestToken(t, jwt.SigningMethodHS256, "hs1", []byte("secret"), testClaims("u1", RoleUser, "platform", time.Hour)),
+			ExpectedPrincipal: Principal{Role: RoleUser, UserID: "u1"},
+		},
+		{
+			Name:              "success_rs256_admin",
+			Token:             signTestToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, testClaims("u2", RoleAdmin, "platform", time.Hour)),
+			ExpectedPrincipal: Principal{Role: RoleAdmin, UserID: "u2"},
+		},
+		{
+			// расхождение часов в пределах leeway допускается
+			Name:              "success_expired_within_leeway",
+			Token:             signTestToken(t, jwt.SigningMethodHS256, "hs1", []byte("secret"), testClaims("u1", RoleUser, "platform", -10*time.Second)),
+			ExpectedPrincipal: Principal{Role: RoleUser, UserID: "u1"},
+		},
+		{
+			Name:        "error_expired",
+			Token:       signTestToken(t, jwt.SigningMethodHS256, "hs1", []byte("secret"), testClaims("u1", RoleUser, "platform", -time.Minute)),
+			ExpectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			Name:        "error_wrong_kid_hs256",
+			Token:       signTestToken(t, jwt.SigningMethodHS256, "hs2", []byte("secret"), testClaims("u1", RoleUser, "platform", time.Hour)),
+			ExpectedErr: jwt.ErrTokenUnverifiable,
+		},
+		{
+			Name:        "error_wrong_kid_rs256",
+			Token:       signTestToken(t, jwt.SigningMethodRS256, "rsa2", rsaKey, testClaims("u1", RoleUser, "platform", time.Hour)),
+			ExpectedErr: jwt.ErrTokenUnverifiable,
+		},
+		{
+			Name:        "error_wrong_secret",
+			Token:       signTestToken(t, jwt.SigningMethodHS256, "hs1", []byte("other"), testClaims("u1", RoleUser, "platform", time.Hour)),
+			ExpectedErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			// открытый ключ RS256 известен всем, HS256 токен с kid ключа RSA не должен им проверяться
+			Name:        "error_alg_mismatch_rsa_key_as_hmac_secret",
+			Token:       signTestToken(t, jwt.SigningMethodHS256, "rsa1", publicPEM, testClaims("u1", RoleAdmin, "platform", time.Hour)),
+			ExpectedErr: jwt.ErrTokenUnverifiable,
+		},
+		{
+			Name:        "error_alg_hs384",
+			Token:       signTestToken(t, jwt.SigningMethodHS384, "hs1", []byte("secret"), testClaims("u1", RoleUser, "platform", time.Hour)),
+			ExpectedErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			Name:        "error_alg_none",
+			Token:       signTestToken(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, testClaims("u1", RoleAdmin, "platform", time.Hour)),
+			ExpectedErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			// роль admin действует только в токенах доверенного издателя
+			Name:        "error_admin_from_other_issuer",
+			Token:       signTestToken(t, jwt.SigningMethodHS256, "hs1", []byte("secret"), testClaims("u1", RoleAdmin, "other", time.Hour)),
+			ExpectedErr: jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			Name:        "error_unknown_role",
+			Token:       signTestToken(t, jwt.SigningMethodHS256, "hs1", []byte("secret"), testClaims("u1", "root", "platform", time.Hour)),
+			ExpectedErr: ErrorInvalidJWT,
+		},
+		{
+			Name:        "error_empty_sub",
+			Token:       signTestToken(t, jwt.SigningMethodHS256, "hs1", []byte("secret"), testClaims("", RoleUser, "platform", time.Hour)),
+			ExpectedErr: ErrorInvalidJWT,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			principal, err := verifier.Verify(tt.Token)
+
+			if tt.ExpectedErr != nil {
+				require.ErrorIs(t, err, tt.ExpectedErr)
+				require.ErrorIs(t, err, ErrorInvalidJWT)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedPrincipal, principal)
+		})
+	}
+}
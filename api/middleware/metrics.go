@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"avito_intern/internal/metrics"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Metrics учитывает количество и длительность HTTP запросов по методу, шаблону маршрута и
+// статусу ответа. Запросы, отклоненные middleware до обработчика (например без токена),
+// учитываются по пути, если такой маршрут зарегистрирован
+func Metrics(m *metrics.Metrics) fiber.Handler {
+	routes := &routeResolver{}
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+
+		// строки fiber ссылаются на буфер запроса, который переиспользуется, поэтому копируются
+		method := utils.CopyString(c.Method())
+
+		m.ObserveHTTPRequest(method, routes.route(c, method), responseStatus(c, err), time.Since(start))
+
+		return err
+	}
+}
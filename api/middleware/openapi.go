@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"avito_intern/api/errs"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// настройки проверки запросов и ответов по OpenAPI спецификации
+type OpenAPIOptions struct {
+	ValidateRequests  bool
+	ValidateResponses bool
+}
+
+// OpenAPI проверяет запросы и ответы по спецификации. Некорректный запрос отклоняется с
+// INVALID_INPUT до вызова хэндлера. Ответ, не соответствующий контракту, заменяется на
+// INTERNAL_SERVER, чтобы расхождение моделей enteties и спецификации сразу было видно в тестах.
+// Запросы к путям, которых нет в спецификации, пропускаются без проверок
+func OpenAPI(doc *openapi3.T, log *slog.Logger, opts OpenAPIOptions) (fiber.Handler, error) {
+
+	// сервер из спецификации привязан к хосту, поэтому маршруты сопоставляем только по пути
+	routerDoc := *doc
+	routerDoc.Servers = openapi3.Servers{{URL: "/"}}
+
+	router, err := gorillamux.NewRouter(&routerDoc)
+	if err != nil {
+		return nil, fmt.Errorf("[Middleware | OpenAPI]: %w", err)
+	}
+
+	filterOpts := &openapi3filter.Options{
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !opts.ValidateRequests && !opts.ValidateResponses {
+			return c.Next()
+		}
+
+		var req http.Request
+		err := fasthttpadaptor.ConvertRequest(c.Context(), &req, true)
+		if err != nil {
+			log.Error("failed convert request for openapi validation", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+
+		route, pathParams, err := router.FindRoute(&req)
+		if err != nil {
+			// путь или метод не описаны в спецификации
+			if errors.Is(err, routers.ErrPathNotFound) || errors.Is(err, routers.ErrMethodNotAllowed) {
+				return c.Next()
+			}
+			log.Error("failed find openapi route", "error", err, "path", c.Path())
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+
+		reqInput := &openapi3filter.RequestValidationInput{
+			Request:    &req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    filterOpts,
+		}
+
+		if opts.ValidateRequests {
+			err = openapi3filter.ValidateRequest(c.Context(), reqInput)
+			if err != nil {
+				log.Error("request does not match openapi spec", "error", err, "path", c.Path())
+
+				// тело запроса не удалось разобрать
+				var parseErr *openapi3filter.ParseError
+				if errors.As(err, &parseErr) {
+					return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInputFormat)
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(errs.ErrorInvaidInput)
+			}
+		}
+
+		err = c.Next()
+		if err != nil || !opts.ValidateResponses {
+			return err
+		}
+
+		resp := c.Response()
+
+		header := make(http.Header)
+		resp.Header.VisitAll(func(key, value []byte) {
+			header.Add(string(key), string(value))
+		})
+
+		respInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: reqInput,
+			Status:                 resp.StatusCode(),
+			Header:                 header,
+			Body:                   io.NopCloser(bytes.NewReader(resp.Body())),
+			Options:                filterOpts,
+		}
+
+		err = openapi3filter.ValidateResponse(c.Context(), respInput)
+		if err != nil {
+			log.Error("response does not match openapi spec", "error", err, "path", c.Path(), "status", resp.StatusCode())
+			return c.Status(fiber.StatusInternalServerError).JSON(errs.ErrorInternal)
+		}
+
+		return nil
+	}, nil
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// метка маршрута для запросов к неизвестным путям, чтобы их количество не раздувало метки
+const unmatchedRoute = "unmatched"
+
+// определяет шаблон маршрута запроса для метрик и трассировки. Для запросов, отклоненных middleware
+// до обработчика (например без токена), шаблоном считается путь, если такой маршрут зарегистрирован
+type routeResolver struct {
+	once   sync.Once
+	routes map[string]bool
+}
+
+// method - скопированный метод запроса
+func (rr *routeResolver) route(c *fiber.Ctx, method string) string {
+	// маршруты регистрируются после middleware, поэтому собираются при первом запросе
+	rr.once.Do(func() {
+		rr.routes = make(map[string]bool)
+		for _, route := range c.App().GetRoutes(true) {
+			rr.routes[route.Method+" "+route.Path] = true
+		}
+	})
+
+	// шаблон маршрута обработчика, иначе путь запроса, отклоненного до обработчика
+	switch {
+	case rr.routes[method+" "+c.Route().Path]:
+		return c.Route().Path
+	case rr.routes[method+" "+c.Path()]:
+		return utils.CopyString(c.Path())
+	}
+
+	return unmatchedRoute
+}
+
+// вспомогательная функция возвращает статус ответа. Ошибку обработчика fiber превратит в ответ
+// уже после middleware
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"avito_intern/internal/tracing"
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing начинает серверный спан запроса и сохраняет его в контексте запроса по tracing.SpanKey,
+// чтобы спаны сервисов, репозиториев и запросов SQL стали его потомками. Если вызывающий передал
+// заголовок traceparent (W3C Trace Context), спан продолжает его трассировку
+func Tracing() fiber.Handler {
+	routes := &routeResolver{}
+	propagator := propagation.TraceContext{}
+
+	return func(c *fiber.Ctx) error {
+		// строки fiber ссылаются на буфер запроса, который переиспользуется, поэтому копируются
+		method := utils.CopyString(c.Method())
+
+		ctx := propagator.Extract(context.Background(), headerCarrier{c: c})
+		_, span := tracing.Tracer().Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", method),
+				attribute.String("url.path", utils.CopyString(c.Path())),
+			))
+		defer span.End()
+
+		c.Context().SetUserValue(tracing.SpanKey, span)
+
+		err := c.Next()
+
+		// имя спана по шаблону маршрута известно только после выбора обработчика
+		route := routes.route(c, method)
+		status := responseStatus(c, err)
+
+		span.SetName(method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, utils.StatusMessage(status))
+		}
+
+		return err
+	}
+}
+
+// заголовки запроса fiber для пропагатора OpenTelemetry
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (hc headerCarrier) Get(key string) string {
+	// tracestate разбирается на подстроки, которые переживут буфер запроса
+	return utils.CopyString(hc.c.Get(key))
+}
+
+func (hc headerCarrier) Set(key, value string) {
+	hc.c.Request().Header.Set(key, value)
+}
+
+func (hc headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	for key := range hc.c.GetReqHeaders() {
+		keys = append(keys, key)
+	}
+	return keys
+}
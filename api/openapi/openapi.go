@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// спецификация API, на которую опираются документация и middleware валидации
+//
+//go:embed openapi.yml
+var specYAML []byte
+
+// загружает и проверяет встроенную спецификацию
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("[OpenAPI | Load]: %w", err)
+	}
+
+	err = doc.Validate(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("[OpenAPI | Load]: %w", err)
+	}
+
+	return doc, nil
+}
+
+// возвращает спецификацию в формате JSON для отдачи по /openapi.json
+func JSON(doc *openapi3.T) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("[OpenAPI | JSON]: %w", err)
+	}
+
+	return data, nil
+}
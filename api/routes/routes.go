@@ -2,25 +2,80 @@ package routes
 
 import (
 	"avito_intern/api/handlers"
+	"avito_intern/api/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// методы API доступны только администратору (по токену или роли admin), кроме чтения своих ревью
+// и методов, права на которые проверяет service.Policy по роли пользователя: они доступны любому
+// аутентифицированному пользователю и отклоняются в сервисе с FORBIDDEN
 func InitUserRoutes(app *fiber.App, h *handlers.UserHandler) {
+	admin := middleware.AdminOnly()
 	api := app.Group("/users")
 	api.Post("/setIsActive", h.SetIsActive)
-	api.Get("/getReview", h.GetReview)
+	api.Get("/getReview", middleware.SelfOrAdmin(middleware.QueryUserID("user_id")), h.GetReview)
+	api.Post("/setUsername", admin, h.SetUsername)
 }
 
 func InitTeamRoutes(app *fiber.App, h *handlers.TeamHandler) {
+	admin := middleware.AdminOnly()
 	api := app.Group("team")
 	api.Post("/add", h.CreateTeam)
 	api.Get("/get", h.GetTeam)
+	api.Get("/settings", h.GetTeamSettings)
+	api.Post("/settings", h.UpdateTeamSettings)
+	api.Post("/addMembers", h.AddMembers)
+	api.Post("/removeMembers", h.RemoveMembers)
+	api.Post("/moveMember", admin, h.MoveMember)
+	api.Post("/delete", admin, h.DeleteTeam)
+	api.Post("/deactivateUsers", admin, h.DeactivateUsers)
 }
 
 func InitPRRoutes(app *fiber.App, h *handlers.PRHandler) {
+	admin := middleware.AdminOnly()
 	api := app.Group("/pullRequest")
 	api.Post("/create", h.CreatePR)
+	api.Post("/markReady", h.MarkReady)
 	api.Post("/merge", h.MergePR)
+	api.Post("/close", h.ClosePR)
+	api.Post("/reopen", h.ReopenPR)
 	api.Post("/reassign", h.ReassignPR)
+	api.Post("/review", h.SubmitReview)
+	api.Get("/get", admin, h.GetPR)
+	api.Get("/list", admin, h.ListPRs)
+	api.Get("/history", admin, h.GetHistory)
+}
+
+func InitStatsRoutes(app *fiber.App, h *handlers.StatsHandler) {
+	admin := middleware.AdminOnly()
+	api := app.Group("/stats")
+	api.Get("/reviewers", admin, h.GetReviewerStats)
+	api.Get("/pullRequests", admin, h.GetPullRequestStats)
+}
+
+func InitWebhookRoutes(app *fiber.App, h *handlers.WebhookHandler) {
+	admin := middleware.AdminOnly()
+	api := app.Group("/webhooks")
+	api.Post("/add", admin, h.CreateSubscription)
+	api.Get("/list", admin, h.GetSubscriptions)
+	api.Post("/delete", admin, h.DeleteSubscription)
+	api.Get("/deliveries", admin, h.GetDeliveries)
+}
+
+func InitRoleRoutes(app *fiber.App, h *handlers.RoleHandler) {
+	admin := middleware.AdminOnly()
+	api := app.Group("/roles")
+	api.Post("/grant", admin, h.GrantRole)
+	api.Post("/revoke", admin, h.RevokeRole)
+	api.Get("/list", admin, h.ListRoles)
+}
+
+func InitDocsRoutes(app *fiber.App, h *handlers.DocsHandler) {
+	app.Get("/openapi.json", h.GetSpec)
+	app.Get("/docs", h.GetSwaggerUI)
+}
+
+func InitMetricsRoutes(app *fiber.App, h *handlers.MetricsHandler, path string) {
+	app.Get(path, h.GetMetrics)
 }
@@ -0,0 +1,868 @@
+package routes
+
+import (
+	"avito_intern/api/handlers"
+	"avito_intern/api/middleware"
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/metrics"
+	"avito_intern/internal/service"
+	"avito_intern/internal/tracing"
+	"avito_intern/mocks"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	adminToken     = "admin-token"
+	userToken      = "u1-token"
+	adminUserToken = "u9-token"
+)
+
+// роли пользователей для тестов: u9 - admin по роли в базе данных
+type staticRoles map[string]bool
+
+func (sr staticRoles) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	return sr[userID], nil
+}
+
+// сервис отклоняет действие, проверяя, что инициатор запроса передан в контексте
+func forbidFor(t *testing.T, userID string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		subject, ok := service.SubjectFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, service.Subject{UserID: userID}, subject)
+		return fmt.Errorf("[test]: %w", service.ErrorForbidden)
+	}
+}
+
+// приложение с аутентификацией и маршрутами, как в internal/app
+func newAuthApp(t *testing.T, ctrl *gomock.Controller, opts middleware.AuthOptions) (*fiber.App,
+	*mocks.MockUserService, *mocks.MockTeamService, *mocks.MockPRService, *mocks.MockRoleService) {
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+
+	tokens, err := middleware.NewTokenStore([]string{adminToken}, map[string]string{userToken: "u1", adminUserToken: "u9"})
+	require.NoError(t, err)
+
+	userService := mocks.NewMockUserService(ctrl)
+	teamService := mocks.NewMockTeamService(ctrl)
+	prService := mocks.NewMockPRService(ctrl)
+	roleService := mocks.NewMockRoleService(ctrl)
+
+	app := fiber.New()
+	app.Use(middleware.Actor(true))
+	opts.PublicPaths = []string{"/docs", "/openapi.json"}
+	opts.Roles = staticRoles{"u9": true}
+	app.Use(middleware.Auth(tokens, logger, opts))
+
+	InitUserRoutes(app, handlers.NewUserHandler(logger, userService))
+	InitTeamRoutes(app, handlers.NewTeamHandler(logger, teamService))
+	InitPRRoutes(app, handlers.NewPRHandler(logger, prService))
+	InitRoleRoutes(app, handlers.NewRoleHandler(logger, roleService))
+	InitDocsRoutes(app, handlers.NewDocsHandler([]byte(`{"openapi": "3.0.3"}`)))
+
+	return app, userService, teamService, prService, roleService
+}
+
+func TestRoutes_Auth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app, userService, teamService, prService, roleService := newAuthApp(t, ctrl, middleware.AuthOptions{Enabled: true})
+
+	reassigned := &enteties.ReassignPullRequestResponce{
+		PR: enteties.PullRequest{
+			PullRequestID:     "pr1",
+			PulRequestName:    "name",
+			AuthorID:          "u3",
+			Status:            enteties.PullRequestStatusOpen,
+			AssignedReviewers: []string{"u2"},
+		},
+		ReplacedBy: "u2",
+	}
+
+	tests := []struct {
+		Name         string
+		Method       string
+		Path         string
+		Token        string
+		Headers      map[string]string
+		Body         string
+		ExpectedCode int
+		ExpectedErr  string
+		MockSetup    func()
+	}{
+		{
+			Name:         "error_missing_token",
+			Method:       "POST",
+			Path:         "/team/add",
+			Body:         `{"team_name": "backend", "members": []}`,
+			ExpectedCode: 401,
+			ExpectedErr:  "UNAUTHORIZED",
+		},
+		{
+			Name:         "error_unknown_token",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        "unknown",
+			ExpectedCode: 401,
+			ExpectedErr:  "UNAUTHORIZED",
+		},
+		{
+			Name:         "error_basic_scheme",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Headers:      map[string]string{"Authorization": "Basic " + adminToken},
+			ExpectedCode: 401,
+			ExpectedErr:  "UNAUTHORIZED",
+		},
+		{
+			Name:         "error_user_creates_team",
+			Method:       "POST",
+			Path:         "/team/add",
+			Token:        userToken,
+			Body:         `{"team_name": "backend", "members": []}`,
+			ExpectedCode: 403,
+			ExpectedErr:  "FORBIDDEN",
+			MockSetup: func() {
+				// права проверяет сервис по роли пользователя
+				forbid := forbidFor(t, "u1")
+				teamService.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, team *enteties.Team) (*enteties.Team, error) {
+						return nil, forbid(ctx)
+					})
+			},
+		},
+		{
+			Name:         "error_user_sets_is_active",
+			Method:       "POST",
+			Path:         "/users/setIsActive",
+			Token:        userToken,
+			Body:         `{"user_id": "u2", "is_active": false}`,
+			ExpectedCode: 403,
+			ExpectedErr:  "FORBIDDEN",
+			MockSetup: func() {
+				forbid := forbidFor(t, "u1")
+				userService.EXPECT().SetIsActive(gomock.Any(), "u2", false).DoAndReturn(
+					func(ctx context.Context, userID string, status bool) (*enteties.User, error) {
+						return nil, forbid(ctx)
+					})
+			},
+		},
+		{
+			Name:         "error_user_closes_pr",
+			Method:       "POST",
+			Path:         "/pullRequest/close",
+			Token:        userToken,
+			Body:         `{"pull_request_id": "pr1"}`,
+			ExpectedCode: 403,
+			ExpectedErr:  "FORBIDDEN",
+			MockSetup: func() {
+				forbid := forbidFor(t, "u1")
+				prService.EXPECT().ClosePR(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *enteties.ClosePullRequest) (*enteties.PullRequest, error) {
+						return nil, forbid(ctx)
+					})
+			},
+		},
+		{
+			Name:         "error_user_lists_roles",
+			Method:       "GET",
+			Path:         "/roles/list",
+			Token:        userToken,
+			ExpectedCode: 403,
+			ExpectedErr:  "FORBIDDEN",
+		},
+		{
+			Name:         "success_role_admin_lists_roles",
+			Method:       "GET",
+			Path:         "/roles/list",
+			Token:        adminUserToken,
+			ExpectedCode: 200,
+			MockSetup: func() {
+				// пользователь с ролью admin в базе данных проходит проверки администратора
+				roleService.EXPECT().ListRoles(gomock.Any(), "").DoAndReturn(
+					func(ctx context.Context, teamName string) (*enteties.UserRoles, error) {
+						subject, _ := service.SubjectFromContext(ctx)
+						assert.Equal(t, service.Subject{UserID: "u9", Admin: true}, subject)
+						return &enteties.UserRoles{Roles: []enteties.UserRole{}}, nil
+					})
+			},
+		},
+		{
+			Name:         "error_user_reads_other_reviews",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u2",
+			Token:        userToken,
+			ExpectedCode: 403,
+			ExpectedErr:  "FORBIDDEN",
+		},
+		{
+			Name:         "error_user_reassigns_other",
+			Method:       "POST",
+			Path:         "/pullRequest/reassign",
+			Token:        userToken,
+			Body:         `{"pull_request_id": "pr1", "old_user_id": "u2"}`,
+			ExpectedCode: 403,
+			ExpectedErr:  "FORBIDDEN",
+			MockSetup: func() {
+				forbid := forbidFor(t, "u1")
+				prService.EXPECT().ReassignPR(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *enteties.ReassignPullRequest) (*enteties.ReassignPullRequestResponce, error) {
+						return nil, forbid(ctx)
+					})
+			},
+		},
+		{
+			Name:         "success_user_reads_own_reviews",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        userToken,
+			ExpectedCode: 200,
+			MockSetup: func() {
+				userService.EXPECT().GetReviews(gomock.Any(), gomock.Any()).Return(&enteties.UserReviews{
+					UserID:       "u1",
+					PullRequests: []enteties.PullRequestShort{},
+				}, nil)
+			},
+		},
+		{
+			Name:         "success_user_reassigns_self",
+			Method:       "POST",
+			Path:         "/pullRequest/reassign",
+			Token:        userToken,
+			Headers:      map[string]string{middleware.ActorHeader: "admin"},
+			Body:         `{"pull_request_id": "pr1", "old_user_id": "u1"}`,
+			ExpectedCode: 200,
+			MockSetup: func() {
+				// пользователь не может выдать себя за другого инициатора через X-Actor-ID
+				prService.EXPECT().ReassignPR(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *enteties.ReassignPullRequest) (*enteties.ReassignPullRequestResponce, error) {
+						assert.Equal(t, "u1", service.ActorFromContext(ctx))
+						return reassigned, nil
+					})
+			},
+		},
+		{
+			Name:         "success_admin_creates_team",
+			Method:       "POST",
+			Path:         "/team/add",
+			Token:        adminToken,
+			Body:         `{"team_name": "backend", "members": [{"user_id": "u1", "username": "name1", "is_active": true}]}`,
+			ExpectedCode: 201,
+			MockSetup: func() {
+				teamService.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).Return(&enteties.Team{
+					TeamName: "backend",
+					Members:  []enteties.TeamMember{{UserID: "u1", UserName: "name1", IsActive: true}},
+				}, nil)
+			},
+		},
+		{
+			Name:         "success_admin_reassigns_other",
+			Method:       "POST",
+			Path:         "/pullRequest/reassign",
+			Token:        adminToken,
+			Headers:      map[string]string{middleware.ActorHeader: "lead"},
+			Body:         `{"pull_request_id": "pr1", "old_user_id": "u1"}`,
+			ExpectedCode: 200,
+			MockSetup: func() {
+				prService.EXPECT().ReassignPR(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *enteties.ReassignPullRequest) (*enteties.ReassignPullRequestResponce, error) {
+						assert.Equal(t, "lead", service.ActorFromContext(ctx))
+						return reassigned, nil
+					})
+			},
+		},
+		{
+			Name:         "success_docs_without_token",
+			Method:       "GET",
+			Path:         "/openapi.json",
+			ExpectedCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			var body io.Reader
+			if tt.Body != "" {
+				body = strings.NewReader(tt.Body)
+			}
+
+			req := httptest.NewRequest(tt.Method, tt.Path, body)
+			if tt.Body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.Token)
+			}
+			for key, value := range tt.Headers {
+				req.Header.Set(key, value)
+			}
+
+			if tt.MockSetup != nil {
+				tt.MockSetup()
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+
+			if tt.ExpectedErr != "" {
+				respBody, err := io.ReadAll(resp.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Contains(t, string(respBody), `"code":"`+tt.ExpectedErr+`"`)
+			}
+
+			if tt.ExpectedCode == 401 {
+				assert.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRoutes_AuthDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app, _, teamService, _, _ := newAuthApp(t, ctrl, middleware.AuthOptions{Enabled: false})
+
+	teamService.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).Return(&enteties.Team{
+		TeamName: "backend",
+		Members:  []enteties.TeamMember{},
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/team/add", strings.NewReader(`{"team_name": "backend", "members": []}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, 201, resp.StatusCode)
+}
+
+func TestRoutes_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+
+	tokens, err := middleware.NewTokenStore([]string{adminToken}, nil)
+	require.NoError(t, err)
+
+	m := metrics.New()
+	userService := mocks.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Use(middleware.Metrics(m))
+	app.Use(middleware.Auth(tokens, logger, middleware.AuthOptions{
+		Enabled:     true,
+		PublicPaths: []string{"/metrics"},
+	}))
+	InitUserRoutes(app, handlers.NewUserHandler(logger, userService))
+	InitMetricsRoutes(app, handlers.NewMetricsHandler(m.Handler()), "/metrics")
+
+	userService.EXPECT().SetIsActive(gomock.Any(), "u1", false).Return(&enteties.User{
+		UserID:   "u1",
+		UserName: "name",
+		TeamName: "backend",
+	}, nil)
+
+	requests := []struct {
+		Method       string
+		Path         string
+		Token        string
+		ExpectedCode int
+	}{
+		{Method: "POST", Path: "/users/setIsActive", ExpectedCode: 401},
+		{Method: "POST", Path: "/users/setIsActive", Token: adminToken, ExpectedCode: 200},
+		{Method: "GET", Path: "/users/unknown/u1", Token: adminToken, ExpectedCode: 404},
+	}
+
+	for _, r := range requests {
+		req := httptest.NewRequest(r.Method, r.Path, strings.NewReader(`{"user_id": "u1", "is_active": false}`))
+		req.Header.Set("Content-Type", "application/json")
+		if r.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.Token)
+		}
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.Equal(t, r.ExpectedCode, resp.StatusCode, r.Path)
+	}
+
+	// метрики доступны без токена
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `pr_reviewer_http_requests_total{method="POST",route="/users/setIsActive",status="401"} 1`)
+	assert.Contains(t, string(body), `pr_reviewer_http_requests_total{method="POST",route="/users/setIsActive",status="200"} 1`)
+	assert.Contains(t, string(body), `pr_reviewer_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, string(body), `pr_reviewer_http_request_duration_seconds_count{method="POST",route="/users/setIsActive",status="200"} 1`)
+}
+
+func TestRoutes_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})
+	logger := slog.New(handler)
+
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	tokens, err := middleware.NewTokenStore([]string{adminToken}, nil)
+	require.NoError(t, err)
+
+	userService := mocks.NewMockUserService(ctrl)
+
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	app.Use(middleware.Auth(tokens, logger, middleware.AuthOptions{
+		Enabled: true,
+	}))
+	InitUserRoutes(app, handlers.NewUserHandler(logger, userService))
+
+	// сервис получает контекст, в котором спан запроса - родитель его спанов
+	userService.EXPECT().SetIsActive(gomock.Any(), "u1", false).DoAndReturn(
+		func(ctx context.Context, userID string, status bool) (*enteties.User, error) {
+			_, span := tracing.Start(ctx, "UserService.SetIsActive")
+			span.End()
+
+			return &enteties.User{UserID: userID, UserName: "name", TeamName: "backend"}, nil
+		})
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	requests := []struct {
+		Token        string
+		TraceParent  string
+		ExpectedCode int
+	}{
+		{Token: adminToken, TraceParent: "00-" + traceID + "-" + parentSpanID + "-01", ExpectedCode: 200},
+		{ExpectedCode: 401},
+	}
+
+	for _, r := range requests {
+		req := httptest.NewRequest("POST", "/users/setIsActive", strings.NewReader(`{"user_id": "u1", "is_active": false}`))
+		req.Header.Set("Content-Type", "application/json")
+		if r.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.Token)
+		}
+		if r.TraceParent != "" {
+			req.Header.Set("traceparent", r.TraceParent)
+		}
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.Equal(t, r.ExpectedCode, resp.StatusCode)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	// запрос с traceparent продолжает трассировку вызывающего
+	serviceSpan, requestSpan := spans[0], spans[1]
+	assert.Equal(t, "UserService.SetIsActive", serviceSpan.Name())
+	assert.Equal(t, "POST /users/setIsActive", requestSpan.Name())
+	assert.Equal(t, traceID, requestSpan.SpanContext().TraceID().String())
+	assert.Equal(t, parentSpanID, requestSpan.Parent().SpanID().String())
+	assert.True(t, requestSpan.Parent().IsRemote())
+	assert.Equal(t, requestSpan.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+
+	// запрос без traceparent и без токена начинает новую трассировку
+	rejected := spans[2]
+	assert.Equal(t, "POST /users/setIsActive", rejected.Name())
+	assert.False(t, rejected.Parent().IsValid())
+	assert.NotEqual(t, traceID, rejected.SpanContext().TraceID().String())
+
+	var status int64
+	for _, attr := range rejected.Attributes() {
+		if attr.Key == "http.response.status_code" {
+			status = attr.Value.AsInt64()
+		}
+	}
+	assert.Equal(t, int64(401), status)
+}
+
+func TestTokenStore(t *testing.T) {
+	_, err := middleware.NewTokenStore([]string{"same"}, map[string]string{"same": "u1"})
+	assert.ErrorIs(t, err, middleware.ErrorInvalidTokenConfig)
+
+	_, err = middleware.NewTokenStore([]string{" "}, nil)
+	assert.ErrorIs(t, err, middleware.ErrorInvalidTokenConfig)
+
+	_, err = middleware.NewTokenStore(nil, map[string]string{"token": ""})
+	assert.ErrorIs(t, err, middleware.ErrorInvalidTokenConfig)
+
+	store, err := middleware.NewTokenStore([]string{"admin"}, map[string]string{"user": "u1"})
+	require.NoError(t, err)
+
+	principal, ok := store.Lookup("user")
+	require.True(t, ok)
+	assert.Equal(t, middleware.Principal{Role: middleware.RoleUser, UserID: "u1"}, principal)
+
+	_, ok = store.Lookup("admin2")
+	assert.False(t, ok)
+}
+
+const (
+	jwtIssuer   = "platform"
+	jwtAudience = "avito_intern"
+)
+
+// claims токена платформы со сроком действия ttl (отрицательный ttl - истекший токен)
+func jwtClaims(sub, role string, ttl time.Duration) jwt.MapClaims {
+	now := time.Now()
+
+	return jwt.MapClaims{
+		"sub":  sub,
+		"role": role,
+		"iss":  jwtIssuer,
+		"aud":  jwtAudience,
+		"iat":  now.Add(min(ttl, 0) - time.Minute).Unix(),
+		"exp":  now.Add(ttl).Unix(),
+	}
+}
+
+func signJWT(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+// записывает JWKS с открытыми ключами и сдвигает время изменения файла, чтобы проверка увидела изменение
+func writeJWKS(t *testing.T, path string, keys map[string]*rsa.PrivateKey) {
+	set := map[string][]map[string]string{"keys": {}}
+	for kid, key := range keys {
+		set["keys"] = append(set["keys"], map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": jwt.SigningMethodRS256.Alg(),
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	modTime := time.Now().Add(time.Duration(len(keys)) * time.Second)
+	if info, err := os.Stat(path); err == nil && !info.ModTime().Before(modTime) {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func TestRoutes_JWT(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// два действующих секрета HS256 (старый и новый) и ключ RS256 в локальном JWKS
+	rsaKey := generateRSAKey(t)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath, map[string]*rsa.PrivateKey{"rsa1": rsaKey})
+
+	verifier, err := middleware.NewJWTVerifier(middleware.JWTOptions{
+		HMACKeys: map[string]string{"k1": "old-secret", "k2": "new-secret"},
+		JWKSPath: jwksPath,
+		Issuer:   jwtIssuer,
+		Audience: jwtAudience,
+	})
+	require.NoError(t, err)
+
+	app, userService, teamService, prService, _ := newAuthApp(t, ctrl, middleware.AuthOptions{Enabled: true, JWT: verifier})
+
+	reviews := func() {
+		userService.EXPECT().GetReviews(gomock.Any(), gomock.Any()).Return(&enteties.UserReviews{
+			UserID:       "u1",
+			PullRequests: []enteties.PullRequestShort{},
+		}, nil)
+	}
+
+	tests := []struct {
+		Name         string
+		Method       string
+		Path         string
+		Token        string
+		Body         string
+		ExpectedCode int
+		MockSetup    func()
+	}{
+		{
+			Name:         "success_hs256_user",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodHS256, "k1", []byte("old-secret"), jwtClaims("u1", "user", time.Hour)),
+			ExpectedCode: 200,
+			MockSetup:    reviews,
+		},
+		{
+			Name:         "success_hs256_rotated_key",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodHS256, "k2", []byte("new-secret"), jwtClaims("u1", "user", time.Hour)),
+			ExpectedCode: 200,
+			MockSetup:    reviews,
+		},
+		{
+			Name:         "success_hs256_without_kid",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodHS256, "", []byte("new-secret"), jwtClaims("u1", "user", time.Hour)),
+			ExpectedCode: 200,
+			MockSetup:    reviews,
+		},
+		{
+			Name:         "success_rs256_admin_is_actor",
+			Method:       "POST",
+			Path:         "/pullRequest/reassign",
+			Token:        signJWT(t, jwt.SigningMethodRS256, "rsa1", rsaKey, jwtClaims("lead", "admin", time.Hour)),
+			Body:         `{"pull_request_id": "pr1", "old_user_id": "u1"}`,
+			ExpectedCode: 200,
+			MockSetup: func() {
+				// инициатор берется из подписанного токена, а не из X-Actor-ID
+				prService.EXPECT().ReassignPR(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *enteties.ReassignPullRequest) (*enteties.ReassignPullRequestResponce, error) {
+						assert.Equal(t, "lead", service.ActorFromContext(ctx))
+						return &enteties.ReassignPullRequestResponce{
+							PR: enteties.PullRequest{
+								PullRequestID:     "pr1",
+								PulRequestName:    "name",
+								AuthorID:          "u3",
+								Status:            enteties.PullRequestStatusOpen,
+								AssignedReviewers: []string{"u2"},
+							},
+							ReplacedBy: "u2",
+						}, nil
+					})
+			},
+		},
+		{
+			Name:         "error_user_jwt_creates_team",
+			Method:       "POST",
+			Path:         "/team/add",
+			Token:        signJWT(t, jwt.SigningMethodRS256, "rsa1", rsaKey, jwtClaims("u1", "user", time.Hour)),
+			Body:         `{"team_name": "backend", "members": []}`,
+			ExpectedCode: 403,
+			MockSetup: func() {
+				forbid := forbidFor(t, "u1")
+				teamService.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, team *enteties.Team) (*enteties.Team, error) {
+						return nil, forbid(ctx)
+					})
+			},
+		},
+		{
+			Name:         "error_expired",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodHS256, "k1", []byte("old-secret"), jwtClaims("u1", "user", -time.Hour)),
+			ExpectedCode: 401,
+		},
+		{
+			Name:         "error_wrong_secret",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodHS256, "k1", []byte("new-secret"), jwtClaims("u1", "user", time.Hour)),
+			ExpectedCode: 401,
+		},
+		{
+			Name:         "error_unknown_kid",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodRS256, "rsa2", rsaKey, jwtClaims("u1", "user", time.Hour)),
+			ExpectedCode: 401,
+		},
+		{
+			Name:         "error_unknown_rsa_key",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodRS256, "", generateRSAKey(t), jwtClaims("u1", "user", time.Hour)),
+			ExpectedCode: 401,
+		},
+		{
+			Name:   "error_wrong_issuer",
+			Method: "GET",
+			Path:   "/users/getReview?user_id=u1",
+			Token: signJWT(t, jwt.SigningMethodHS256, "k1", []byte("old-secret"), func() jwt.MapClaims {
+				claims := jwtClaims("u1", "user", time.Hour)
+				claims["iss"] = "other"
+				return claims
+			}()),
+			ExpectedCode: 401,
+		},
+		{
+			Name:         "error_unknown_role",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodHS256, "k1", []byte("old-secret"), jwtClaims("u1", "owner", time.Hour)),
+			ExpectedCode: 401,
+		},
+		{
+			Name:         "error_alg_none",
+			Method:       "GET",
+			Path:         "/users/getReview?user_id=u1",
+			Token:        signJWT(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, jwtClaims("u1", "admin", time.Hour)),
+			ExpectedCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+
+			var body io.Reader
+			if tt.Body != "" {
+				body = strings.NewReader(tt.Body)
+			}
+
+			req := httptest.NewRequest(tt.Method, tt.Path, body)
+			if tt.Body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req.Header.Set("Authorization", "Bearer "+tt.Token)
+			req.Header.Set(middleware.ActorHeader, "someone")
+
+			if tt.MockSetup != nil {
+				tt.MockSetup()
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.ExpectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestJWTVerifier_JWKSRotation(t *testing.T) {
+	oldKey, newKey := generateRSAKey(t), generateRSAKey(t)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath, map[string]*rsa.PrivateKey{"old": oldKey})
+
+	verifier, err := middleware.NewJWTVerifier(middleware.JWTOptions{
+		JWKSPath:            jwksPath,
+		JWKSRefreshInterval: time.Nanosecond,
+	})
+	require.NoError(t, err)
+
+	oldToken := signJWT(t, jwt.SigningMethodRS256, "old", oldKey, jwtClaims("u1", "user", time.Hour))
+	newToken := signJWT(t, jwt.SigningMethodRS256, "new", newKey, jwtClaims("u1", "user", time.Hour))
+
+	_, err = verifier.Verify(oldToken)
+	require.NoError(t, err)
+	_, err = verifier.Verify(newToken)
+	require.ErrorIs(t, err, middleware.ErrorInvalidJWT)
+
+	// на время ротации в JWKS оба ключа
+	writeJWKS(t, jwksPath, map[string]*rsa.PrivateKey{"old": oldKey, "new": newKey})
+
+	principal, err := verifier.Verify(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, middleware.Principal{Role: middleware.RoleUser, UserID: "u1"}, principal)
+	_, err = verifier.Verify(oldToken)
+	require.NoError(t, err)
+
+	// после удаления старого ключа его токены не принимаются
+	writeJWKS(t, jwksPath, map[string]*rsa.PrivateKey{"new": newKey})
+
+	_, err = verifier.Verify(oldToken)
+	require.ErrorIs(t, err, middleware.ErrorInvalidJWT)
+
+	// испорченный файл не сбрасывает загруженные ключи
+	require.NoError(t, os.WriteFile(jwksPath, []byte("{"), 0o644))
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(jwksPath, future, future))
+
+	_, err = verifier.Verify(newToken)
+	require.NoError(t, err)
+}
+
+func TestNewJWTVerifier(t *testing.T) {
+	_, err := middleware.NewJWTVerifier(middleware.JWTOptions{})
+	assert.ErrorIs(t, err, middleware.ErrorInvalidJWTConfig)
+
+	_, err = middleware.NewJWTVerifier(middleware.JWTOptions{HMACKeys: map[string]string{"k1": ""}})
+	assert.ErrorIs(t, err, middleware.ErrorInvalidJWTConfig)
+
+	_, err = middleware.NewJWTVerifier(middleware.JWTOptions{JWKSPath: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksPath, []byte(`{"keys": [{"kty": "RSA", "kid": "k", "n": "AQAB", "e": "AQAB"},
+		{"kty": "RSA", "kid": "k", "n": "AQAB", "e": "AQAB"}]}`), 0o644))
+
+	_, err = middleware.NewJWTVerifier(middleware.JWTOptions{JWKSPath: jwksPath})
+	assert.ErrorIs(t, err, middleware.ErrorInvalidJWTConfig)
+}
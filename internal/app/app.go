@@ -2,11 +2,15 @@ package app
 
 import (
 	"avito_intern/api/handlers"
+	"avito_intern/api/middleware"
+	"avito_intern/api/openapi"
 	"avito_intern/api/routes"
 	"avito_intern/internal/config"
 	"avito_intern/internal/database/postgres"
+	"avito_intern/internal/metrics"
 	"avito_intern/internal/repository"
 	"avito_intern/internal/service"
+	"avito_intern/internal/tracing"
 	"context"
 	"errors"
 	"fmt"
@@ -15,71 +19,298 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type App struct {
 	Cfg      *config.Config
 	FiberApp *fiber.App
-	Storage  *pgx.Conn
+	Storage  *pgxpool.Pool // nil при STORAGE=memory
 	Logger   *slog.Logger
+	// фоновая доставка событий webhook, останавливается вместе с приложением
+	Webhooks service.WebhookService
+	// фоновая отправка событий outbox
+	Outbox *OutboxDispatcher
+	// провайдер спанов OpenTelemetry, nil при TRACING_EXPORTER=none
+	Tracing *sdktrace.TracerProvider
 }
 
 func InitNewApp(ctx context.Context, cfg *config.Config, log *slog.Logger) *App {
 
-	// подключаемся к DB
-	conn, err := postgres.NewPostgresDB(ctx, cfg)
+	var (
+		pool        *pgxpool.Pool
+		txManager   repository.TxManager
+		userRepo    repository.UserRepository
+		teamRepo    repository.TeamRepository
+		prRepo      repository.PRRepository
+		statsRepo   repository.StatsRepository
+		webhookRepo repository.WebhookRepository
+		outboxRepo  repository.OutboxRepository
+		roleRepo    repository.RoleRepository
+	)
+
+	// метрики Prometheus, при METRICS_ENABLED=false сервисы и пул соединений их не получают
+	var (
+		appMetrics     *metrics.Metrics
+		serviceMetrics service.Metrics
+		queryTracers   []pgx.QueryTracer
+	)
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New()
+		serviceMetrics = appMetrics
+		queryTracers = append(queryTracers, metrics.NewQueryTracer(appMetrics))
+	}
+
+	// трассировка OpenTelemetry, при TRACING_EXPORTER=none спаны не создаются
+	tracerProvider, err := tracing.NewProvider(ctx, cfg)
 	if err != nil {
-		slog.Error("Failed to connect postgres DB", "error", err)
+		log.Error("Failed to create tracer provider", "error", err)
+		os.Exit(1)
+	}
+	if tracerProvider != nil {
+		queryTracers = append(queryTracers, tracing.NewQueryTracer())
+	}
+
+	switch cfg.Storage.Type {
+	case config.StoragePostgres:
+		// подключаемся к DB (пул соединений)
+		pool, err = postgres.NewPostgresDB(ctx, cfg, combineQueryTracers(queryTracers))
+		if err != nil {
+			slog.Error("Failed to connect postgres DB", "error", err)
+			os.Exit(1)
+		}
+
+		log.Info("Successfully connected to postgres DB")
+
+		// запускаем миграции
+		err = postgres.RunMigrations(cfg)
+		if err != nil {
+			log.Error("Failed run migrations",
+				"error", err)
+			os.Exit(1)
+		}
+		log.Info("Successfully ran migrations")
+
+		// менеджер транзакций с повтором при конфликтах сериализации
+		txManager, err = repository.NewPostgresTxManager(pool, cfg.Postgres.TxIsolation, cfg.Postgres.TxMaxRetries)
+		if err != nil {
+			log.Error("Failed to create transaction manager", "error", err)
+			os.Exit(1)
+		}
+
+		// создание репозиториев
+		userRepo = repository.NewUserPostgresRepository(pool)
+		teamRepo = repository.NewTeamPostgresRepository(pool)
+		prRepo = repository.NewPRPostgresRepository(pool)
+		statsRepo = repository.NewStatsPostgresRepository(pool)
+		webhookRepo = repository.NewWebhookPostgresRepository(pool)
+		outboxRepo = repository.NewOutboxPostgresRepository(pool)
+		roleRepo = repository.NewRolePostgresRepository(pool)
+	case config.StorageMemory:
+		// данные хранятся в памяти процесса, база данных не нужна
+		storage := repository.NewMemoryStorage()
+
+		txManager = storage
+		userRepo = repository.NewUserMemoryRepository(storage)
+		teamRepo = repository.NewTeamMemoryRepository(storage)
+		prRepo = repository.NewPRMemoryRepository(storage)
+		statsRepo = repository.NewStatsMemoryRepository(storage)
+		webhookRepo = repository.NewWebhookMemoryRepository(storage)
+		outboxRepo = repository.NewOutboxMemoryRepository(storage)
+		roleRepo = repository.NewRoleMemoryRepository(storage)
+
+		log.Info("Using in-memory storage")
+	default:
+		log.Error("Unknown storage type", "storage", cfg.Storage.Type)
 		os.Exit(1)
 	}
 
-	log.Info("Successfully connected to postgres DB")
+	// количество открытых pull request и активных пользователей запрашивается при сборе метрик
+	if appMetrics != nil {
+		err := appMetrics.RegisterCounts(statsRepo, cfg.Metrics.CountsTimeout, log)
+		if err != nil {
+			log.Error("Failed to register metrics", "error", err)
+			os.Exit(1)
+		}
+	}
 
-	// запускаем миграции
-	err = postgres.RunMigrations(cfg)
+	// выбор стратегии назначения ревьюеров
+	strategy, err := service.NewReviewerStrategy(cfg.Reviewers.Strategy, prRepo)
 	if err != nil {
-		log.Error("Failed run migrations",
-			"error", err)
+		log.Error("Failed to create reviewer strategy", "error", err)
 		os.Exit(1)
 	}
-	log.Info("Successfully ran migrations")
 
-	// создание репозиториев
-	userRepo := repository.NewUserPostgresRepository(conn)
-	teamRepo := repository.NewTeamPostgresRepository(conn)
-	prRepo := repository.NewPRPostgresRepository(conn)
+	// проверка прав по ролям пользователей
+	policy := service.NewRolePolicy(roleRepo)
 
 	// создание сервисов
-	userService := service.NewUserService(conn, userRepo, prRepo)
-	teamService := service.NewTeamService(conn, userRepo, teamRepo)
-	prService := service.NewPRService(conn, userRepo, teamRepo, prRepo)
+	webhookService := service.NewWebhookService(teamRepo, webhookRepo, service.WebhookOptions{
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		Timeout:     cfg.Webhooks.Timeout,
+	})
+	userService := service.NewUserService(txManager, userRepo, prRepo, outboxRepo, roleRepo, policy)
+	teamService := service.NewTeamService(txManager, userRepo, teamRepo, prRepo, outboxRepo, roleRepo, policy, strategy,
+		cfg.Reviewers.Count, serviceMetrics)
+	prService := service.NewPRService(txManager, userRepo, teamRepo, prRepo, outboxRepo, policy, strategy,
+		cfg.Reviewers.Count, cfg.Reviewers.RequiredApprovals, serviceMetrics)
+	statsService := service.NewStatsService(teamRepo, statsRepo)
+	roleService := service.NewRoleService(txManager, userRepo, teamRepo, roleRepo, outboxRepo)
+
+	// отправка событий outbox выбранному получателю, события webhook доставляет webhook сервис
+	outboxSink, err := NewOutboxSink(cfg, log)
+	if err != nil {
+		log.Error("Failed to create outbox sink", "error", err)
+		os.Exit(1)
+	}
+
+	outboxDispatcher := NewOutboxDispatcher(outboxRepo, NewWebhookOutboxSink(outboxSink, webhookService), log, OutboxDispatcherOptions{
+		PollInterval:    cfg.Outbox.PollInterval,
+		BatchSize:       cfg.Outbox.BatchSize,
+		Lease:           cfg.Outbox.Lease,
+		RetryBaseDelay:  cfg.Outbox.RetryBaseDelay,
+		RetryMaxDelay:   cfg.Outbox.RetryMaxDelay,
+		Retention:       cfg.Outbox.Retention,
+		CleanupInterval: cfg.Outbox.CleanupInterval,
+	})
+
+	// загрузка OpenAPI спецификации для документации и проверки запросов
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Error("Failed to load openapi spec", "error", err)
+		os.Exit(1)
+	}
+
+	specJSON, err := openapi.JSON(spec)
+	if err != nil {
+		log.Error("Failed to encode openapi spec", "error", err)
+		os.Exit(1)
+	}
+
+	validator, err := middleware.OpenAPI(spec, log, middleware.OpenAPIOptions{
+		ValidateRequests:  cfg.OpenAPI.ValidateRequests,
+		ValidateResponses: cfg.OpenAPI.ValidateResponses,
+	})
+	if err != nil {
+		log.Error("Failed to create openapi validator", "error", err)
+		os.Exit(1)
+	}
+
+	// токены доступа из конфигурации
+	tokens, err := middleware.NewTokenStore(cfg.Auth.AdminTokens, cfg.Auth.UserTokens)
+	if err != nil {
+		log.Error("Failed to load auth tokens", "error", err)
+		os.Exit(1)
+	}
+
+	// проверка JWT, если заданы ключи
+	var jwtVerifier *middleware.JWTVerifier
+	if len(cfg.Auth.JWT.HMACKeys) > 0 || cfg.Auth.JWT.JWKSPath != "" {
+		jwtVerifier, err = middleware.NewJWTVerifier(middleware.JWTOptions{
+			HMACKeys:            cfg.Auth.JWT.HMACKeys,
+			JWKSPath:            cfg.Auth.JWT.JWKSPath,
+			JWKSRefreshInterval: cfg.Auth.JWT.JWKSRefreshInterval,
+			Issuer:              cfg.Auth.JWT.Issuer,
+			Audience:            cfg.Auth.JWT.Audience,
+			Leeway:              cfg.Auth.JWT.Leeway,
+		})
+		if err != nil {
+			log.Error("Failed to load jwt keys", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.Auth.Enabled && tokens.Empty() && jwtVerifier == nil {
+		log.Error("Auth is enabled, but AUTH_ADMIN_TOKENS, AUTH_USER_TOKENS and AUTH_JWT_* keys are empty")
+		os.Exit(1)
+	}
+
+	if !cfg.Auth.Enabled {
+		log.Warn("Auth is disabled, every request has admin rights")
+	}
 
 	// создание приложения fiber
 	app := fiber.New(fiber.Config{
 		Prefork: false,
 	})
 
+	// спан запроса начинается первым, чтобы в трассировку попали и отклоненные запросы
+	if tracerProvider != nil {
+		app.Use(middleware.Tracing())
+	}
+
+	// метрики учитывают и запросы, отклоненные следующими middleware
+	publicPaths := []string{"/docs", "/openapi.json"}
+	if appMetrics != nil {
+		app.Use(middleware.Metrics(appMetrics))
+		publicPaths = append(publicPaths, cfg.Metrics.Path)
+	}
+
+	// инициатор действия из заголовка X-Actor-ID для истории назначений (только с AUTH_ALLOW_ACTOR_HEADER)
+	app.Use(middleware.Actor(cfg.Auth.AllowActorHeader))
+
+	// проверка токена до проверки запроса по спецификации, чтобы без токена не раскрывать контракт
+	app.Use(middleware.Auth(tokens, log, middleware.AuthOptions{
+		Enabled:     cfg.Auth.Enabled,
+		PublicPaths: publicPaths,
+		JWT:         jwtVerifier,
+		Roles:       policy,
+	}))
+
+	// проверка запросов (и ответов в тестовом окружении) по спецификации
+	app.Use(validator)
+
 	// подключение хэндлеров
 	userHanlder := handlers.NewUserHandler(log, userService)
 	teamHandler := handlers.NewTeamHandler(log, teamService)
 	prHandler := handlers.NewPRHandler(log, prService)
+	statsHandler := handlers.NewStatsHandler(log, statsService)
+	webhookHandler := handlers.NewWebhookHandler(log, webhookService)
+	roleHandler := handlers.NewRoleHandler(log, roleService)
+	docsHandler := handlers.NewDocsHandler(specJSON)
 
 	// подключение роутов
 	routes.InitUserRoutes(app, userHanlder)
 	routes.InitTeamRoutes(app, teamHandler)
 	routes.InitPRRoutes(app, prHandler)
+	routes.InitStatsRoutes(app, statsHandler)
+	routes.InitWebhookRoutes(app, webhookHandler)
+	routes.InitRoleRoutes(app, roleHandler)
+	routes.InitDocsRoutes(app, docsHandler)
+	if appMetrics != nil {
+		routes.InitMetricsRoutes(app, handlers.NewMetricsHandler(appMetrics.Handler()), cfg.Metrics.Path)
+	}
 
 	return &App{
 		Cfg:      cfg,
 		FiberApp: app,
-		Storage:  conn,
+		Storage:  pool,
 		Logger:   log,
+		Webhooks: webhookService,
+		Outbox:   outboxDispatcher,
+		Tracing:  tracerProvider,
+	}
+}
+
+// вспомогательная функция объединяет трассировщики запросов пула, nil - запросы не отслеживаются
+func combineQueryTracers(tracers []pgx.QueryTracer) pgx.QueryTracer {
+	switch len(tracers) {
+	case 0:
+		return nil
+	case 1:
+		return tracers[0]
+	default:
+		return multitracer.New(tracers...)
 	}
 }
 
 func (a *App) Start(ctx context.Context) {
 	a.Logger.Info("App started", "port", a.Cfg.Server.ServerPort)
 
+	a.Outbox.Start()
+
 	go func() {
 		err := a.FiberApp.Listen(fmt.Sprintf(":%s", a.Cfg.Server.ServerPort))
 		if err != nil {
@@ -93,20 +324,34 @@ func (a *App) Start(ctx context.Context) {
 func (a *App) Stop(ctx context.Context) error {
 	a.Logger.Info("[!] Shutting down...")
 
-	var stopErr = errors.New("")
-
-	// закрываем соединение БД
-	if err := postgres.ClosePostgresDB(ctx, a.Storage); err != nil {
-		errors.Join(stopErr, err)
-	}
+	var stopErr error
 
 	// закрываем соединение с сервером
 	if err := a.FiberApp.ShutdownWithContext(ctx); err != nil {
-		errors.Join(stopErr, err)
+		stopErr = errors.Join(stopErr, err)
+	}
+
+	// останавливаем отправку outbox до закрытия пула, неотправленные события останутся в таблице
+	if err := a.Outbox.Stop(ctx); err != nil {
+		stopErr = errors.Join(stopErr, err)
+	}
+
+	// дожидаемся начатых доставок webhook, они пишут журнал в хранилище
+	if err := a.Webhooks.Shutdown(ctx); err != nil {
+		stopErr = errors.Join(stopErr, err)
 	}
 
-	if stopErr.Error() != "" {
-		return stopErr
+	// закрываем пул соединений БД после сервера, чтобы дождаться завершения запросов
+	if a.Storage != nil {
+		postgres.ClosePostgresDB(a.Storage)
 	}
-	return nil
+
+	// отправляем накопленные спаны после завершения запросов
+	if a.Tracing != nil {
+		if err := a.Tracing.Shutdown(ctx); err != nil {
+			stopErr = errors.Join(stopErr, err)
+		}
+	}
+
+	return stopErr
 }
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"avito_intern/mocks"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// вспомогательная функция создает приложение без хранилища и трассировки
+func newStopTestApp(webhooks *mocks.MockWebhookService) *App {
+	return &App{
+		FiberApp: fiber.New(),
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Webhooks: webhooks,
+		Outbox:   newTestDispatcher(repository.NewOutboxMemoryRepository(repository.NewMemoryStorage()), &recordingSink{}),
+	}
+}
+
+func TestApp_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// без ошибок остановки Stop возвращает nil
+	webhooks := mocks.NewMockWebhookService(ctrl)
+	webhooks.EXPECT().Shutdown(gomock.Any()).Return(nil)
+
+	require.NoError(t, newStopTestApp(webhooks).Stop(context.Background()))
+
+	// ошибка доставки webhook не теряется
+	errWebhooks := errors.New("webhook deliveries not finished")
+	webhooks = mocks.NewMockWebhookService(ctrl)
+	webhooks.EXPECT().Shutdown(gomock.Any()).Return(errWebhooks)
+
+	err := newStopTestApp(webhooks).Stop(context.Background())
+	assert.ErrorIs(t, err, errWebhooks)
+
+	// отправка outbox не успела завершиться до отмены ctx
+	webhooks = mocks.NewMockWebhookService(ctrl)
+	webhooks.EXPECT().Shutdown(gomock.Any()).Return(nil)
+
+	repo := repository.NewOutboxMemoryRepository(repository.NewMemoryStorage())
+	addTestEvents(t, repo, 1)
+
+	sink := &blockingSink{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(sink.release)
+
+	app := newStopTestApp(webhooks)
+	app.Outbox = newTestDispatcher(repo, sink)
+	app.Outbox.Start()
+	<-sink.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = app.Stop(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// ошибка отправки накопленных спанов не теряется
+	webhooks = mocks.NewMockWebhookService(ctrl)
+	webhooks.EXPECT().Shutdown(gomock.Any()).Return(nil)
+
+	errExport := errors.New("collector unavailable")
+	app = newStopTestApp(webhooks)
+	app.Tracing = sdktrace.NewTracerProvider(sdktrace.WithSyncer(&failingExporter{err: errExport}))
+
+	err = app.Stop(context.Background())
+	assert.ErrorIs(t, err, errExport)
+}
+
+// экспортер для тестов: не может завершить отправку спанов при остановке
+type failingExporter struct {
+	err error
+}
+
+func (fe *failingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return nil
+}
+
+func (fe *failingExporter) Shutdown(ctx context.Context) error {
+	return fe.err
+}
+
+// получатель для тестов: сообщает о начале отправки и не завершает ее до закрытия release
+type blockingSink struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (bs *blockingSink) Publish(ctx context.Context, events []enteties.OutboxEvent) error {
+	close(bs.started)
+	<-bs.release
+	return nil
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// OutboxSink получатель событий outbox. Publish возвращает ошибку, если пакет принят не целиком,
+// тогда весь пакет отправляется повторно, поэтому получатель должен отбрасывать дубликаты по event_id
+type OutboxSink interface {
+	Publish(ctx context.Context, events []enteties.OutboxEvent) error
+}
+
+// параметры отправки событий outbox
+type OutboxDispatcherOptions struct {
+	PollInterval    time.Duration
+	BatchSize       int
+	Lease           time.Duration
+	RetryBaseDelay  time.Duration
+	RetryMaxDelay   time.Duration
+	Retention       time.Duration
+	CleanupInterval time.Duration
+}
+
+// OutboxDispatcher в фоне отправляет записанные в outbox события получателю (доставка хотя бы
+// один раз) и удаляет доставленные события по истечении времени хранения
+type OutboxDispatcher struct {
+	Repo    repository.OutboxRepository
+	Sink    OutboxSink
+	Logger  *slog.Logger
+	Options OutboxDispatcherOptions
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewOutboxDispatcher(repo repository.OutboxRepository, sink OutboxSink, log *slog.Logger,
+	options OutboxDispatcherOptions) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		Repo:    repo,
+		Sink:    sink,
+		Logger:  log,
+		Options: options,
+	}
+}
+
+// Start запускает фоновую отправку событий
+func (d *OutboxDispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go d.run(ctx)
+}
+
+// Stop останавливает отправку и дожидается завершения текущего пакета или отмены ctx.
+// Неотправленные события остаются в outbox и будут отправлены после перезапуска
+func (d *OutboxDispatcher) Stop(ctx context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+
+	d.cancel()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("[OutboxDispatcher | Stop]: %w", ctx.Err())
+	}
+}
+
+func (d *OutboxDispatcher) run(ctx context.Context) {
+	defer close(d.done)
+
+	poll := time.NewTicker(d.Options.PollInterval)
+	defer poll.Stop()
+
+	cleanup := time.NewTicker(d.Options.CleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		// отправляем пакеты подряд, пока готовые события не закончатся
+		for ctx.Err() == nil {
+			sent, err := d.dispatch(ctx)
+			if err != nil {
+				d.Logger.Error("Failed to dispatch outbox events", "error", err)
+				break
+			}
+
+			if sent < d.Options.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		case <-cleanup.C:
+			d.cleanup(ctx)
+		}
+	}
+}
+
+// dispatch отправляет один пакет событий и возвращает количество доставленных
+func (d *OutboxDispatcher) dispatch(ctx context.Context) (int, error) {
+	events, err := d.Repo.ClaimEvents(ctx, d.Options.BatchSize, d.Options.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("[OutboxDispatcher | dispatch]: %w", err)
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, 0, len(events))
+	attempt := 0
+	for _, event := range events {
+		ids = append(ids, event.EventID)
+		attempt = max(attempt, event.Attempt)
+	}
+
+	// результат отправки сохраняется и при остановке сервиса, иначе события ждали бы истечения lease
+	storeCtx := context.WithoutCancel(ctx)
+
+	publishErr := d.Sink.Publish(ctx, events)
+	if publishErr != nil {
+		err = d.Repo.MarkFailed(storeCtx, ids, d.backoff(attempt), publishErr.Error())
+		if err != nil {
+			return 0, fmt.Errorf("[OutboxDispatcher | dispatch]: %w", err)
+		}
+
+		return 0, fmt.Errorf("[OutboxDispatcher | dispatch]: %w", publishErr)
+	}
+
+	// если отметка не сохранится, события отправятся повторно после истечения lease
+	err = d.Repo.MarkDelivered(storeCtx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("[OutboxDispatcher | dispatch]: %w", err)
+	}
+
+	d.Logger.Debug("Outbox events dispatched", "count", len(events))
+	return len(events), nil
+}
+
+// cleanup удаляет доставленные события старше времени хранения
+func (d *OutboxDispatcher) cleanup(ctx context.Context) {
+	deleted, err := d.Repo.DeleteDelivered(ctx, d.Options.Retention)
+	if err != nil {
+		d.Logger.Error("Failed to delete delivered outbox events", "error", err)
+		return
+	}
+
+	if deleted > 0 {
+		d.Logger.Info("Deleted delivered outbox events", "count", deleted)
+	}
+}
+
+// задержка перед повтором после attempt неудачных попыток: RetryBaseDelay, удваивается после
+// каждой неудачи, но не больше RetryMaxDelay
+func (d *OutboxDispatcher) backoff(attempt int) time.Duration {
+	delay := d.Options.RetryBaseDelay
+	for i := 1; i < attempt && delay < d.Options.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.Options.RetryMaxDelay)
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"avito_intern/internal/config"
+	"avito_intern/internal/enteties"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+)
+
+var ErrorUnknownOutboxSink = errors.New("unknown outbox sink")
+
+// NewOutboxSink создает получателя событий outbox по настройкам OUTBOX_*
+func NewOutboxSink(cfg *config.Config, log *slog.Logger) (OutboxSink, error) {
+	switch cfg.Outbox.Sink {
+	case config.OutboxSinkLog:
+		return NewLogOutboxSink(log), nil
+	case config.OutboxSinkHTTP:
+		if cfg.Outbox.HTTPURL == "" {
+			return nil, fmt.Errorf("[NewOutboxSink]: OUTBOX_HTTP_URL is required for sink %q", cfg.Outbox.Sink)
+		}
+
+		return NewHTTPOutboxSink(cfg.Outbox.HTTPURL, &http.Client{Timeout: cfg.Outbox.HTTPTimeout}), nil
+	case config.OutboxSinkFile:
+		return NewFileOutboxSink(cfg.Outbox.FilePath), nil
+	default:
+		return nil, fmt.Errorf("[NewOutboxSink]: %w: %s", ErrorUnknownOutboxSink, cfg.Outbox.Sink)
+	}
+}
+
+// отправляет события webhook.event подписчикам webhook, остальные события - основному получателю.
+// Пакет считается доставленным, только если свою часть приняли оба получателя
+type webhookOutboxSink struct {
+	Events   OutboxSink
+	Webhooks OutboxSink
+}
+
+func NewWebhookOutboxSink(events OutboxSink, webhooks OutboxSink) *webhookOutboxSink {
+	return &webhookOutboxSink{
+		Events:   events,
+		Webhooks: webhooks,
+	}
+}
+
+func (ws *webhookOutboxSink) Publish(ctx context.Context, events []enteties.OutboxEvent) error {
+	domainEvents := make([]enteties.OutboxEvent, 0, len(events))
+	webhookEvents := make([]enteties.OutboxEvent, 0)
+	for _, event := range events {
+		if event.EventType == enteties.OutboxEventWebhook {
+			webhookEvents = append(webhookEvents, event)
+			continue
+		}
+
+		domainEvents = append(domainEvents, event)
+	}
+
+	var err error
+	if len(domainEvents) > 0 {
+		err = ws.Events.Publish(ctx, domainEvents)
+	}
+
+	// подписчики webhook получают события, даже если основной получатель недоступен. При повторе
+	// пакета подписчики, которые уже приняли событие, пропускаются
+	if len(webhookEvents) > 0 {
+		err = errors.Join(err, ws.Webhooks.Publish(ctx, webhookEvents))
+	}
+
+	if err != nil {
+		return fmt.Errorf("[WebhookOutboxSink | Publish]: %w", err)
+	}
+
+	return nil
+}
+
+// логирует события, подходит для локального запуска
+type logOutboxSink struct {
+	Logger *slog.Logger
+}
+
+func NewLogOutboxSink(log *slog.Logger) *logOutboxSink {
+	return &logOutboxSink{
+		Logger: log,
+	}
+}
+
+func (ls *logOutboxSink) Publish(ctx context.Context, events []enteties.OutboxEvent) error {
+	for _, event := range events {
+		ls.Logger.Info("outbox event", "event_id", event.EventID, "event_type", event.EventType,
+			"actor", event.Actor, "attempt", event.Attempt, "payload", string(event.Payload))
+	}
+
+	return nil
+}
+
+// отправляет пакет событий POST запросом с JSON массивом в теле, ответ не 2xx - ошибка
+type httpOutboxSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewHTTPOutboxSink(url string, client *http.Client) *httpOutboxSink {
+	return &httpOutboxSink{
+		URL:    url,
+		Client: client,
+	}
+}
+
+func (hs *httpOutboxSink) Publish(ctx context.Context, events []enteties.OutboxEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("[HTTPOutboxSink | Publish]: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hs.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("[HTTPOutboxSink | Publish]: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hs.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("[HTTPOutboxSink | Publish]: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// дочитываем тело, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("[HTTPOutboxSink | Publish]: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// дописывает события в файл, по одному JSON объекту на строку
+type fileOutboxSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileOutboxSink(path string) *fileOutboxSink {
+	return &fileOutboxSink{
+		Path: path,
+	}
+}
+
+func (fs *fileOutboxSink) Publish(ctx context.Context, events []enteties.OutboxEvent) error {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		err := encoder.Encode(event)
+		if err != nil {
+			return fmt.Errorf("[FileOutboxSink | Publish]: %w", err)
+		}
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	file, err := os.OpenFile(fs.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("[FileOutboxSink | Publish]: %w", err)
+	}
+
+	_, err = file.Write(buf.Bytes())
+	if err == nil {
+		// событие считается доставленным только после записи на диск
+		err = file.Sync()
+	}
+
+	closeErr := file.Close()
+	if err = errors.Join(err, closeErr); err != nil {
+		return fmt.Errorf("[FileOutboxSink | Publish]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+package app
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// получатель для тестов: запоминает принятые события, первые failures вызовов завершаются ошибкой
+type recordingSink struct {
+	mu       sync.Mutex
+	failures int
+	events   []enteties.OutboxEvent
+}
+
+func (rs *recordingSink) Publish(ctx context.Context, events []enteties.OutboxEvent) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.failures > 0 {
+		rs.failures--
+		return errors.New("sink unavailable")
+	}
+
+	rs.events = append(rs.events, events...)
+	return nil
+}
+
+func (rs *recordingSink) received() []enteties.OutboxEvent {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]enteties.OutboxEvent{}, rs.events...)
+}
+
+func newTestDispatcher(repo repository.OutboxRepository, sink OutboxSink) *OutboxDispatcher {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return NewOutboxDispatcher(repo, sink, logger, OutboxDispatcherOptions{
+		PollInterval:    10 * time.Millisecond,
+		BatchSize:       2,
+		Lease:           time.Minute,
+		RetryBaseDelay:  0,
+		RetryMaxDelay:   time.Minute,
+		Retention:       0,
+		CleanupInterval: time.Hour,
+	})
+}
+
+func addTestEvents(t *testing.T, repo repository.OutboxRepository, count int) {
+	for i := 0; i < count; i++ {
+		err := repo.AddEvent(context.Background(), &enteties.OutboxEvent{
+			EventType: enteties.OutboxEventTeamCreated,
+			Actor:     "system",
+			Payload:   json.RawMessage(`{"team_name":"backend"}`),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestOutboxDispatcher_RetryAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewOutboxMemoryRepository(repository.NewMemoryStorage())
+	sink := &recordingSink{failures: 1}
+	dispatcher := newTestDispatcher(repo, sink)
+
+	addTestEvents(t, repo, 3)
+
+	// ошибка получателя откладывает пакет на повтор
+	_, err := dispatcher.dispatch(ctx)
+	require.Error(t, err)
+	assert.Empty(t, sink.received())
+
+	sent, err := dispatcher.dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	sent, err = dispatcher.dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	// доставленные события больше не отправляются
+	sent, err = dispatcher.dispatch(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	received := sink.received()
+	require.Len(t, received, 3)
+	assert.Equal(t, []int64{1, 2, 3}, []int64{received[0].EventID, received[1].EventID, received[2].EventID})
+	assert.Equal(t, 2, received[0].Attempt)
+	assert.Equal(t, 1, received[2].Attempt)
+
+	deleted, err := repo.DeleteDelivered(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
+
+func TestOutboxDispatcher_LeaseAfterCrash(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewOutboxMemoryRepository(repository.NewMemoryStorage())
+	sink := &recordingSink{}
+	dispatcher := newTestDispatcher(repo, sink)
+
+	addTestEvents(t, repo, 1)
+
+	// процесс выбрал событие и упал до подтверждения
+	claimed, err := repo.ClaimEvents(ctx, 10, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	sent, err := dispatcher.dispatch(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// после истечения lease событие отправляется повторно
+	time.Sleep(30 * time.Millisecond)
+
+	sent, err = dispatcher.dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 2, sink.received()[0].Attempt)
+}
+
+func TestOutboxDispatcher_StartStop(t *testing.T) {
+	repo := repository.NewOutboxMemoryRepository(repository.NewMemoryStorage())
+	sink := &recordingSink{}
+	dispatcher := newTestDispatcher(repo, sink)
+
+	dispatcher.Start()
+
+	addTestEvents(t, repo, 5)
+
+	require.Eventually(t, func() bool {
+		return len(sink.received()) == 5
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, dispatcher.Stop(ctx))
+}
+
+func TestOutboxDispatcher_Backoff(t *testing.T) {
+	dispatcher := NewOutboxDispatcher(nil, nil, nil, OutboxDispatcherOptions{
+		RetryBaseDelay: time.Second,
+		RetryMaxDelay:  5 * time.Second,
+	})
+
+	assert.Equal(t, time.Second, dispatcher.backoff(1))
+	assert.Equal(t, 2*time.Second, dispatcher.backoff(2))
+	assert.Equal(t, 4*time.Second, dispatcher.backoff(3))
+	assert.Equal(t, 5*time.Second, dispatcher.backoff(10))
+}
+
+func TestHTTPOutboxSink(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []enteties.OutboxEvent
+		status   = http.StatusInternalServerError
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var events []enteties.OutboxEvent
+		if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if status == http.StatusOK {
+			received = append(received, events...)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPOutboxSink(server.URL, server.Client())
+	events := []enteties.OutboxEvent{{
+		EventID:   1,
+		EventType: enteties.OutboxEventPRMerged,
+		Actor:     "u1",
+		Payload:   json.RawMessage(`{"pull_request_id":"pr1"}`),
+		Attempt:   1,
+	}}
+
+	err := sink.Publish(context.Background(), events)
+	require.Error(t, err)
+
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+
+	err = sink.Publish(context.Background(), events)
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	assert.Equal(t, enteties.OutboxEventPRMerged, received[0].EventType)
+	assert.JSONEq(t, `{"pull_request_id":"pr1"}`, string(received[0].Payload))
+}
+
+func TestFileOutboxSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	sink := NewFileOutboxSink(path)
+
+	for i := int64(1); i <= 2; i++ {
+		err := sink.Publish(context.Background(), []enteties.OutboxEvent{{
+			EventID:   i,
+			EventType: enteties.OutboxEventTeamCreated,
+			Actor:     "system",
+			Payload:   json.RawMessage(`{"team_name":"backend"}`),
+			Attempt:   1,
+		}})
+		require.NoError(t, err)
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	// каждая строка - отдельное событие
+	ids := make([]int64, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event enteties.OutboxEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		ids = append(ids, event.EventID)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []int64{1, 2}, ids)
+}
+
+func TestWebhookOutboxSink(t *testing.T) {
+	events := []enteties.OutboxEvent{
+		{EventID: 1, EventType: enteties.OutboxEventTeamCreated, Payload: json.RawMessage(`{}`), Attempt: 1},
+		{EventID: 2, EventType: enteties.OutboxEventWebhook, Payload: json.RawMessage(`{}`), Attempt: 1},
+		{EventID: 3, EventType: enteties.OutboxEventPRMerged, Payload: json.RawMessage(`{}`), Attempt: 1},
+	}
+
+	tests := []struct {
+		name             string
+		eventsFailures   int
+		webhooksFailures int
+		wantErr          bool
+		wantEvents       []int64
+		wantWebhooks     []int64
+	}{
+		{
+			name:         "success",
+			wantEvents:   []int64{1, 3},
+			wantWebhooks: []int64{2},
+		},
+		{
+			// webhook подписчики получают события, даже если основной получатель недоступен
+			name:           "error_events_sink",
+			eventsFailures: 1,
+			wantErr:        true,
+			wantEvents:     []int64{},
+			wantWebhooks:   []int64{2},
+		},
+		{
+			name:             "error_webhooks_sink",
+			webhooksFailures: 1,
+			wantErr:          true,
+			wantEvents:       []int64{1, 3},
+			wantWebhooks:     []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventsSink := &recordingSink{failures: tt.eventsFailures}
+			webhooksSink := &recordingSink{failures: tt.webhooksFailures}
+			sink := NewWebhookOutboxSink(eventsSink, webhooksSink)
+
+			err := sink.Publish(context.Background(), events)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantEvents, eventIDs(eventsSink.received()))
+			assert.Equal(t, tt.wantWebhooks, eventIDs(webhooksSink.received()))
+		})
+	}
+}
+
+func eventIDs(events []enteties.OutboxEvent) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.EventID)
+	}
+
+	return ids
+}
@@ -1,13 +1,50 @@
 package config
 
 import (
+	"time"
+
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+// хранилища данных сервиса
+const (
+	StoragePostgres = "postgres"
+	// данные хранятся в памяти процесса и теряются при перезапуске (тесты и локальные демо)
+	StorageMemory = "memory"
+)
+
+// получатели событий outbox
+const (
+	OutboxSinkLog  = "log"
+	OutboxSinkHTTP = "http"
+	OutboxSinkFile = "file"
+)
+
+// экспортеры трассировки OpenTelemetry
+const (
+	TracingExporterNone = "none"
+	TracingExporterOTLP = "otlp"
+	// stdout и file пишут спаны в JSON для локальной отладки
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
 type Config struct {
-	Postgres postgresConfig
-	Server   serverConfig
-	Logger   loggerConfig
+	Storage   storageConfig
+	Postgres  postgresConfig
+	Server    serverConfig
+	Logger    loggerConfig
+	Reviewers reviewersConfig
+	OpenAPI   openAPIConfig
+	Webhooks  webhooksConfig
+	Outbox    outboxConfig
+	Auth      authConfig
+	Metrics   metricsConfig
+	Tracing   tracingConfig
+}
+
+type storageConfig struct {
+	Type string `env:"STORAGE" env-default:"postgres"`
 }
 
 type postgresConfig struct {
@@ -17,6 +54,18 @@ type postgresConfig struct {
 	Password string `env:"DB_PASSWORD,required"`
 	Name     string `env:"DB_NAME,required"`
 	SSLMode  string `env:"DB_SSLMODE" envDefault:"disable"`
+
+	// настройки пула соединений
+	MaxConns          int32         `env:"DB_MAX_CONNS" env-default:"10"`
+	MinConns          int32         `env:"DB_MIN_CONNS" env-default:"2"`
+	MaxConnLifetime   time.Duration `env:"DB_MAX_CONN_LIFETIME" env-default:"1h"`
+	MaxConnIdleTime   time.Duration `env:"DB_MAX_CONN_IDLE_TIME" env-default:"30m"`
+	HealthCheckPeriod time.Duration `env:"DB_HEALTH_CHECK_PERIOD" env-default:"1m"`
+
+	// уровень изоляции транзакций (read_committed, repeatable_read, serializable)
+	// и число повторов при конфликтах сериализации и дедлоках
+	TxIsolation  string `env:"DB_TX_ISOLATION" env-default:"read_committed"`
+	TxMaxRetries int    `env:"DB_TX_MAX_RETRIES" env-default:"3"`
 }
 
 type serverConfig struct {
@@ -27,6 +76,88 @@ type loggerConfig struct {
 	LogLevel string `env:"LOG_LEVEL" envDefault:"INFO"`
 }
 
+// стратегия выбора ревьюеров и их количество на один pull request
+type reviewersConfig struct {
+	Strategy string `env:"REVIEWERS_STRATEGY" env-default:"least_open_reviews"`
+	Count    int    `env:"REVIEWERS_COUNT" env-default:"2"`
+	// количество одобрений ревьюеров, необходимое для мерджа (0 - мердж без одобрений)
+	RequiredApprovals int `env:"REVIEWERS_REQUIRED_APPROVALS" env-default:"0"`
+}
+
+// проверка запросов и ответов по OpenAPI спецификации
+type openAPIConfig struct {
+	ValidateRequests bool `env:"OPENAPI_VALIDATE_REQUESTS" env-default:"true"`
+	// проверка ответов нужна в тестовом окружении, чтобы ловить расхождения моделей и контракта
+	ValidateResponses bool `env:"OPENAPI_VALIDATE_RESPONSES" env-default:"false"`
+}
+
+// доставка событий подписчикам webhook: количество попыток (повторы выполняет диспетчер outbox
+// с задержкой OUTBOX_RETRY_*) и таймаут одного запроса
+type webhooksConfig struct {
+	MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"5"`
+	Timeout     time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"5s"`
+}
+
+// отправка событий outbox: получатель (log, http или file) и его параметры, период опроса таблицы,
+// размер пакета, время, на которое выбранные события откладываются до подтверждения отправки,
+// задержка повтора после ошибки (удваивается до RetryMaxDelay) и время хранения доставленных событий
+type outboxConfig struct {
+	Sink            string        `env:"OUTBOX_SINK" env-default:"log"`
+	HTTPURL         string        `env:"OUTBOX_HTTP_URL"`
+	HTTPTimeout     time.Duration `env:"OUTBOX_HTTP_TIMEOUT" env-default:"5s"`
+	FilePath        string        `env:"OUTBOX_FILE_PATH" env-default:"outbox.jsonl"`
+	PollInterval    time.Duration `env:"OUTBOX_POLL_INTERVAL" env-default:"1s"`
+	BatchSize       int           `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	Lease           time.Duration `env:"OUTBOX_LEASE" env-default:"30s"`
+	RetryBaseDelay  time.Duration `env:"OUTBOX_RETRY_BASE_DELAY" env-default:"1s"`
+	RetryMaxDelay   time.Duration `env:"OUTBOX_RETRY_MAX_DELAY" env-default:"5m"`
+	Retention       time.Duration `env:"OUTBOX_RETENTION" env-default:"24h"`
+	CleanupInterval time.Duration `env:"OUTBOX_CLEANUP_INTERVAL" env-default:"1h"`
+}
+
+// аутентификация по bearer токенам: токены администраторов через запятую и токены пользователей
+// в формате token:user_id через запятую. При выключенной аутентификации все запросы выполняются
+// с правами администратора. AllowActorHeader разрешает администратору указывать инициатора
+// действия в X-Actor-ID, по умолчанию заголовок игнорируется
+type authConfig struct {
+	Enabled          bool              `env:"AUTH_ENABLED" env-default:"true"`
+	AdminTokens      []string          `env:"AUTH_ADMIN_TOKENS" env-separator:","`
+	UserTokens       map[string]string `env:"AUTH_USER_TOKENS" env-separator:","`
+	AllowActorHeader bool              `env:"AUTH_ALLOW_ACTOR_HEADER" env-default:"false"`
+	JWT              jwtConfig
+}
+
+// проверка JWT: секреты HS256 в формате kid:secret через запятую и JWKS файл с открытыми ключами
+// RS256, который перечитывается при изменении не чаще JWKSRefreshInterval. Без ключей JWT не принимаются
+type jwtConfig struct {
+	HMACKeys            map[string]string `env:"AUTH_JWT_HS256_KEYS" env-separator:","`
+	JWKSPath            string            `env:"AUTH_JWT_JWKS_PATH"`
+	JWKSRefreshInterval time.Duration     `env:"AUTH_JWT_JWKS_REFRESH_INTERVAL" env-default:"1m"`
+	Issuer              string            `env:"AUTH_JWT_ISSUER"`
+	Audience            string            `env:"AUTH_JWT_AUDIENCE"`
+	Leeway              time.Duration     `env:"AUTH_JWT_LEEWAY" env-default:"30s"`
+}
+
+// метрики Prometheus: путь, по которому они отдаются без токена, и таймаут запроса текущего
+// количества открытых pull request и активных пользователей при сборе метрик
+type metricsConfig struct {
+	Enabled       bool          `env:"METRICS_ENABLED" env-default:"true"`
+	Path          string        `env:"METRICS_PATH" env-default:"/metrics"`
+	CountsTimeout time.Duration `env:"METRICS_COUNTS_TIMEOUT" env-default:"2s"`
+}
+
+// трассировка OpenTelemetry: экспортер (none, otlp, stdout или file) и его параметры, имя сервиса
+// в ресурсе спанов и доля трассировок, которые записываются, если вызывающий не передал решение
+// в заголовке traceparent
+type tracingConfig struct {
+	Exporter     string  `env:"TRACING_EXPORTER" env-default:"none"`
+	OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" env-default:"localhost:4318"`
+	OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" env-default:"true"`
+	FilePath     string  `env:"TRACING_FILE_PATH" env-default:"traces.jsonl"`
+	ServiceName  string  `env:"TRACING_SERVICE_NAME" env-default:"pr-reviewer"`
+	SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
 func MustLoad() (*Config, error) {
 
 	var cfg Config
@@ -6,30 +6,41 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPostgresDB(ctx context.Context, cfg *config.Config) (*pgx.Conn, error) {
+// tracer получает все запросы пула (метрики длительности и спаны), nil - запросы не отслеживаются
+func NewPostgresDB(ctx context.Context, cfg *config.Config, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
 	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=%s",
 		cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.Host, cfg.Postgres.Port,
 		cfg.Postgres.Name, cfg.Postgres.SSLMode)
 
-	conn, err := pgx.Connect(ctx, dsn)
+	poolCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("[NewPostgresDB]: %w", err)
 	}
 
-	if err := conn.Ping(ctx); err != nil {
+	// настройки пула соединений
+	poolCfg.MaxConns = cfg.Postgres.MaxConns
+	poolCfg.MinConns = cfg.Postgres.MinConns
+	poolCfg.MaxConnLifetime = cfg.Postgres.MaxConnLifetime
+	poolCfg.MaxConnIdleTime = cfg.Postgres.MaxConnIdleTime
+	poolCfg.HealthCheckPeriod = cfg.Postgres.HealthCheckPeriod
+	poolCfg.ConnConfig.Tracer = tracer
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
 		return nil, fmt.Errorf("[NewPostgresDB]: %w", err)
 	}
 
-	return conn, nil
-}
-
-func ClosePostgresDB(ctx context.Context, conn *pgx.Conn) error {
-	err := conn.Close(ctx)
-	if err != nil {
-		return fmt.Errorf("[ClosePostgresDB]: %w", err)
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("[NewPostgresDB]: %w", err)
 	}
 
-	return nil
+	return pool, nil
+}
+
+func ClosePostgresDB(pool *pgxpool.Pool) {
+	pool.Close()
 }
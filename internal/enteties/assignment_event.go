@@ -0,0 +1,41 @@
+package enteties
+
+import "time"
+
+type AssignmentEventType string
+
+const (
+	AssignmentEventAssign   AssignmentEventType = "ASSIGN"
+	AssignmentEventReassign AssignmentEventType = "REASSIGN"
+	AssignmentEventUnassign AssignmentEventType = "UNASSIGN"
+)
+
+// причины изменения ревьюеров, которые записываются в историю назначений
+const (
+	AssignmentReasonPRCreated       = "pr_created"
+	AssignmentReasonManualReassign  = "manual_reassign"
+	AssignmentReasonUserDeactivated = "user_deactivated"
+	AssignmentReasonRemovedFromTeam = "removed_from_team"
+	AssignmentReasonMovedToTeam     = "moved_to_another_team"
+	AssignmentReasonTeamDeleted     = "team_deleted"
+	AssignmentReasonPRReopened      = "pr_reopened"
+	AssignmentReasonPRReady         = "pr_ready_for_review"
+)
+
+// модель описывает событие истории назначения ревьюеров на pull request
+type AssignmentEvent struct {
+	EventID       int64               `json:"event_id"`
+	PullRequestID string              `json:"pull_request_id"`
+	EventType     AssignmentEventType `json:"event_type"`
+	OldUserID     string              `json:"old_user_id,omitempty"` // снятый ревьюер (REASSIGN, UNASSIGN)
+	NewUserID     string              `json:"new_user_id,omitempty"` // назначенный ревьюер (ASSIGN, REASSIGN)
+	Actor         string              `json:"actor"`
+	Reason        string              `json:"reason"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// модель описывает формат ответа на запрос истории назначений pull request
+type PullRequestHistory struct {
+	PullRequestID string            `json:"pull_request_id"`
+	Events        []AssignmentEvent `json:"events"`
+}
@@ -0,0 +1,41 @@
+package enteties
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OutboxEventType string
+
+// доменные события, которые записываются в outbox в одной транзакции с изменением состояния
+const (
+	OutboxEventPRCreated          OutboxEventType = "pr.created"
+	OutboxEventPRReady            OutboxEventType = "pr.ready"
+	OutboxEventPRMerged           OutboxEventType = "pr.merged"
+	OutboxEventPRClosed           OutboxEventType = "pr.closed"
+	OutboxEventPRReopened         OutboxEventType = "pr.reopened"
+	OutboxEventReviewerReassigned OutboxEventType = "pr.reviewer_reassigned"
+	OutboxEventReviewSubmitted    OutboxEventType = "pr.review_submitted"
+	OutboxEventTeamCreated        OutboxEventType = "team.created"
+	OutboxEventTeamMembersAdded   OutboxEventType = "team.members_added"
+	OutboxEventTeamMembersRemoved OutboxEventType = "team.members_removed"
+	OutboxEventTeamMemberMoved    OutboxEventType = "team.member_moved"
+	OutboxEventTeamDeleted        OutboxEventType = "team.deleted"
+	OutboxEventUsersDeactivated   OutboxEventType = "users.deactivated"
+	OutboxEventUserStatusChanged  OutboxEventType = "user.status_changed"
+	OutboxEventRoleGranted        OutboxEventType = "role.granted"
+	OutboxEventRoleRevoked        OutboxEventType = "role.revoked"
+	// событие для подписчиков webhook (payload - WebhookEvent), доставляется webhook сервисом
+	OutboxEventWebhook OutboxEventType = "webhook.event"
+)
+
+// модель описывает событие outbox. Payload - результат операции в том же формате, что и ответ API
+type OutboxEvent struct {
+	EventID   int64           `json:"event_id"`
+	EventType OutboxEventType `json:"event_type"`
+	Actor     string          `json:"actor"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	// номер попытки отправки, начиная с 1 (при повторе получатель может увидеть событие снова)
+	Attempt int `json:"attempt"`
+}
@@ -7,8 +7,53 @@ type PullRequestStatus string
 const (
 	PullRequestStatusOpen   PullRequestStatus = "OPEN"
 	PullRequestStatusMerged PullRequestStatus = "MERGED"
+
+	// pull request закрыт без мерджа (отклонен), может быть переоткрыт
+	PullRequestStatusClosed PullRequestStatus = "CLOSED"
+
+	// pull request команды, удаленной в режиме archive
+	PullRequestStatusArchived PullRequestStatus = "ARCHIVED"
+)
+
+// все статусы pull request в порядке вывода в отчетах
+var PullRequestStatuses = []PullRequestStatus{
+	PullRequestStatusOpen,
+	PullRequestStatusMerged,
+	PullRequestStatusClosed,
+	PullRequestStatusArchived,
+}
+
+type ReviewState string
+
+const (
+	// ревьюер назначен, но еще не принял решение
+	ReviewStatePending          ReviewState = "PENDING"
+	ReviewStateApproved         ReviewState = "APPROVED"
+	ReviewStateChangesRequested ReviewState = "CHANGES_REQUESTED"
+)
+
+// модель описывает решение ревьюера по pull request
+type ReviewerReview struct {
+	UserID     string      `json:"user_id"`
+	State      ReviewState `json:"state"`
+	ReviewedAt *time.Time  `json:"reviewed_at,omitempty"` // пустое, пока решение не принято
+}
+
+// порядок сортировки списков pull request по created_at
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
 )
 
+// позиция в списке pull request, отсортированном по created_at и pull_request_id: следующая
+// страница начинается сразу после нее
+type PullRequestCursor struct {
+	CreatedAt     time.Time
+	PullRequestID string
+}
+
 // модель описывает полную сущность pull request
 type PullRequest struct {
 	PullRequestID     string            `json:"pull_request_id"`
@@ -16,6 +61,8 @@ type PullRequest struct {
 	AuthorID          string            `json:"author_id"`
 	Status            PullRequestStatus `json:"status"`
 	AssignedReviewers []string          `json:"assigned_reviewers"` // (user_id)
+	IsDraft           bool              `json:"is_draft,omitempty"` // черновик без ревьюеров
+	Reviews           []ReviewerReview  `json:"reviews,omitempty"`  // решения назначенных ревьюеров
 	CreatedAt         *time.Time        `json:"created_at,omitempty"`
 	MergedAt          *time.Time        `json:"merged_at,omitempty"`
 }
@@ -26,6 +73,8 @@ type PullRequestShort struct {
 	PulRequestName string            `json:"pull_request_name"`
 	AuthorID       string            `json:"author_id"`
 	Status         PullRequestStatus `json:"status"`
+	IsDraft        bool              `json:"is_draft,omitempty"`
+	CreatedAt      *time.Time        `json:"created_at,omitempty"`
 }
 
 // модель описывает формат запроса на создание pull request
@@ -33,6 +82,19 @@ type CreatePullRequest struct {
 	PullRequestID   string `json:"pull_request_id" validate:"required"`
 	PullRequestName string `json:"pull_request_name" validate:"required"`
 	AuthorID        string `json:"author_id" validate:"required"`
+	IsDraft         bool   `json:"is_draft"` // черновик создается без ревьюеров
+}
+
+// модель описывает формат запроса на перевод черновика pull request в готовый к ревью
+type MarkReadyPullRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+}
+
+// модель описывает формат запроса на решение ревьюера по pull request
+type ReviewPullRequest struct {
+	PullRequestID string      `json:"pull_request_id" validate:"required"`
+	UserID        string      `json:"user_id" validate:"required"`
+	State         ReviewState `json:"state" validate:"required,oneof=APPROVED CHANGES_REQUESTED"`
 }
 
 // модель описывает формат запроса на мердж pull request
@@ -40,14 +102,65 @@ type MergePullRequest struct {
 	PullRequestID string `json:"pull_request_id" validate:"required"`
 }
 
+// модель описывает формат запроса на закрытие pull request без мерджа
+type ClosePullRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+}
+
+// модель описывает формат запроса на повторное открытие закрытого pull request
+type ReopenPullRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+}
+
+// модель описывает формат ответа на повторное открытие pull request: неактивные ревьюеры
+// заменяются или снимаются без замены
+type ReopenPullRequestResponce struct {
+	PR               PullRequest           `json:"pr"`
+	Reassigned       []ReviewerReplacement `json:"reassigned"`
+	WithoutCandidate []ReviewerReplacement `json:"without_candidate"`
+}
+
 // модель описывает формат запроса на переназанчение ревьюера на pull request
 type ReassignPullRequest struct {
 	PullRequestID string `json:"pull_request_id" validate:"required"`
 	OldUserID     string `json:"old_user_id" validate:"required"`
+	Reason        string `json:"reason,omitempty" validate:"max=255"` // причина для истории назначений
 }
 
 // модель описывает формат ответа на запрос о переназначении ревьюера на pull request
 type ReassignPullRequestResponce struct {
 	PR         PullRequest `json:"pr"`
-	ReplacedBy string      `json:"replaced_by"`
+	ReplacedBy string      `json:"replaced_by"` // пустой, если ревьюер снят без замены
+}
+
+// модель описывает замену ревьюера на pull request при изменении состава команды
+type ReviewerReplacement struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	ReplacedBy    string `json:"replaced_by"` // пустой, если подходящего кандидата не нашлось
+}
+
+// модель описывает параметры поиска pull request. Все фильтры необязательные и объединяются
+// через И. Pull request сортируются по created_at (при равенстве по pull_request_id) и отдаются
+// страницами по Limit штук, следующая страница запрашивается по курсору из ответа
+type PullRequestListFilter struct {
+	Status     PullRequestStatus `json:"status,omitempty" validate:"omitempty,oneof=OPEN MERGED CLOSED ARCHIVED"`
+	AuthorID   string            `json:"author_id,omitempty"`
+	TeamName   string            `json:"team_name,omitempty"`   // команда автора
+	ReviewerID string            `json:"reviewer_id,omitempty"` // назначенный ревьюер
+	Name       string            `json:"name,omitempty"`        // подстрока названия, без учета регистра
+	// created_at и merged_at в полуинтервалах [from, to)
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+	MergedFrom  *time.Time `json:"merged_from,omitempty"`
+	MergedTo    *time.Time `json:"merged_to,omitempty"`
+	Sort        SortOrder  `json:"sort,omitempty" validate:"omitempty,oneof=asc desc"`
+	Limit       int        `json:"limit,omitempty" validate:"gte=0,lte=100"` // 0 - размер страницы по умолчанию
+	Cursor      string     `json:"cursor,omitempty"`
+}
+
+// модель описывает формат ответа на поиск pull request
+type PullRequestList struct {
+	PullRequests []PullRequestShort `json:"pull_requests"`
+	NextCursor   string             `json:"next_cursor,omitempty"` // пустой на последней странице
 }
@@ -0,0 +1,39 @@
+package enteties
+
+import "time"
+
+type Role string
+
+// роли пользователей. member - роль по умолчанию, она не хранится в базе данных
+const (
+	RoleAdmin    Role = "admin"
+	RoleTeamLead Role = "team_lead"
+	RoleMember   Role = "member"
+)
+
+// модель описывает роль пользователя. TeamName задан только у team_lead - команда, которой он
+// руководит. GrantedBy и GrantedAt не заданы у роли member
+type UserRole struct {
+	UserID    string     `json:"user_id"`
+	Role      Role       `json:"role"`
+	TeamName  string     `json:"team_name,omitempty"`
+	GrantedBy string     `json:"granted_by,omitempty"`
+	GrantedAt *time.Time `json:"granted_at,omitempty"`
+}
+
+// модель описывает формат запроса на выдачу роли. team_name обязателен для team_lead
+type GrantRole struct {
+	UserID   string `json:"user_id" validate:"required"`
+	Role     Role   `json:"role" validate:"required,oneof=admin team_lead"`
+	TeamName string `json:"team_name" validate:"required_if=Role team_lead,excluded_unless=Role team_lead"`
+}
+
+// модель описывает формат запроса на отзыв роли (пользователь становится member)
+type RevokeRole struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+// модель описывает формат ответа на запрос выданных ролей
+type UserRoles struct {
+	Roles []UserRole `json:"roles"`
+}
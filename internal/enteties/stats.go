@@ -0,0 +1,65 @@
+package enteties
+
+import "time"
+
+// модель описывает необязательные фильтры статистики по ревьюерам
+type ReviewerStatsFilter struct {
+	TeamName string     `json:"team_name,omitempty"`
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+}
+
+// модель описывает статистику ревью одного пользователя
+type ReviewerStat struct {
+	UserID           string `json:"user_id"`
+	UserName         string `json:"username"`
+	TeamName         string `json:"team_name"`
+	TotalAssignments int    `json:"total_assignments"` // текущие назначения и назначения, с которых пользователь снят
+	OpenReviews      int    `json:"open"`
+	MergedReviews    int    `json:"merged"`
+	ReassignedAway   int    `json:"reassigned_away"`
+}
+
+// модель описывает формат ответа на запрос статистики по ревьюерам
+type ReviewerStats struct {
+	ReviewerStatsFilter
+	Reviewers []ReviewerStat `json:"reviewers"`
+}
+
+type PullRequestStatsGroupBy string
+
+const (
+	PullRequestStatsGroupByTeam   PullRequestStatsGroupBy = "team"
+	PullRequestStatsGroupByAuthor PullRequestStatsGroupBy = "author"
+	PullRequestStatsGroupByWeek   PullRequestStatsGroupBy = "week" // неделя создания pull request
+)
+
+// модель описывает параметры статистики по pull request
+type PullRequestStatsFilter struct {
+	GroupBy  PullRequestStatsGroupBy `json:"group_by" validate:"required,oneof=team author week"`
+	TeamName string                  `json:"team_name,omitempty"`
+	From     *time.Time              `json:"from,omitempty"`
+	To       *time.Time              `json:"to,omitempty"`
+}
+
+// модель описывает статистику pull request одной группы (команды, автора или недели)
+type PullRequestGroupStats struct {
+	Group             string                    `json:"group"`
+	Total             int                       `json:"total"`
+	StatusCounts      map[PullRequestStatus]int `json:"status_counts"`
+	MedianTimeToMerge *float64                  `json:"median_time_to_merge_seconds"` // nil, если в группе нет MERGED
+	P90TimeToMerge    *float64                  `json:"p90_time_to_merge_seconds"`
+}
+
+// модель описывает формат ответа на запрос статистики по pull request
+type PullRequestStats struct {
+	PullRequestStatsFilter
+	StatusCounts map[PullRequestStatus]int `json:"status_counts"`
+	Groups       []PullRequestGroupStats   `json:"groups"`
+}
+
+// модель описывает текущее состояние сервиса для метрик
+type CurrentCounts struct {
+	OpenPullRequests int `json:"open_pull_requests"`
+	ActiveUsers      int `json:"active_users"`
+}
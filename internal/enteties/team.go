@@ -5,3 +5,72 @@ type Team struct {
 	TeamName string       `json:"team_name" validate:"required"`
 	Members  []TeamMember `json:"members" validate:"required"`
 }
+
+// модель описывает настройки команды (минимальное и максимальное количество ревьюеров)
+type TeamSettings struct {
+	TeamName     string `json:"team_name"`
+	MinReviewers int    `json:"min_reviewers"`
+	MaxReviewers int    `json:"max_reviewers"`
+}
+
+// модель описывает формат запроса на изменение настроек команды
+type UpdateTeamSettings struct {
+	TeamName     string `json:"team_name" validate:"required"`
+	MinReviewers *int   `json:"min_reviewers" validate:"required,gte=0"`
+	MaxReviewers *int   `json:"max_reviewers" validate:"required,gte=0"`
+}
+
+// модель описывает формат запроса на добавление участников в существующую команду
+type AddTeamMembers struct {
+	TeamName string       `json:"team_name" validate:"required"`
+	Members  []TeamMember `json:"members" validate:"required,min=1"`
+}
+
+// модель описывает формат запроса на исключение участников из команды
+type RemoveTeamMembers struct {
+	TeamName string   `json:"team_name" validate:"required"`
+	UsersID  []string `json:"user_ids" validate:"required,min=1,unique,dive,required"` // повторы запрещены
+}
+
+// модель описывает формат ответа на запрос об исключении участников из команды
+type RemoveTeamMembersResponce struct {
+	Team       Team                  `json:"team"`
+	Reassigned []ReviewerReplacement `json:"reassigned"`
+}
+
+// модель описывает формат запроса на перевод пользователя в другую команду
+type MoveTeamMember struct {
+	UserID   string `json:"user_id" validate:"required"`
+	TeamName string `json:"team_name" validate:"required"`
+}
+
+// модель описывает формат ответа на запрос о переводе пользователя в другую команду
+type MoveTeamMemberResponce struct {
+	User       User                  `json:"user"`
+	Reassigned []ReviewerReplacement `json:"reassigned"`
+}
+
+// режимы удаления команды
+const (
+	// отказать в удалении, если у участников есть OPEN pull request или OPEN ревью
+	DeleteTeamModeRefuse = "refuse"
+	// переназначить OPEN ревью участников на пользователей других команд
+	DeleteTeamModeReassign = "reassign"
+	// перевести OPEN pull request участников в ARCHIVED и снять участников с остальных ревью
+	DeleteTeamModeArchive = "archive"
+)
+
+// модель описывает формат запроса на удаление команды
+type DeleteTeam struct {
+	TeamName string `json:"team_name" validate:"required"`
+	Mode     string `json:"mode" validate:"omitempty,oneof=refuse reassign archive"`
+}
+
+// модель описывает формат ответа на запрос об удалении команды
+type DeleteTeamResponce struct {
+	TeamName       string                `json:"team_name"`
+	Mode           string                `json:"mode"`
+	RemovedMembers []string              `json:"removed_members"`        // user_id бывших участников
+	ArchivedPRs    []string              `json:"archived_pull_requests"` // pull_request_id
+	Reassigned     []ReviewerReplacement `json:"reassigned"`
+}
@@ -1,5 +1,7 @@
 package enteties
 
+import "time"
+
 // модель описывает полную сущность пользователя (User)
 type User struct {
 	UserID   string `json:"user_id"`
@@ -11,14 +13,49 @@ type User struct {
 // модель описывает формат запроса для изменения статуса пользователя
 type RequestUserToSetActive struct {
 	UserID   string `json:"user_id" validate:"required"`
-	IsActive bool   `json:"is_active" validate:"required"`
+	IsActive *bool  `json:"is_active" validate:"required"` // указатель, чтобы false проходил валидацию
+}
+
+// модель описывает формат запроса на массовую деактивацию пользователей
+type DeactivateUsers struct {
+	UsersID []string `json:"user_ids" validate:"required,min=1,unique,dive,required"` // повторы запрещены
+}
+
+// модель описывает формат ответа на запрос о массовой деактивации пользователей
+type DeactivateUsersResponce struct {
+	DeactivatedUsers []string              `json:"deactivated_users"`
+	Reassigned       []ReviewerReplacement `json:"reassigned"`
+	WithoutCandidate []ReviewerReplacement `json:"without_candidate"` // ревьюер снят, замены не нашлось
+}
+
+// модель описывает формат запроса для изменения username пользователя
+type RequestUserToSetUsername struct {
+	UserID   string `json:"user_id" validate:"required"`
+	UserName string `json:"username" validate:"required"`
 }
 
 // модель описывает члена команды
 type TeamMember struct {
 	UserID   string `json:"user_id" validate:"required"`
 	UserName string `json:"username" validate:"required"`
-	IsActive bool   `json:"is_active" validate:"required"`
+	IsActive bool   `json:"is_active"` // наличие поля проверяется по OpenAPI спецификации
+}
+
+// модель описывает параметры запроса pull request, на которые пользователь назначен ревьюером.
+// Pull request сортируются по created_at (при равенстве по pull_request_id) и отдаются страницами
+// по Limit штук, следующая страница запрашивается по курсору из ответа
+type UserReviewsFilter struct {
+	UserID string `json:"user_id" validate:"required"`
+	// по умолчанию черновики не возвращаются
+	IncludeDrafts bool `json:"include_drafts"`
+	// по умолчанию возвращаются все статусы, кроме CLOSED
+	Status   PullRequestStatus `json:"status,omitempty" validate:"omitempty,oneof=OPEN MERGED CLOSED ARCHIVED"`
+	AuthorID string            `json:"author_id,omitempty"`
+	From     *time.Time        `json:"from,omitempty"` // created_at, включительно
+	To       *time.Time        `json:"to,omitempty"`   // created_at, не включительно
+	Sort     SortOrder         `json:"sort,omitempty" validate:"omitempty,oneof=asc desc"`
+	Limit    int               `json:"limit,omitempty" validate:"gte=0,lte=100"` // 0 - размер страницы по умолчанию
+	Cursor   string            `json:"cursor,omitempty"`
 }
 
 // модель описывает формат запроса на получение всех pull request, на которые пользователь
@@ -26,4 +63,5 @@ type TeamMember struct {
 type UserReviews struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
+	NextCursor   string             `json:"next_cursor,omitempty"` // пустой на последней странице
 }
@@ -0,0 +1,75 @@
+package enteties
+
+import "time"
+
+type WebhookEventType string
+
+const (
+	WebhookEventPRCreated          WebhookEventType = "pr.created"
+	WebhookEventReviewerAssigned   WebhookEventType = "reviewer.assigned"
+	WebhookEventReviewerReassigned WebhookEventType = "reviewer.reassigned"
+	WebhookEventPRMerged           WebhookEventType = "pr.merged"
+)
+
+// модель описывает подписку команды на события pull request
+type WebhookSubscription struct {
+	SubscriptionID int64              `json:"subscription_id"`
+	TeamName       string             `json:"team_name"`
+	URL            string             `json:"url"`
+	Secret         string             `json:"-"` // секрет подписи не возвращается в ответах
+	EventTypes     []WebhookEventType `json:"event_types"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// модель описывает формат запроса на создание подписки
+type CreateWebhookSubscription struct {
+	TeamName   string             `json:"team_name" validate:"required"`
+	URL        string             `json:"url" validate:"required,http_url,max=2048"`
+	Secret     string             `json:"secret" validate:"required,min=16,max=255"`
+	EventTypes []WebhookEventType `json:"event_types" validate:"required,min=1,dive,oneof=pr.created reviewer.assigned reviewer.reassigned pr.merged"`
+}
+
+// модель описывает формат запроса на удаление подписки
+type DeleteWebhookSubscription struct {
+	SubscriptionID int64 `json:"subscription_id" validate:"required,gt=0"`
+}
+
+// модель описывает формат ответа на запрос подписок команды
+type TeamWebhooks struct {
+	TeamName      string                `json:"team_name"`
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+// модель описывает событие, которое отправляется подписчикам в теле запроса
+type WebhookEvent struct {
+	EventID     string           `json:"event_id"`
+	EventType   WebhookEventType `json:"event_type"`
+	TeamName    string           `json:"team_name"` // команда автора pull request
+	Actor       string           `json:"actor"`
+	OccurredAt  time.Time        `json:"occurred_at"`
+	PullRequest PullRequest      `json:"pull_request"`
+	// reviewer.assigned - назначенный ревьюер
+	ReviewerID string `json:"reviewer_id,omitempty"`
+	// reviewer.reassigned - заменяемый ревьюер и замена (пустая, если ревьюер снят без замены)
+	OldReviewerID string `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+}
+
+// модель описывает одну попытку доставки события подписчику
+type WebhookDelivery struct {
+	DeliveryID     int64            `json:"delivery_id"`
+	SubscriptionID int64            `json:"subscription_id"`
+	EventID        string           `json:"event_id"`
+	EventType      WebhookEventType `json:"event_type"`
+	Attempt        int              `json:"attempt"`
+	StatusCode     int              `json:"status_code,omitempty"` // 0, если ответ не получен
+	Error          string           `json:"error,omitempty"`
+	Success        bool             `json:"success"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// модель описывает формат ответа на запрос журнала доставок подписки
+type WebhookDeliveries struct {
+	SubscriptionID int64             `json:"subscription_id"`
+	Deliveries     []WebhookDelivery `json:"deliveries"`
+}
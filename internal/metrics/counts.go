@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CountsSource возвращает текущее состояние хранилища (repository.StatsRepository)
+type CountsSource interface {
+	GetCurrentCounts(ctx context.Context) (*enteties.CurrentCounts, error)
+}
+
+// countsCollector при каждом сборе метрик запрашивает количество OPEN pull request и активных
+// пользователей. Если хранилище не ответило за timeout, значения в этом сборе не отдаются
+type countsCollector struct {
+	source  CountsSource
+	timeout time.Duration
+	log     *slog.Logger
+
+	openPRs     *prometheus.Desc
+	activeUsers *prometheus.Desc
+}
+
+// RegisterCounts добавляет gauge метрики открытых pull request и активных пользователей
+func (m *Metrics) RegisterCounts(source CountsSource, timeout time.Duration, log *slog.Logger) error {
+	return m.Registry.Register(&countsCollector{
+		source:  source,
+		timeout: timeout,
+		log:     log,
+		openPRs: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "open_pull_requests"),
+			"Pull requests in OPEN status, including drafts.", nil, nil),
+		activeUsers: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_users"),
+			"Users with is_active set.", nil, nil),
+	})
+}
+
+func (cc *countsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cc.openPRs
+	ch <- cc.activeUsers
+}
+
+func (cc *countsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), cc.timeout)
+	defer cancel()
+
+	counts, err := cc.source.GetCurrentCounts(ctx)
+	if err != nil {
+		cc.log.Warn("failed collect current counts", "error", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(cc.openPRs, prometheus.GaugeValue, float64(counts.OpenPullRequests))
+	ch <- prometheus.MustNewConstMetric(cc.activeUsers, prometheus.GaugeValue, float64(counts.ActiveUsers))
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// операции SQL, которые попадают в метки как есть, остальные учитываются как other
+var knownOperations = map[string]bool{
+	"select":   true,
+	"insert":   true,
+	"update":   true,
+	"delete":   true,
+	"with":     true,
+	"begin":    true,
+	"commit":   true,
+	"rollback": true,
+}
+
+// первая таблица запроса: после FROM, INTO или UPDATE
+var tablePattern = regexp.MustCompile(`(?i)\b(?:from|into|update)\s+([a-z_][a-z0-9_]*)`)
+
+type queryStartKey struct{}
+
+// QueryTracer измеряет длительность запросов pgx (одиночных и пакетов SendBatch).
+// Подключается к пулу через pgxpool.Config.ConnConfig.Tracer
+type QueryTracer struct {
+	Metrics *Metrics
+}
+
+func NewQueryTracer(m *Metrics) *QueryTracer {
+	return &QueryTracer{
+		Metrics: m,
+	}
+}
+
+// запрос и время его начала передаются из TraceQueryStart в TraceQueryEnd через контекст
+type queryStart struct {
+	sql   string
+	start time.Time
+}
+
+func (qt *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, start: time.Now()})
+}
+
+func (qt *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	started, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	qt.Metrics.ObserveDBQuery(started.sql, data.Err, time.Since(started.start))
+}
+
+func (qt *QueryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: "batch", start: time.Now()})
+}
+
+func (qt *QueryTracer) TraceBatchQuery(context.Context, *pgx.Conn, pgx.TraceBatchQueryData) {}
+
+func (qt *QueryTracer) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchEndData) {
+	qt.TraceQueryEnd(ctx, conn, pgx.TraceQueryEndData{Err: data.Err})
+}
+
+// вспомогательная функция определяет по тексту запроса метки операции и таблицы. Текст запроса
+// в метки не попадает: списки IN с разным количеством параметров дают разные запросы
+func queryLabels(sql string) (operation, table string) {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "other", ""
+	}
+
+	operation = strings.ToLower(fields[0])
+	if operation == "batch" {
+		return operation, ""
+	}
+	if !knownOperations[operation] {
+		operation = "other"
+	}
+
+	if match := tablePattern.FindStringSubmatch(sql); match != nil {
+		table = strings.ToLower(match[1])
+	}
+
+	return operation, table
+}
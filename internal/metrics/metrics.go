@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"avito_intern/internal/enteties"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// префикс имен метрик сервиса
+const namespace = "pr_reviewer"
+
+// причины замены ревьюеров, которые попадают в метки как есть. Причина ReassignPR задается в
+// запросе произвольным текстом, поэтому остальные причины учитываются как other
+var knownReassignReasons = map[string]bool{
+	enteties.AssignmentReasonManualReassign:  true,
+	enteties.AssignmentReasonUserDeactivated: true,
+	enteties.AssignmentReasonRemovedFromTeam: true,
+	enteties.AssignmentReasonMovedToTeam:     true,
+	enteties.AssignmentReasonTeamDeleted:     true,
+	enteties.AssignmentReasonPRReopened:      true,
+}
+
+// Metrics хранит метрики сервиса в собственном реестре: HTTP запросы, запросы к базе данных,
+// бизнес-счетчики (реализует service.Metrics) и текущие значения из хранилища
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	dbDuration   *prometheus.HistogramVec
+
+	prsCreated          prometheus.Counter
+	reviewersAssigned   prometheus.Counter
+	reviewersReassigned *prometheus.CounterVec
+	noCandidate         prometheus.Counter
+	prsMerged           prometheus.Counter
+}
+
+// New создает метрики и регистрирует их вместе с метриками Go runtime и процесса
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and response status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and response status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latency by SQL operation, table and result.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table", "status"}),
+
+		prsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_created_total",
+			Help:      "Pull requests created.",
+		}),
+		reviewersAssigned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewers_assigned_total",
+			Help:      "Reviewers assigned to new or ready for review pull requests.",
+		}),
+		reviewersReassigned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_reassignments_total",
+			Help:      "Reviewers replaced by another team member, by reason.",
+		}, []string{"reason"}),
+		noCandidate: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reassign_no_candidate_total",
+			Help:      "Reassign requests rejected with NO_CANDIDATE.",
+		}),
+		prsMerged: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_merged_total",
+			Help:      "Pull requests merged.",
+		}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.dbDuration,
+		m.prsCreated,
+		m.reviewersAssigned,
+		m.reviewersReassigned,
+		m.noCandidate,
+		m.prsMerged,
+	)
+
+	return m
+}
+
+// Handler отдает метрики реестра в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest учитывает обработанный HTTP запрос. route - шаблон пути, а не путь запроса,
+// чтобы количество меток не зависело от параметров
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveDBQuery учитывает выполненный запрос к базе данных
+func (m *Metrics) ObserveDBQuery(sql string, err error, duration time.Duration) {
+	operation, table := queryLabels(sql)
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	m.dbDuration.WithLabelValues(operation, table, status).Observe(duration.Seconds())
+}
+
+func (m *Metrics) PRCreated() {
+	m.prsCreated.Inc()
+}
+
+func (m *Metrics) ReviewersAssigned(count int) {
+	m.reviewersAssigned.Add(float64(count))
+}
+
+func (m *Metrics) ReviewersReassigned(reason string, count int) {
+	if !knownReassignReasons[reason] {
+		reason = "other"
+	}
+
+	m.reviewersReassigned.WithLabelValues(reason).Add(float64(count))
+}
+
+func (m *Metrics) NoCandidate() {
+	m.noCandidate.Inc()
+}
+
+func (m *Metrics) PRMerged() {
+	m.prsMerged.Inc()
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryLabels(t *testing.T) {
+	tests := []struct {
+		Name      string
+		SQL       string
+		Operation string
+		Table     string
+	}{
+		{Name: "select", SQL: "SELECT user_id FROM users WHERE team_name = $1", Operation: "select", Table: "users"},
+		{Name: "select_exists", SQL: "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", Operation: "select", Table: "pull_requests"},
+		{Name: "insert", SQL: "INSERT INTO assigned_reviewers (pull_request_id,user_id) VALUES ($1,$2)", Operation: "insert", Table: "assigned_reviewers"},
+		{Name: "update_lowercase", SQL: "update users set is_active = $1", Operation: "update", Table: "users"},
+		{Name: "delete", SQL: "\n\tDELETE FROM user_roles WHERE user_id = $1", Operation: "delete", Table: "user_roles"},
+		{Name: "transaction", SQL: "begin isolation level read committed", Operation: "begin", Table: ""},
+		{Name: "unknown_operation", SQL: "LOCK TABLE users", Operation: "other", Table: ""},
+		{Name: "empty", SQL: "  ", Operation: "other", Table: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			operation, table := queryLabels(tt.SQL)
+
+			assert.Equal(t, tt.Operation, operation)
+			assert.Equal(t, tt.Table, table)
+		})
+	}
+}
+
+type countsSourceFunc func(ctx context.Context) (*enteties.CurrentCounts, error)
+
+func (f countsSourceFunc) GetCurrentCounts(ctx context.Context) (*enteties.CurrentCounts, error) {
+	return f(ctx)
+}
+
+// вспомогательная функция возвращает метрики в текстовом формате Prometheus
+func scrape(t *testing.T, m *Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	require.Equal(t, 200, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestMetrics_Counts(t *testing.T) {
+	m := New()
+
+	var countsErr error
+	err := m.RegisterCounts(countsSourceFunc(func(ctx context.Context) (*enteties.CurrentCounts, error) {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+
+		if countsErr != nil {
+			return nil, countsErr
+		}
+		return &enteties.CurrentCounts{OpenPullRequests: 3, ActiveUsers: 7}, nil
+	}), time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, "pr_reviewer_open_pull_requests 3\n")
+	assert.Contains(t, body, "pr_reviewer_active_users 7\n")
+
+	// ошибка хранилища не ломает сбор остальных метрик
+	countsErr = errors.New("connection refused")
+	m.PRCreated()
+
+	body = scrape(t, m)
+	assert.NotContains(t, body, "pr_reviewer_open_pull_requests ")
+	assert.Contains(t, body, "pr_reviewer_pull_requests_created_total 1\n")
+}
+
+func TestMetrics_Business(t *testing.T) {
+	m := New()
+
+	m.PRCreated()
+	m.ReviewersAssigned(2)
+	m.ReviewersAssigned(0)
+	m.ReviewersReassigned(enteties.AssignmentReasonManualReassign, 1)
+	m.ReviewersReassigned(enteties.AssignmentReasonUserDeactivated, 3)
+	m.ReviewersReassigned("vacation", 2)
+	m.NoCandidate()
+	m.PRMerged()
+	m.ObserveDBQuery("SELECT 1 FROM users", nil, time.Millisecond)
+	m.ObserveDBQuery("UPDATE users SET is_active = $1", errors.New("deadlock"), time.Millisecond)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, "pr_reviewer_pull_requests_created_total 1\n")
+	assert.Contains(t, body, "pr_reviewer_reviewers_assigned_total 2\n")
+	assert.Contains(t, body, `pr_reviewer_reviewer_reassignments_total{reason="manual_reassign"} 1`)
+	assert.Contains(t, body, `pr_reviewer_reviewer_reassignments_total{reason="user_deactivated"} 3`)
+	// причина из запроса ReassignPR произвольная, в метку попадает other
+	assert.Contains(t, body, `pr_reviewer_reviewer_reassignments_total{reason="other"} 2`)
+	assert.NotContains(t, body, "vacation")
+	assert.Contains(t, body, "pr_reviewer_reassign_no_candidate_total 1\n")
+	assert.Contains(t, body, "pr_reviewer_pull_requests_merged_total 1\n")
+	assert.Contains(t, body, `pr_reviewer_db_query_duration_seconds_count{operation="select",status="ok",table="users"} 1`)
+	assert.Contains(t, body, `pr_reviewer_db_query_duration_seconds_count{operation="update",status="error",table="users"} 1`)
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	errMemoryNotFound     = errors.New("record not found")
+	errMemoryForeignKey   = errors.New("referenced record not found")
+	errMemoryInvalidValue = errors.New("value violates check constraint")
+)
+
+const memoryTxKey contextKey = "memory_tx"
+
+// данные in-memory хранилища, повторяют таблицы базы данных
+type memoryData struct {
+	teams        map[string]struct{}
+	teamSettings map[string]enteties.TeamSettings
+	users        map[string]enteties.User // пустой TeamName - пользователь не состоит в команде
+	prs          map[string]enteties.PullRequest
+	reviews      map[reviewKey]enteties.ReviewerReview // только принятые решения, остальные PENDING
+	events       []enteties.AssignmentEvent
+	lastEventID  int64
+
+	webhooks       map[int64]enteties.WebhookSubscription
+	lastWebhookID  int64
+	deliveries     []enteties.WebhookDelivery
+	lastDeliveryID int64
+
+	outbox       []memoryOutboxEvent // в порядке id
+	lastOutboxID int64
+
+	roles map[string]enteties.UserRole // только выданные роли, остальные пользователи - member
+}
+
+// ключ решения ревьюера (аналог первичного ключа assigned_reviewers)
+type reviewKey struct {
+	prID   string
+	userID string
+}
+
+// MemoryStorage общее хранилище in-memory репозиториев для тестов и локального запуска без базы
+// данных, реализует TxManager. Операции репозиториев и транзакции выполняются по одной под общим
+// мьютексом: транзакция захватывает хранилище до завершения, а откат восстанавливает снимок
+// данных на момент ее начала
+type MemoryStorage struct {
+	mu   sync.Mutex
+	data memoryData
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		data: memoryData{
+			teams:        make(map[string]struct{}),
+			teamSettings: make(map[string]enteties.TeamSettings),
+			users:        make(map[string]enteties.User),
+			prs:          make(map[string]enteties.PullRequest),
+			reviews:      make(map[reviewKey]enteties.ReviewerReview),
+			events:       make([]enteties.AssignmentEvent, 0),
+			webhooks:     make(map[int64]enteties.WebhookSubscription),
+			deliveries:   make([]enteties.WebhookDelivery, 0),
+			outbox:       make([]memoryOutboxEvent, 0),
+			roles:        make(map[string]enteties.UserRole),
+		},
+	}
+}
+
+// транзакция in-memory хранилища
+type memoryTx struct {
+	storage  *MemoryStorage
+	snapshot memoryData
+	done     atomic.Bool
+}
+
+// транзакции выполняются по одной, поэтому изоляция всегда serializable и повторы не нужны
+func (s *MemoryStorage) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// вложенный вызов выполняется в уже открытой транзакции
+	if s.activeTx(ctx) != nil {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+
+	tx := &memoryTx{
+		storage:  s,
+		snapshot: s.data.clone(),
+	}
+	defer tx.rollback()
+
+	err := fn(context.WithValue(ctx, memoryTxKey, tx))
+	if err != nil {
+		return err
+	}
+
+	tx.commit()
+	return nil
+}
+
+func (tx *memoryTx) commit() {
+	if tx.done.CompareAndSwap(false, true) {
+		tx.storage.mu.Unlock()
+	}
+}
+
+// откатывает незавершенную транзакцию, после commit ничего не делает
+func (tx *memoryTx) rollback() {
+	if tx.done.CompareAndSwap(false, true) {
+		tx.storage.data = tx.snapshot
+		tx.storage.mu.Unlock()
+	}
+}
+
+// возвращает незавершенную транзакцию этого хранилища из контекста
+func (s *MemoryStorage) activeTx(ctx context.Context) *memoryTx {
+	tx, ok := ctx.Value(memoryTxKey).(*memoryTx)
+	if !ok || tx.storage != s || tx.done.Load() {
+		return nil
+	}
+	return tx
+}
+
+// lock захватывает хранилище на время операции репозитория и возвращает функцию освобождения.
+// Внутри транзакции хранилище уже захвачено
+func (s *MemoryStorage) lock(ctx context.Context) func() {
+	if s.activeTx(ctx) != nil {
+		return func() {}
+	}
+
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// глубокая копия данных для отката транзакции
+func (d memoryData) clone() memoryData {
+	result := memoryData{
+		teams:        make(map[string]struct{}, len(d.teams)),
+		teamSettings: make(map[string]enteties.TeamSettings, len(d.teamSettings)),
+		users:        make(map[string]enteties.User, len(d.users)),
+		prs:          make(map[string]enteties.PullRequest, len(d.prs)),
+		reviews:      make(map[reviewKey]enteties.ReviewerReview, len(d.reviews)),
+		events:       append(make([]enteties.AssignmentEvent, 0, len(d.events)), d.events...),
+		lastEventID:  d.lastEventID,
+
+		webhooks:       make(map[int64]enteties.WebhookSubscription, len(d.webhooks)),
+		lastWebhookID:  d.lastWebhookID,
+		deliveries:     append(make([]enteties.WebhookDelivery, 0, len(d.deliveries)), d.deliveries...),
+		lastDeliveryID: d.lastDeliveryID,
+
+		// записи outbox хранятся по значению, а payload не изменяется, поэтому достаточно копии среза
+		outbox:       append(make([]memoryOutboxEvent, 0, len(d.outbox)), d.outbox...),
+		lastOutboxID: d.lastOutboxID,
+
+		// время выдачи роли не изменяется после записи, поэтому указатель можно разделять
+		roles: make(map[string]enteties.UserRole, len(d.roles)),
+	}
+
+	for name := range d.teams {
+		result.teams[name] = struct{}{}
+	}
+	for name, settings := range d.teamSettings {
+		result.teamSettings[name] = settings
+	}
+	for id, user := range d.users {
+		result.users[id] = user
+	}
+	for id, pr := range d.prs {
+		pr.AssignedReviewers = append([]string{}, pr.AssignedReviewers...)
+		result.prs[id] = pr
+	}
+	for key, review := range d.reviews {
+		result.reviews[key] = review
+	}
+	for id, webhook := range d.webhooks {
+		webhook.EventTypes = append([]enteties.WebhookEventType{}, webhook.EventTypes...)
+		result.webhooks[id] = webhook
+	}
+	for id, role := range d.roles {
+		result.roles[id] = role
+	}
+
+	return result
+}
+
+// удаляет пользователя вместе с его pull request, назначениями и ролью (аналог ON DELETE CASCADE)
+func (d *memoryData) deleteUser(userID string) {
+	delete(d.users, userID)
+	delete(d.roles, userID)
+
+	for id, pr := range d.prs {
+		if pr.AuthorID == userID {
+			d.deletePR(id)
+			continue
+		}
+
+		pr.AssignedReviewers = removeString(pr.AssignedReviewers, userID)
+		d.prs[id] = pr
+		delete(d.reviews, reviewKey{prID: id, userID: userID})
+	}
+}
+
+// удаляет pull request вместе с историей назначений
+func (d *memoryData) deletePR(prID string) {
+	for _, reviewer := range d.prs[prID].AssignedReviewers {
+		delete(d.reviews, reviewKey{prID: prID, userID: reviewer})
+	}
+	delete(d.prs, prID)
+
+	events := d.events[:0]
+	for _, e := range d.events {
+		if e.PullRequestID != prID {
+			events = append(events, e)
+		}
+	}
+	d.events = events
+}
+
+// удаляет подписку вместе с журналом доставок (аналог ON DELETE CASCADE)
+func (d *memoryData) deleteWebhook(subscriptionID int64) {
+	delete(d.webhooks, subscriptionID)
+
+	deliveries := d.deliveries[:0]
+	for _, delivery := range d.deliveries {
+		if delivery.SubscriptionID != subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	d.deliveries = deliveries
+}
+
+// возвращает копию pull request, которую можно отдавать наружу
+func copyPR(pr enteties.PullRequest) *enteties.PullRequest {
+	pr.AssignedReviewers = append([]string{}, pr.AssignedReviewers...)
+	return &pr
+}
+
+// возвращает решения ревьюеров pull request в порядке назначения
+func (d *memoryData) prReviews(pr enteties.PullRequest) []enteties.ReviewerReview {
+	result := make([]enteties.ReviewerReview, 0, len(pr.AssignedReviewers))
+	for _, reviewer := range pr.AssignedReviewers {
+		review, ok := d.reviews[reviewKey{prID: pr.PullRequestID, userID: reviewer}]
+		if !ok {
+			review = enteties.ReviewerReview{UserID: reviewer, State: enteties.ReviewStatePending}
+		}
+
+		result = append(result, review)
+	}
+
+	return result
+}
+
+// возвращает pull request, отсортированные по pull_request_id
+func (d *memoryData) sortedPRs() []enteties.PullRequest {
+	result := make([]enteties.PullRequest, 0, len(d.prs))
+	for _, pr := range d.prs {
+		result = append(result, pr)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PullRequestID < result[j].PullRequestID
+	})
+
+	return result
+}
+
+// возвращает пользователей, отсортированных по user_id
+func (d *memoryData) sortedUsers() []enteties.User {
+	result := make([]enteties.User, 0, len(d.users))
+	for _, user := range d.users {
+		result = append(result, user)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UserID < result[j].UserID
+	})
+
+	return result
+}
+
+func removeString(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// текущее время хранилища (аналог NOW() в базе данных)
+func memoryNow() time.Time {
+	return time.Now().UTC()
+}
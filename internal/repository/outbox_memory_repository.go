@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// запись таблицы outbox in-memory хранилища
+type memoryOutboxEvent struct {
+	event       enteties.OutboxEvent
+	availableAt time.Time
+	deliveredAt *time.Time
+	lastError   string
+}
+
+type outboxMemoryRepository struct {
+	Storage *MemoryStorage
+}
+
+func NewOutboxMemoryRepository(storage *MemoryStorage) *outboxMemoryRepository {
+	return &outboxMemoryRepository{
+		Storage: storage,
+	}
+}
+
+func (omr *outboxMemoryRepository) AddEvent(ctx context.Context, event *enteties.OutboxEvent) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.AddEvent")
+	defer span.End()
+
+	unlock := omr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &omr.Storage.data
+
+	now := memoryNow()
+	data.lastOutboxID++
+	data.outbox = append(data.outbox, memoryOutboxEvent{
+		event: enteties.OutboxEvent{
+			EventID:   data.lastOutboxID,
+			EventType: event.EventType,
+			Actor:     event.Actor,
+			Payload:   append(json.RawMessage{}, event.Payload...),
+			CreatedAt: now,
+		},
+		availableAt: now,
+	})
+
+	return nil
+}
+
+func (omr *outboxMemoryRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]enteties.OutboxEvent, error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.ClaimEvents")
+	defer span.End()
+
+	unlock := omr.Storage.lock(ctx)
+	defer unlock()
+
+	now := memoryNow()
+	result := make([]enteties.OutboxEvent, 0)
+
+	// записи хранятся в порядке id
+	for i := range omr.Storage.data.outbox {
+		if len(result) == limit {
+			break
+		}
+
+		record := &omr.Storage.data.outbox[i]
+		if record.deliveredAt != nil || record.availableAt.After(now) {
+			continue
+		}
+
+		record.event.Attempt++
+		record.availableAt = now.Add(lease)
+
+		event := record.event
+		event.Payload = append(json.RawMessage{}, event.Payload...)
+		result = append(result, event)
+	}
+
+	return result, nil
+}
+
+func (omr *outboxMemoryRepository) MarkDelivered(ctx context.Context, eventIDs []int64) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkDelivered")
+	defer span.End()
+
+	unlock := omr.Storage.lock(ctx)
+	defer unlock()
+
+	now := memoryNow()
+	omr.Storage.data.updateOutbox(eventIDs, func(record *memoryOutboxEvent) {
+		record.deliveredAt = &now
+		record.lastError = ""
+	})
+
+	return nil
+}
+
+func (omr *outboxMemoryRepository) MarkFailed(ctx context.Context, eventIDs []int64, retryDelay time.Duration,
+	lastError string) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkFailed")
+	defer span.End()
+
+	unlock := omr.Storage.lock(ctx)
+	defer unlock()
+
+	availableAt := memoryNow().Add(retryDelay)
+	omr.Storage.data.updateOutbox(eventIDs, func(record *memoryOutboxEvent) {
+		record.availableAt = availableAt
+		record.lastError = lastError
+	})
+
+	return nil
+}
+
+func (omr *outboxMemoryRepository) DeleteDelivered(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.DeleteDelivered")
+	defer span.End()
+
+	unlock := omr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &omr.Storage.data
+	before := memoryNow().Add(-olderThan)
+
+	var deleted int64
+	outbox := data.outbox[:0]
+	for _, record := range data.outbox {
+		if record.deliveredAt != nil && record.deliveredAt.Before(before) {
+			deleted++
+			continue
+		}
+
+		outbox = append(outbox, record)
+	}
+	data.outbox = outbox
+
+	return deleted, nil
+}
+
+// применяет update к записям outbox с заданными id
+func (d *memoryData) updateOutbox(eventIDs []int64, update func(record *memoryOutboxEvent)) {
+	ids := make(map[int64]struct{}, len(eventIDs))
+	for _, id := range eventIDs {
+		ids[id] = struct{}{}
+	}
+
+	for i := range d.outbox {
+		if _, ok := ids[d.outbox[i].event.EventID]; ok {
+			update(&d.outbox[i])
+		}
+	}
+}
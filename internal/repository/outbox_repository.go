@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository interface {
+	/* метод записывает событие в таблицу outbox. Вызывается внутри транзакции изменения
+	состояния, чтобы событие фиксировалось вместе с ним. Принимает на вход модель
+	enteties.OutboxEvent (EventID, CreatedAt и Attempt заполняются хранилищем)*/
+	AddEvent(ctx context.Context, event *enteties.OutboxEvent) error
+
+	/* метод выбирает до limit недоставленных событий, время отправки которых наступило, в порядке
+	записи, увеличивает у них счетчик попыток и откладывает их на lease. Пока lease не истек,
+	события не выбираются повторно (в том числе другими экземплярами сервиса), а если отправивший
+	их процесс упал, они будут выбраны снова. Возвращает список моделей enteties.OutboxEvent*/
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]enteties.OutboxEvent, error)
+
+	/* метод отмечает события доставленными. Принимает на вход id событий*/
+	MarkDelivered(ctx context.Context, eventIDs []int64) error
+
+	/* метод откладывает повторную отправку событий на retryDelay и сохраняет текст ошибки.
+	Принимает на вход id событий*/
+	MarkFailed(ctx context.Context, eventIDs []int64, retryDelay time.Duration, lastError string) error
+
+	/* метод удаляет события, доставленные раньше, чем olderThan назад. Возвращает количество
+	удаленных событий*/
+	DeleteDelivered(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type outboxPostgresRepository struct {
+	Db *pgxpool.Pool
+	sq squirrel.StatementBuilderType
+}
+
+func NewOutboxPostgresRepository(db *pgxpool.Pool) *outboxPostgresRepository {
+	return &outboxPostgresRepository{
+		Db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (op *outboxPostgresRepository) AddEvent(ctx context.Context, event *enteties.OutboxEvent) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.AddEvent")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, op.Db)
+
+	query := op.sq.Insert("outbox").
+		Columns("event_type", "actor", "payload").
+		Values(event.EventType, event.Actor, string(event.Payload))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[OutboxRepo | AddEvent]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[OutboxRepo | AddEvent]: %w", err)
+	}
+
+	return nil
+}
+
+func (op *outboxPostgresRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]enteties.OutboxEvent, error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.ClaimEvents")
+	defer span.End()
+
+	// SKIP LOCKED не дает двум экземплярам сервиса выбрать одни и те же события
+	query := `UPDATE outbox
+		SET available_at = CURRENT_TIMESTAMP + make_interval(secs => $2), attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE delivered_at IS NULL AND available_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, actor, payload, created_at, attempts`
+
+	rows, err := GetQuerier(ctx, op.Db).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("[OutboxRepo | ClaimEvents]: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]enteties.OutboxEvent, 0)
+	for rows.Next() {
+		var e enteties.OutboxEvent
+		err := rows.Scan(&e.EventID, &e.EventType, &e.Actor, &e.Payload, &e.CreatedAt, &e.Attempt)
+		if err != nil {
+			return nil, fmt.Errorf("[OutboxRepo | ClaimEvents]: %w", err)
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[OutboxRepo | ClaimEvents]: %w", err)
+	}
+
+	// RETURNING не сохраняет порядок подзапроса
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].EventID < events[j].EventID
+	})
+
+	return events, nil
+}
+
+func (op *outboxPostgresRepository) MarkDelivered(ctx context.Context, eventIDs []int64) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkDelivered")
+	defer span.End()
+
+	query := `UPDATE outbox SET delivered_at = CURRENT_TIMESTAMP, last_error = '' WHERE id = ANY($1)`
+
+	_, err := GetQuerier(ctx, op.Db).Exec(ctx, query, eventIDs)
+	if err != nil {
+		return fmt.Errorf("[OutboxRepo | MarkDelivered]: %w", err)
+	}
+
+	return nil
+}
+
+func (op *outboxPostgresRepository) MarkFailed(ctx context.Context, eventIDs []int64, retryDelay time.Duration,
+	lastError string) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkFailed")
+	defer span.End()
+
+	query := `UPDATE outbox SET available_at = CURRENT_TIMESTAMP + make_interval(secs => $2), last_error = $3
+		WHERE id = ANY($1)`
+
+	_, err := GetQuerier(ctx, op.Db).Exec(ctx, query, eventIDs, retryDelay.Seconds(), lastError)
+	if err != nil {
+		return fmt.Errorf("[OutboxRepo | MarkFailed]: %w", err)
+	}
+
+	return nil
+}
+
+func (op *outboxPostgresRepository) DeleteDelivered(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.DeleteDelivered")
+	defer span.End()
+
+	query := `DELETE FROM outbox
+		WHERE delivered_at IS NOT NULL AND delivered_at < CURRENT_TIMESTAMP - make_interval(secs => $1)`
+
+	tag, err := GetQuerier(ctx, op.Db).Exec(ctx, query, olderThan.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("[OutboxRepo | DeleteDelivered]: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,610 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type prMemoryRepository struct {
+	Storage *MemoryStorage
+}
+
+func NewPRMemoryRepository(storage *MemoryStorage) *prMemoryRepository {
+	return &prMemoryRepository{
+		Storage: storage,
+	}
+}
+
+func (pmr *prMemoryRepository) CreatePR(ctx context.Context, pr *enteties.CreatePullRequest) (*enteties.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.CreatePR")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &pmr.Storage.data
+
+	if _, ok := data.prs[pr.PullRequestID]; ok {
+		return nil, fmt.Errorf("[PRRepo | CreatePR]: %w", ErrorDuplicate)
+	}
+
+	if _, ok := data.users[pr.AuthorID]; !ok {
+		return nil, fmt.Errorf("[PRRepo | CreatePR]: %w", errMemoryForeignKey)
+	}
+
+	createdAt := memoryNow()
+	data.prs[pr.PullRequestID] = enteties.PullRequest{
+		PullRequestID:     pr.PullRequestID,
+		PulRequestName:    pr.PullRequestName,
+		AuthorID:          pr.AuthorID,
+		Status:            enteties.PullRequestStatusOpen,
+		AssignedReviewers: make([]string, 0),
+		IsDraft:           pr.IsDraft,
+		CreatedAt:         &createdAt,
+	}
+
+	return &enteties.PullRequestShort{
+		PullRequestID:  pr.PullRequestID,
+		PulRequestName: pr.PullRequestName,
+		AuthorID:       pr.AuthorID,
+		Status:         enteties.PullRequestStatusOpen,
+		IsDraft:        pr.IsDraft,
+	}, nil
+}
+
+func (pmr *prMemoryRepository) PRExists(ctx context.Context, id string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.PRExists")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	_, ok := pmr.Storage.data.prs[id]
+	return ok, nil
+}
+
+func (pmr *prMemoryRepository) SetReviewersBatch(ctx context.Context, PR_id string, usersID []string) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetReviewersBatch")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &pmr.Storage.data
+
+	pr, ok := data.prs[PR_id]
+	if !ok {
+		return fmt.Errorf("[PRRepo | SetReviewersBatch]: %w", errMemoryForeignKey)
+	}
+
+	reviewers := append([]string{}, pr.AssignedReviewers...)
+	for _, userID := range usersID {
+		if _, ok := data.users[userID]; !ok {
+			return fmt.Errorf("[PRRepo | SetReviewersBatch]: %w", errMemoryForeignKey)
+		}
+
+		if containsString(reviewers, userID) {
+			return fmt.Errorf("[PRRepo | SetReviewersBatch]: %w", ErrorDuplicate)
+		}
+
+		reviewers = append(reviewers, userID)
+	}
+
+	pr.AssignedReviewers = reviewers
+	data.prs[PR_id] = pr
+
+	return nil
+}
+
+func (pmr *prMemoryRepository) IsMerged(ctx context.Context, PR_id string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.IsMerged")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	pr, ok := pmr.Storage.data.prs[PR_id]
+	if !ok {
+		return false, fmt.Errorf("[PRRepo | IsMerged]: %w", errMemoryNotFound)
+	}
+
+	return pr.Status == enteties.PullRequestStatusMerged, nil
+}
+
+func (pmr *prMemoryRepository) MergePR(ctx context.Context, PR_id string) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.MergePR")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	pr, ok := pmr.Storage.data.prs[PR_id]
+	if !ok {
+		return nil, fmt.Errorf("[PRRepo | MergePR]: %w", errMemoryNotFound)
+	}
+
+	pr.Status = enteties.PullRequestStatusMerged
+	if pr.MergedAt == nil {
+		mergedAt := memoryNow()
+		pr.MergedAt = &mergedAt
+	}
+	pmr.Storage.data.prs[PR_id] = pr
+
+	result := publicPR(pr)
+	result.Reviews = pmr.Storage.data.prReviews(pr)
+
+	return result, nil
+}
+
+func (pmr *prMemoryRepository) GetPR(ctx context.Context, PR_id string) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetPR")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	pr, ok := pmr.Storage.data.prs[PR_id]
+	if !ok {
+		return nil, fmt.Errorf("[PRRepo | GetPR]: %w", errMemoryNotFound)
+	}
+
+	result := publicPR(pr)
+	result.Reviews = pmr.Storage.data.prReviews(pr)
+
+	return result, nil
+}
+
+func (pmr *prMemoryRepository) GetReviewsByUser(ctx context.Context, filter *enteties.UserReviewsFilter,
+	after *enteties.PullRequestCursor, limit int) ([]*enteties.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetReviewsByUser")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	prs := make([]enteties.PullRequest, 0)
+	for _, pr := range pmr.Storage.data.prs {
+		if !containsString(pr.AssignedReviewers, filter.UserID) {
+			continue
+		}
+
+		// закрытые pull request возвращаются только по явному фильтру
+		if filter.Status != "" && pr.Status != filter.Status ||
+			filter.Status == "" && pr.Status == enteties.PullRequestStatusClosed {
+			continue
+		}
+
+		if pr.IsDraft && !filter.IncludeDrafts {
+			continue
+		}
+
+		if filter.AuthorID != "" && pr.AuthorID != filter.AuthorID {
+			continue
+		}
+
+		if !inTimeRange(pr.CreatedAt, filter.From, filter.To) {
+			continue
+		}
+
+		prs = append(prs, pr)
+	}
+
+	return pagePRs(prs, filter.Sort, after, limit), nil
+}
+
+func (pmr *prMemoryRepository) ListPRs(ctx context.Context, filter *enteties.PullRequestListFilter,
+	after *enteties.PullRequestCursor, limit int) ([]*enteties.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.ListPRs")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &pmr.Storage.data
+
+	prs := make([]enteties.PullRequest, 0)
+	for _, pr := range data.prs {
+		if filter.Status != "" && pr.Status != filter.Status {
+			continue
+		}
+
+		if filter.AuthorID != "" && pr.AuthorID != filter.AuthorID {
+			continue
+		}
+
+		// команда pull request - текущая команда автора
+		if filter.TeamName != "" && data.users[pr.AuthorID].TeamName != filter.TeamName {
+			continue
+		}
+
+		if filter.ReviewerID != "" && !containsString(pr.AssignedReviewers, filter.ReviewerID) {
+			continue
+		}
+
+		if filter.Name != "" && !strings.Contains(strings.ToLower(pr.PulRequestName), strings.ToLower(filter.Name)) {
+			continue
+		}
+
+		if !inTimeRange(pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo) {
+			continue
+		}
+
+		if !inTimeRange(pr.MergedAt, filter.MergedFrom, filter.MergedTo) {
+			continue
+		}
+
+		prs = append(prs, pr)
+	}
+
+	return pagePRs(prs, filter.Sort, after, limit), nil
+}
+
+func (pmr *prMemoryRepository) IsUserAssignedToPR(ctx context.Context, userID, prID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.IsUserAssignedToPR")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	pr, ok := pmr.Storage.data.prs[prID]
+	if !ok {
+		return false, nil
+	}
+
+	return containsString(pr.AssignedReviewers, userID), nil
+}
+
+func (pmr *prMemoryRepository) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.ReassignReviewer")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	err := pmr.Storage.data.replaceReviewer(prID, oldUserID, newUserID)
+	if err != nil {
+		return fmt.Errorf("[PRRepo | ReassignReviewer]: %w", err)
+	}
+
+	return nil
+}
+
+func (pmr *prMemoryRepository) GetAuthorPR(ctx context.Context, prID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetAuthorPR")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	pr, ok := pmr.Storage.data.prs[prID]
+	if !ok {
+		return "", fmt.Errorf("[PRRepo | GetAuthorPR]: %w", errMemoryNotFound)
+	}
+
+	return pr.AuthorID, nil
+}
+
+func (pmr *prMemoryRepository) CountOpenReviewsByUsers(ctx context.Context, usersID []string) (map[string]int, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.CountOpenReviewsByUsers")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	result := make(map[string]int, len(usersID))
+	for _, userID := range usersID {
+		result[userID] = 0
+	}
+
+	for _, pr := range pmr.Storage.data.prs {
+		if pr.Status != enteties.PullRequestStatusOpen {
+			continue
+		}
+
+		for _, reviewer := range pr.AssignedReviewers {
+			if _, ok := result[reviewer]; ok {
+				result[reviewer]++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (pmr *prMemoryRepository) GetOpenPRsByReviewers(ctx context.Context, usersID []string) ([]*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetOpenPRsByReviewers")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	result := make([]*enteties.PullRequest, 0)
+	for _, pr := range pmr.Storage.data.sortedPRs() {
+		if pr.Status != enteties.PullRequestStatusOpen {
+			continue
+		}
+
+		for _, userID := range usersID {
+			if containsString(pr.AssignedReviewers, userID) {
+				result = append(result, shortListPR(pr))
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (pmr *prMemoryRepository) GetOpenPRsByTeamAuthors(ctx context.Context, teamName string) ([]*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetOpenPRsByTeamAuthors")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &pmr.Storage.data
+
+	result := make([]*enteties.PullRequest, 0)
+	for _, pr := range data.sortedPRs() {
+		author, ok := data.users[pr.AuthorID]
+		if !ok || author.TeamName == "" || author.TeamName != teamName {
+			continue
+		}
+
+		if pr.Status == enteties.PullRequestStatusOpen {
+			result = append(result, shortListPR(pr))
+		}
+	}
+
+	return result, nil
+}
+
+func (pmr *prMemoryRepository) SetPRStatus(ctx context.Context, prID string, status enteties.PullRequestStatus) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetPRStatus")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	if pr, ok := pmr.Storage.data.prs[prID]; ok {
+		pr.Status = status
+		pmr.Storage.data.prs[prID] = pr
+	}
+
+	return nil
+}
+
+func (pmr *prMemoryRepository) SetPRDraft(ctx context.Context, prID string, isDraft bool) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetPRDraft")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	if pr, ok := pmr.Storage.data.prs[prID]; ok {
+		pr.IsDraft = isDraft
+		pmr.Storage.data.prs[prID] = pr
+	}
+
+	return nil
+}
+
+func (pmr *prMemoryRepository) ReplaceReviewersBatch(ctx context.Context, replacements []enteties.ReviewerReplacement) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.ReplaceReviewersBatch")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	for _, r := range replacements {
+		err := pmr.Storage.data.replaceReviewer(r.PullRequestID, r.OldUserID, r.ReplacedBy)
+		if err != nil {
+			return fmt.Errorf("[PRRepo | ReplaceReviewersBatch]: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (pmr *prMemoryRepository) SetReviewState(ctx context.Context, prID, userID string, state enteties.ReviewState) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetReviewState")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &pmr.Storage.data
+
+	// как и UPDATE в базе, ничего не делает, если ревьюер не назначен
+	pr, ok := data.prs[prID]
+	if !ok || !containsString(pr.AssignedReviewers, userID) {
+		return nil
+	}
+
+	reviewedAt := memoryNow()
+	data.reviews[reviewKey{prID: prID, userID: userID}] = enteties.ReviewerReview{
+		UserID:     userID,
+		State:      state,
+		ReviewedAt: &reviewedAt,
+	}
+
+	return nil
+}
+
+func (pmr *prMemoryRepository) AddAssignmentEvents(ctx context.Context, events []enteties.AssignmentEvent) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.AddAssignmentEvents")
+	defer span.End()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &pmr.Storage.data
+
+	for _, e := range events {
+		if _, ok := data.prs[e.PullRequestID]; !ok {
+			return fmt.Errorf("[PRRepo | AddAssignmentEvents]: %w", errMemoryForeignKey)
+		}
+	}
+
+	createdAt := memoryNow()
+	for _, e := range events {
+		data.lastEventID++
+		e.EventID = data.lastEventID
+		e.CreatedAt = createdAt
+		data.events = append(data.events, e)
+	}
+
+	return nil
+}
+
+func (pmr *prMemoryRepository) GetAssignmentEvents(ctx context.Context, prID string) ([]enteties.AssignmentEvent, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetAssignmentEvents")
+	defer span.End()
+
+	unlock := pmr.Storage.lock(ctx)
+	defer unlock()
+
+	events := make([]enteties.AssignmentEvent, 0)
+	for _, e := range pmr.Storage.data.events {
+		if e.PullRequestID == prID {
+			events = append(events, e)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.Before(events[j].CreatedAt)
+		}
+		return events[i].EventID < events[j].EventID
+	})
+
+	return events, nil
+}
+
+func (pmr *prMemoryRepository) LockPRs(ctx context.Context, prIDs []string) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.LockPRs")
+	defer span.End()
+
+	// транзакции in-memory хранилища и так выполняются по одной
+	return nil
+}
+
+// заменяет ревьюера на pull request, пустой newUserID снимает ревьюера без замены
+func (d *memoryData) replaceReviewer(prID, oldUserID, newUserID string) error {
+	pr, ok := d.prs[prID]
+	if !ok {
+		return nil
+	}
+
+	if newUserID == "" {
+		pr.AssignedReviewers = removeString(pr.AssignedReviewers, oldUserID)
+		d.prs[prID] = pr
+		delete(d.reviews, reviewKey{prID: prID, userID: oldUserID})
+		return nil
+	}
+
+	if !containsString(pr.AssignedReviewers, oldUserID) {
+		return nil
+	}
+
+	if _, ok := d.users[newUserID]; !ok {
+		return errMemoryForeignKey
+	}
+
+	if containsString(pr.AssignedReviewers, newUserID) {
+		return ErrorDuplicate
+	}
+
+	reviewers := make([]string, len(pr.AssignedReviewers))
+	for i, reviewer := range pr.AssignedReviewers {
+		if reviewer == oldUserID {
+			reviewer = newUserID
+		}
+		reviewers[i] = reviewer
+	}
+
+	pr.AssignedReviewers = reviewers
+	d.prs[prID] = pr
+
+	// решение заменяемого ревьюера к новому не переходит
+	delete(d.reviews, reviewKey{prID: prID, userID: oldUserID})
+
+	return nil
+}
+
+// pull request в том виде, в котором его возвращает GetPR (без даты создания)
+func publicPR(pr enteties.PullRequest) *enteties.PullRequest {
+	result := copyPR(pr)
+	result.CreatedAt = nil
+	return result
+}
+
+// pull request в том виде, в котором его возвращают списки (без дат)
+func shortListPR(pr enteties.PullRequest) *enteties.PullRequest {
+	result := publicPR(pr)
+	result.MergedAt = nil
+	return result
+}
+
+// сортирует pull request по created_at и pull_request_id в порядке order (по умолчанию desc) и
+// возвращает не больше limit pull request, следующих сразу после after (аналог keyset пагинации)
+func pagePRs(prs []enteties.PullRequest, order enteties.SortOrder, after *enteties.PullRequestCursor,
+	limit int) []*enteties.PullRequestShort {
+
+	// сравнение позиций pull request: отрицательное, если a раньше b по возрастанию
+	compare := func(createdAt time.Time, prID string, cursor enteties.PullRequestCursor) int {
+		if c := createdAt.Compare(cursor.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(prID, cursor.PullRequestID)
+	}
+
+	asc := order == enteties.SortOrderAsc
+
+	slices.SortFunc(prs, func(a, b enteties.PullRequest) int {
+		c := compare(*a.CreatedAt, a.PullRequestID, enteties.PullRequestCursor{
+			CreatedAt:     *b.CreatedAt,
+			PullRequestID: b.PullRequestID,
+		})
+		if !asc {
+			c = -c
+		}
+		return c
+	})
+
+	result := make([]*enteties.PullRequestShort, 0, limit)
+	for _, pr := range prs {
+		if len(result) == limit {
+			break
+		}
+
+		if after != nil {
+			c := compare(*pr.CreatedAt, pr.PullRequestID, *after)
+			if asc && c <= 0 || !asc && c >= 0 {
+				continue
+			}
+		}
+
+		createdAt := *pr.CreatedAt
+		result = append(result, &enteties.PullRequestShort{
+			PullRequestID:  pr.PullRequestID,
+			PulRequestName: pr.PulRequestName,
+			AuthorID:       pr.AuthorID,
+			Status:         pr.Status,
+			IsDraft:        pr.IsDraft,
+			CreatedAt:      &createdAt,
+		})
+	}
+
+	return result
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PRRepository interface {
@@ -30,32 +33,87 @@ type PRRepository interface {
 	Принимает на вход pull_request_id, возвращает модель enteties.PullRequest*/
 	MergePR(ctx context.Context, PR_id string) (*enteties.PullRequest, error)
 
-	/* метод возвращает информацию о pull_request в виде модели enteties.PullRequest.
-	Принимает на вход pull_request_id*/
+	/* метод возвращает информацию о pull_request вместе с решениями ревьюеров в виде модели
+	enteties.PullRequest. Принимает на вход pull_request_id*/
 	GetPR(ctx context.Context, PR_id string) (*enteties.PullRequest, error)
 
-	/* метод возвращает все pull_request, на которые пользователь назначен ревьюером.
-	Принимает на вход user_id, возвращает список моделей enteties.PullRequestShort */
-	GetAllPRByUserID(ctx context.Context, user_id string) ([]*enteties.PullRequestShort, error)
+	/* метод возвращает pull_request, на которые пользователь назначен ревьюером, с учетом
+	фильтров enteties.UserReviewsFilter (Cursor и Limit не используются). Pull request
+	сортируются по created_at и pull_request_id в порядке filter.Sort (по умолчанию desc).
+	Возвращает не больше limit pull request, следующих сразу после after (nil - с начала)*/
+	GetReviewsByUser(ctx context.Context, filter *enteties.UserReviewsFilter, after *enteties.PullRequestCursor,
+		limit int) ([]*enteties.PullRequestShort, error)
+
+	/* метод возвращает pull_request, подходящие под фильтры enteties.PullRequestListFilter
+	(Cursor и Limit не используются). Pull request сортируются по created_at и pull_request_id
+	в порядке filter.Sort (по умолчанию desc). Возвращает не больше limit pull request,
+	следующих сразу после after (nil - с начала)*/
+	ListPRs(ctx context.Context, filter *enteties.PullRequestListFilter, after *enteties.PullRequestCursor,
+		limit int) ([]*enteties.PullRequestShort, error)
 
 	/* метод возвращает true, если пользователь назначен ревьюером на pull request, иначе
 	false. Принимает на вход user_id и pull_request_id*/
 	IsUserAssignedToPR(ctx context.Context, userID, prID string) (bool, error)
 
-	/* метод заменяет в таблице assigned_reviewers ревьюера на pull request. Принимает
-	на вход pull_request, user_id заменяемого пользователя и user_id замещающего*/
+	/* метод заменяет в таблице assigned_reviewers ревьюера на pull request, решение нового
+	ревьюера сбрасывается в PENDING. Принимает на вход pull_request, user_id заменяемого
+	пользователя и user_id замещающего*/
 	ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string) error
 
 	/* метод возвращает автора pull request. Принимает на вход pull_request_id*/
 	GetAuthorPR(ctx context.Context, prID string) (string, error)
+
+	/* метод возвращает количество OPEN pull request, на которые назначен ревьюером каждый
+	пользователь. Принимает на вход список user_id, возвращает map user_id -> количество*/
+	CountOpenReviewsByUsers(ctx context.Context, usersID []string) (map[string]int, error)
+
+	/* метод возвращает все OPEN pull request, на которые назначен ревьюером хотя бы один из
+	пользователей, вместе со всеми их ревьюерами. Принимает на вход список user_id, возвращает
+	список моделей enteties.PullRequest*/
+	GetOpenPRsByReviewers(ctx context.Context, usersID []string) ([]*enteties.PullRequest, error)
+
+	/* метод возвращает все OPEN pull request, авторы которых состоят в команде. Принимает на
+	вход название команды, возвращает список моделей enteties.PullRequest*/
+	GetOpenPRsByTeamAuthors(ctx context.Context, teamName string) ([]*enteties.PullRequest, error)
+
+	/* метод устанавливает status у pull_request. Принимает на вход pull_request_id и
+	новый статус*/
+	SetPRStatus(ctx context.Context, prID string, status enteties.PullRequestStatus) error
+
+	/* метод устанавливает признак черновика у pull_request. Принимает на вход pull_request_id
+	и новое значение признака*/
+	SetPRDraft(ctx context.Context, prID string, isDraft bool) error
+
+	/* метод заносит в таблицу assigned_reviewers замены ревьюеров сразу пачкой, решения
+	новых ревьюеров сбрасываются в PENDING. Если ReplacedBy пустой, ревьюер снимается с pull
+	request без замены. Принимает на вход список моделей enteties.ReviewerReplacement*/
+	ReplaceReviewersBatch(ctx context.Context, replacements []enteties.ReviewerReplacement) error
+
+	/* метод сохраняет решение ревьюера по pull request вместе с временем решения. Принимает
+	на вход pull_request_id, user_id ревьюера и решение*/
+	SetReviewState(ctx context.Context, prID, userID string, state enteties.ReviewState) error
+
+	/* метод заносит в таблицу assignment_events события истории назначений сразу пачкой.
+	Принимает на вход список моделей enteties.AssignmentEvent*/
+	AddAssignmentEvents(ctx context.Context, events []enteties.AssignmentEvent) error
+
+	/* метод возвращает историю назначений ревьюеров на pull request в порядке событий.
+	Принимает на вход pull_request_id, возвращает список моделей enteties.AssignmentEvent*/
+	GetAssignmentEvents(ctx context.Context, prID string) ([]enteties.AssignmentEvent, error)
+
+	/* метод блокирует строки pull request до конца транзакции (SELECT ... FOR UPDATE), чтобы
+	параллельные мердж и переназначения ревьюеров выполнялись по очереди. Строки блокируются
+	в порядке pull_request_id, несуществующие pull request пропускаются. Должен вызываться
+	внутри транзакции (tx в контексте). Принимает на вход список pull_request_id*/
+	LockPRs(ctx context.Context, prIDs []string) error
 }
 
 type prPostgresRepository struct {
-	Db *pgx.Conn
+	Db *pgxpool.Pool
 	sq squirrel.StatementBuilderType
 }
 
-func NewPRPostgresRepository(db *pgx.Conn) *prPostgresRepository {
+func NewPRPostgresRepository(db *pgxpool.Pool) *prPostgresRepository {
 	return &prPostgresRepository{
 		Db: db,
 		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
@@ -63,6 +121,8 @@ func NewPRPostgresRepository(db *pgx.Conn) *prPostgresRepository {
 }
 
 func (prp *prPostgresRepository) CreatePR(ctx context.Context, pr *enteties.CreatePullRequest) (*enteties.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.CreatePR")
+	defer span.End()
 
 	prShort := enteties.PullRequestShort{
 		PullRequestID:  pr.PullRequestID,
@@ -70,25 +130,25 @@ func (prp *prPostgresRepository) CreatePR(ctx context.Context, pr *enteties.Crea
 		AuthorID:       pr.AuthorID,
 	}
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return nil, fmt.Errorf("[PRRepo | CreatePR]: can not get pgx.Tx")
-	}
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
 
 	query := prp.sq.Insert("pull_requests").
-		Columns("pull_request_id", "pull_request_name", "author_id", "status").
-		Values(pr.PullRequestID, pr.PullRequestName, pr.AuthorID, enteties.PullRequestStatusOpen).
-		Suffix("RETURNING status")
+		Columns("pull_request_id", "pull_request_name", "author_id", "status", "is_draft").
+		Values(pr.PullRequestID, pr.PullRequestName, pr.AuthorID, enteties.PullRequestStatusOpen, pr.IsDraft).
+		Suffix("RETURNING status, is_draft")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("[PRRepo | CreatePR]: %w", err)
 	}
 
-	row := tx.QueryRow(ctx, sql, args...)
+	row := db.QueryRow(ctx, sql, args...)
 
-	err = row.Scan(&prShort.Status)
+	err = row.Scan(&prShort.Status, &prShort.IsDraft)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("[PRRepo | CreatePR]: %w", ErrorDuplicate)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("[PRRepo | CreatePR]: %w", err)
 	}
@@ -97,9 +157,12 @@ func (prp *prPostgresRepository) CreatePR(ctx context.Context, pr *enteties.Crea
 }
 
 func (prp *prPostgresRepository) PRExists(ctx context.Context, id string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.PRExists")
+	defer span.End()
+
 	query := "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)"
 	var exists bool
-	err := prp.Db.QueryRow(ctx, query, id).Scan(&exists)
+	err := GetQuerier(ctx, prp.Db).QueryRow(ctx, query, id).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("[PRRepo | PRExists]: %w", err)
 	}
@@ -107,20 +170,19 @@ func (prp *prPostgresRepository) PRExists(ctx context.Context, id string) (bool,
 }
 
 func (prp *prPostgresRepository) SetReviewersBatch(ctx context.Context, PR_id string, usersID []string) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetReviewersBatch")
+	defer span.End()
 
 	batch := &pgx.Batch{}
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return fmt.Errorf("[PRRepo | SetReviewersBatch]: can not get pgx.Tx")
-	}
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
 
 	for _, user := range usersID {
 		batch.Queue(`INSERT INTO assigned_reviewers(pull_request_id, user_id)
 		VALUES ($1, $2)`, PR_id, user)
 	}
-	results := tx.SendBatch(ctx, batch)
+	results := db.SendBatch(ctx, batch)
 	defer results.Close()
 
 	for i := 0; i < batch.Len(); i++ {
@@ -134,6 +196,9 @@ func (prp *prPostgresRepository) SetReviewersBatch(ctx context.Context, PR_id st
 }
 
 func (prp *prPostgresRepository) IsMerged(ctx context.Context, PR_id string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.IsMerged")
+	defer span.End()
+
 	var status string
 
 	query := prp.sq.Select("status").
@@ -145,7 +210,7 @@ func (prp *prPostgresRepository) IsMerged(ctx context.Context, PR_id string) (bo
 		return false, fmt.Errorf("[PRRepo | IsMerged]: %w", err)
 	}
 
-	err = prp.Db.QueryRow(ctx, sql, args...).Scan(&status)
+	err = GetQuerier(ctx, prp.Db).QueryRow(ctx, sql, args...).Scan(&status)
 	if err != nil {
 		return false, fmt.Errorf("[PRRepo | IsMerged]: %w", err)
 	}
@@ -158,12 +223,11 @@ func (prp *prPostgresRepository) IsMerged(ctx context.Context, PR_id string) (bo
 }
 
 func (prp *prPostgresRepository) MergePR(ctx context.Context, PR_id string) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.MergePR")
+	defer span.End()
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return nil, fmt.Errorf("[PRRepo | MergePR]: can not get pgx.Tx")
-	}
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
 
 	query := prp.sq.Update("pull_requests").
 		Set("status", enteties.PullRequestStatusMerged).
@@ -175,7 +239,7 @@ func (prp *prPostgresRepository) MergePR(ctx context.Context, PR_id string) (*en
 		return nil, fmt.Errorf("[PRRepo | MergePR]: %w", err)
 	}
 
-	_, err = tx.Exec(ctx, sql, args...)
+	_, err = db.Exec(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("[PRRepo | MergePR]: %w", err)
 	}
@@ -189,16 +253,15 @@ func (prp *prPostgresRepository) MergePR(ctx context.Context, PR_id string) (*en
 }
 
 func (prp *prPostgresRepository) GetPR(ctx context.Context, PR_id string) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetPR")
+	defer span.End()
 
 	var responcePR enteties.PullRequest
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return nil, fmt.Errorf("[PRRepo | GetPR]: can not get pgx.Tx")
-	}
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
 
-	query := prp.sq.Select("pull_request_id", "pull_request_name", "author_id", "status", "merged_at").
+	query := prp.sq.Select("pull_request_id", "pull_request_name", "author_id", "status", "is_draft", "merged_at").
 		From("pull_requests").
 		Where(squirrel.Eq{"pull_request_id": PR_id})
 
@@ -207,109 +270,268 @@ func (prp *prPostgresRepository) GetPR(ctx context.Context, PR_id string) (*ente
 		return nil, fmt.Errorf("[PRRepo | GetPR]: %w", err)
 	}
 
-	row := tx.QueryRow(ctx, sql, args...)
+	row := db.QueryRow(ctx, sql, args...)
 
 	err = row.Scan(&responcePR.PullRequestID, &responcePR.PulRequestName, &responcePR.AuthorID,
-		&responcePR.Status, &responcePR.MergedAt)
+		&responcePR.Status, &responcePR.IsDraft, &responcePR.MergedAt)
 	if err != nil {
 		return nil, fmt.Errorf("[PRRepo | GetPR]: %w", err)
 	}
 
-	reviewersID, err := prp.getListReviewersID(ctx, PR_id)
+	reviews, err := prp.getReviews(ctx, PR_id)
 	if err != nil {
 		return nil, fmt.Errorf("[PRRepo | GetPR]: %w", err)
 	}
 
-	responcePR.AssignedReviewers = reviewersID
+	responcePR.AssignedReviewers = make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		responcePR.AssignedReviewers = append(responcePR.AssignedReviewers, review.UserID)
+	}
+	responcePR.Reviews = reviews
 
 	return &responcePR, nil
 }
 
-// вспомогательный метод для получения ревьюеров
-func (prp *prPostgresRepository) getListReviewersID(ctx context.Context, PR_id string) ([]string, error) {
-	var result []string
+// вспомогательный метод для получения ревьюеров и их решений
+func (prp *prPostgresRepository) getReviews(ctx context.Context, PR_id string) ([]enteties.ReviewerReview, error) {
+	result := make([]enteties.ReviewerReview, 0)
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return nil, fmt.Errorf("[PRRepo | getListReviewersID]: can not get pgx.Tx")
-	}
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
 
-	query := prp.sq.Select("user_id").
+	query := prp.sq.Select("user_id", "review_state", "reviewed_at").
 		From("assigned_reviewers").
 		Where(squirrel.Eq{"pull_request_id": PR_id})
 
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("[PRRepo | getListReviewersID]: %w", err)
+		return nil, fmt.Errorf("[PRRepo | getReviews]: %w", err)
 	}
 
-	rows, err := tx.Query(ctx, sql, args...)
+	rows, err := db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("[PRRepo | getListReviewersID]: %w", err)
+		return nil, fmt.Errorf("[PRRepo | getReviews]: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
-		var user_id string
-		err := rows.Scan(&user_id)
+		var review enteties.ReviewerReview
+		err := rows.Scan(&review.UserID, &review.State, &review.ReviewedAt)
 		if err != nil {
-			return nil, fmt.Errorf("[PRRepo | getListReviewersID]: %w", err)
+			return nil, fmt.Errorf("[PRRepo | getReviews]: %w", err)
 		}
 
-		result = append(result, user_id)
+		result = append(result, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[PRRepo | getReviews]: %w", err)
 	}
 
 	return result, nil
 }
 
-func (prp *prPostgresRepository) GetAllPRByUserID(ctx context.Context, user_id string) ([]*enteties.PullRequestShort, error) {
+func (prp *prPostgresRepository) GetReviewsByUser(ctx context.Context, filter *enteties.UserReviewsFilter,
+	after *enteties.PullRequestCursor, limit int) ([]*enteties.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetReviewsByUser")
+	defer span.End()
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return nil, fmt.Errorf("[PRRepo | GetAllPRByUserID]: can not get pgx.Tx")
-	}
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
 
-	var responce []*enteties.PullRequestShort
+	responce := make([]*enteties.PullRequestShort, 0)
 
 	query := prp.sq.Select(
 		"p.pull_request_id",
 		"p.pull_request_name",
 		"p.author_id",
-		"p.status").
+		"p.status",
+		"p.is_draft",
+		"p.created_at").
 		From("assigned_reviewers ar").
-		LeftJoin("pull_requests p ON ar.pull_request_id = p.pull_request_id").
-		Where(squirrel.Eq{"ar.user_id": user_id})
+		Join("pull_requests p ON ar.pull_request_id = p.pull_request_id").
+		Where(squirrel.Eq{"ar.user_id": filter.UserID})
+
+	// закрытые pull request возвращаются только по явному фильтру
+	if filter.Status != "" {
+		query = query.Where(squirrel.Eq{"p.status": filter.Status})
+	} else {
+		query = query.Where(squirrel.NotEq{"p.status": enteties.PullRequestStatusClosed})
+	}
+
+	if !filter.IncludeDrafts {
+		query = query.Where(squirrel.Eq{"p.is_draft": false})
+	}
+
+	if filter.AuthorID != "" {
+		query = query.Where(squirrel.Eq{"p.author_id": filter.AuthorID})
+	}
+
+	if filter.From != nil {
+		query = query.Where(squirrel.GtOrEq{"p.created_at": *filter.From})
+	}
+
+	if filter.To != nil {
+		query = query.Where(squirrel.Lt{"p.created_at": *filter.To})
+	}
+
+	// keyset пагинация: следующая страница начинается сразу после последнего pull request
+	order, cmp := "DESC", "<"
+	if filter.Sort == enteties.SortOrderAsc {
+		order, cmp = "ASC", ">"
+	}
+
+	if after != nil {
+		query = query.Where(squirrel.Expr("(p.created_at, p.pull_request_id) "+cmp+" (?, ?)",
+			after.CreatedAt, after.PullRequestID))
+	}
+
+	query = query.OrderBy("p.created_at "+order, "p.pull_request_id "+order).
+		Limit(uint64(limit))
 
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("[PRRepo | GetAllPRByUserID]: %w", err)
+		return nil, fmt.Errorf("[PRRepo | GetReviewsByUser]: %w", err)
 	}
 
-	rows, err := tx.Query(ctx, sql, args...)
+	rows, err := db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("[PRRepo | GetAllPRByUserID]: %w", err)
+		return nil, fmt.Errorf("[PRRepo | GetReviewsByUser]: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var shortPR enteties.PullRequestShort
-		err := rows.Scan(&shortPR.PullRequestID, &shortPR.PulRequestName, &shortPR.AuthorID, &shortPR.Status)
+		err := rows.Scan(&shortPR.PullRequestID, &shortPR.PulRequestName, &shortPR.AuthorID, &shortPR.Status,
+			&shortPR.IsDraft, &shortPR.CreatedAt)
 		if err != nil {
-			return nil, fmt.Errorf("[PRRepo | GetAllPRByUserID]: %w", err)
+			return nil, fmt.Errorf("[PRRepo | GetReviewsByUser]: %w", err)
 		}
 
 		responce = append(responce, &shortPR)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetReviewsByUser]: %w", err)
+	}
+
 	return responce, nil
 }
 
+func (prp *prPostgresRepository) ListPRs(ctx context.Context, filter *enteties.PullRequestListFilter,
+	after *enteties.PullRequestCursor, limit int) ([]*enteties.PullRequestShort, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.ListPRs")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	responce := make([]*enteties.PullRequestShort, 0)
+
+	query := prp.sq.Select(
+		"p.pull_request_id",
+		"p.pull_request_name",
+		"p.author_id",
+		"p.status",
+		"p.is_draft",
+		"p.created_at").
+		From("pull_requests p")
+
+	if filter.Status != "" {
+		query = query.Where(squirrel.Eq{"p.status": filter.Status})
+	}
+
+	if filter.AuthorID != "" {
+		query = query.Where(squirrel.Eq{"p.author_id": filter.AuthorID})
+	}
+
+	// команда pull request - текущая команда автора
+	if filter.TeamName != "" {
+		query = query.Join("users u ON u.user_id = p.author_id").
+			Where(squirrel.Eq{"u.team_name": filter.TeamName})
+	}
+
+	if filter.ReviewerID != "" {
+		query = query.Where(squirrel.Expr(`EXISTS(SELECT 1 FROM assigned_reviewers ar
+		WHERE ar.pull_request_id = p.pull_request_id AND ar.user_id = ?)`, filter.ReviewerID))
+	}
+
+	if filter.Name != "" {
+		query = query.Where(squirrel.ILike{"p.pull_request_name": "%" + escapeLike(filter.Name) + "%"})
+	}
+
+	if filter.CreatedFrom != nil {
+		query = query.Where(squirrel.GtOrEq{"p.created_at": *filter.CreatedFrom})
+	}
+
+	if filter.CreatedTo != nil {
+		query = query.Where(squirrel.Lt{"p.created_at": *filter.CreatedTo})
+	}
+
+	if filter.MergedFrom != nil {
+		query = query.Where(squirrel.GtOrEq{"p.merged_at": *filter.MergedFrom})
+	}
+
+	if filter.MergedTo != nil {
+		query = query.Where(squirrel.Lt{"p.merged_at": *filter.MergedTo})
+	}
+
+	// keyset пагинация: следующая страница начинается сразу после последнего pull request
+	order, cmp := "DESC", "<"
+	if filter.Sort == enteties.SortOrderAsc {
+		order, cmp = "ASC", ">"
+	}
+
+	if after != nil {
+		query = query.Where(squirrel.Expr("(p.created_at, p.pull_request_id) "+cmp+" (?, ?)",
+			after.CreatedAt, after.PullRequestID))
+	}
+
+	query = query.OrderBy("p.created_at "+order, "p.pull_request_id "+order).
+		Limit(uint64(limit))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | ListPRs]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | ListPRs]: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shortPR enteties.PullRequestShort
+		err := rows.Scan(&shortPR.PullRequestID, &shortPR.PulRequestName, &shortPR.AuthorID, &shortPR.Status,
+			&shortPR.IsDraft, &shortPR.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[PRRepo | ListPRs]: %w", err)
+		}
+
+		responce = append(responce, &shortPR)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[PRRepo | ListPRs]: %w", err)
+	}
+
+	return responce, nil
+}
+
+// вспомогательная функция экранирует спецсимволы LIKE, чтобы подстрока искалась как есть
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 func (prp *prPostgresRepository) IsUserAssignedToPR(ctx context.Context, userID, prID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.IsUserAssignedToPR")
+	defer span.End()
 
 	query := `SELECT EXISTS(SELECT 1 FROM assigned_reviewers WHERE pull_request_id = $1 AND 
 	user_id = $2)`
 	var isReviewed bool
-	err := prp.Db.QueryRow(ctx, query, prID, userID).Scan(&isReviewed)
+	err := GetQuerier(ctx, prp.Db).QueryRow(ctx, query, prID, userID).Scan(&isReviewed)
 	if err != nil {
 		return false, fmt.Errorf("[PRRepo | IsUserReviewedToPR]: %w", err)
 	}
@@ -317,14 +539,17 @@ func (prp *prPostgresRepository) IsUserAssignedToPR(ctx context.Context, userID,
 }
 
 func (prp *prPostgresRepository) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string) error {
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return fmt.Errorf("[PRRepo | ReassignReviewer]: can not get pgx.Tx")
-	}
+	ctx, span := tracing.Start(ctx, "PRRepository.ReassignReviewer")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
 
+	// решение заменяемого ревьюера к новому не переходит
 	query := prp.sq.Update("assigned_reviewers").
 		Set("user_id", newUserID).
+		Set("review_state", enteties.ReviewStatePending).
+		Set("reviewed_at", nil).
 		Where(squirrel.Eq{"pull_request_id": prID}).
 		Where(squirrel.Eq{"user_id": oldUserID})
 
@@ -333,7 +558,7 @@ func (prp *prPostgresRepository) ReassignReviewer(ctx context.Context, prID, old
 		return fmt.Errorf("[PRRepo | ReassignReviewer]: %w", err)
 	}
 
-	_, err = tx.Exec(ctx, sql, args...)
+	_, err = db.Exec(ctx, sql, args...)
 	if err != nil {
 		return fmt.Errorf("[PRRepo | ReassignReviewer]: %w", err)
 	}
@@ -342,6 +567,9 @@ func (prp *prPostgresRepository) ReassignReviewer(ctx context.Context, prID, old
 }
 
 func (prp *prPostgresRepository) GetAuthorPR(ctx context.Context, prID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetAuthorPR")
+	defer span.End()
+
 	query := prp.sq.Select("author_id").
 		From("pull_requests").
 		Where(squirrel.Eq{"pull_request_id": prID})
@@ -353,7 +581,7 @@ func (prp *prPostgresRepository) GetAuthorPR(ctx context.Context, prID string) (
 
 	var author string
 
-	row := prp.Db.QueryRow(ctx, sql, args...)
+	row := GetQuerier(ctx, prp.Db).QueryRow(ctx, sql, args...)
 
 	err = row.Scan(&author)
 	if err != nil {
@@ -361,3 +589,384 @@ func (prp *prPostgresRepository) GetAuthorPR(ctx context.Context, prID string) (
 	}
 	return author, nil
 }
+
+func (prp *prPostgresRepository) CountOpenReviewsByUsers(ctx context.Context, usersID []string) (map[string]int, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.CountOpenReviewsByUsers")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	result := make(map[string]int, len(usersID))
+	for _, userID := range usersID {
+		result[userID] = 0
+	}
+
+	query := prp.sq.Select("ar.user_id", "COUNT(*)").
+		From("assigned_reviewers ar").
+		Join("pull_requests p ON ar.pull_request_id = p.pull_request_id").
+		Where(squirrel.Eq{"ar.user_id": usersID}).
+		Where(squirrel.Eq{"p.status": enteties.PullRequestStatusOpen}).
+		GroupBy("ar.user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | CountOpenReviewsByUsers]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | CountOpenReviewsByUsers]: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var count int
+		err := rows.Scan(&userID, &count)
+		if err != nil {
+			return nil, fmt.Errorf("[PRRepo | CountOpenReviewsByUsers]: %w", err)
+		}
+
+		result[userID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[PRRepo | CountOpenReviewsByUsers]: %w", err)
+	}
+
+	return result, nil
+}
+
+func (prp *prPostgresRepository) GetOpenPRsByReviewers(ctx context.Context, usersID []string) ([]*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetOpenPRsByReviewers")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	query := prp.sq.Select(
+		"p.pull_request_id",
+		"p.pull_request_name",
+		"p.author_id",
+		"p.status",
+		"array_agg(ar.user_id)").
+		From("pull_requests p").
+		Join("assigned_reviewers ar ON ar.pull_request_id = p.pull_request_id").
+		Where(squirrel.Eq{"p.status": enteties.PullRequestStatusOpen}).
+		Where(squirrel.Expr(`EXISTS(SELECT 1 FROM assigned_reviewers sel
+		WHERE sel.pull_request_id = p.pull_request_id AND sel.user_id = ANY(?))`, usersID)).
+		GroupBy("p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status").
+		OrderBy("p.pull_request_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetOpenPRsByReviewers]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetOpenPRsByReviewers]: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*enteties.PullRequest, 0)
+	for rows.Next() {
+		var pr enteties.PullRequest
+		err := rows.Scan(&pr.PullRequestID, &pr.PulRequestName, &pr.AuthorID, &pr.Status, &pr.AssignedReviewers)
+		if err != nil {
+			return nil, fmt.Errorf("[PRRepo | GetOpenPRsByReviewers]: %w", err)
+		}
+
+		result = append(result, &pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetOpenPRsByReviewers]: %w", err)
+	}
+
+	return result, nil
+}
+
+func (prp *prPostgresRepository) GetOpenPRsByTeamAuthors(ctx context.Context, teamName string) ([]*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetOpenPRsByTeamAuthors")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	query := prp.sq.Select(
+		"p.pull_request_id",
+		"p.pull_request_name",
+		"p.author_id",
+		"p.status",
+		"COALESCE(array_agg(ar.user_id) FILTER (WHERE ar.user_id IS NOT NULL), '{}')").
+		From("pull_requests p").
+		Join("users u ON p.author_id = u.user_id").
+		LeftJoin("assigned_reviewers ar ON ar.pull_request_id = p.pull_request_id").
+		Where(squirrel.Eq{"u.team_name": teamName}).
+		Where(squirrel.Eq{"p.status": enteties.PullRequestStatusOpen}).
+		GroupBy("p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status").
+		OrderBy("p.pull_request_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetOpenPRsByTeamAuthors]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetOpenPRsByTeamAuthors]: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*enteties.PullRequest, 0)
+	for rows.Next() {
+		var pr enteties.PullRequest
+		err := rows.Scan(&pr.PullRequestID, &pr.PulRequestName, &pr.AuthorID, &pr.Status, &pr.AssignedReviewers)
+		if err != nil {
+			return nil, fmt.Errorf("[PRRepo | GetOpenPRsByTeamAuthors]: %w", err)
+		}
+
+		result = append(result, &pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetOpenPRsByTeamAuthors]: %w", err)
+	}
+
+	return result, nil
+}
+
+func (prp *prPostgresRepository) SetPRStatus(ctx context.Context, prID string, status enteties.PullRequestStatus) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetPRStatus")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	query := prp.sq.Update("pull_requests").
+		Set("status", status).
+		Where(squirrel.Eq{"pull_request_id": prID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[PRRepo | SetPRStatus]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[PRRepo | SetPRStatus]: %w", err)
+	}
+
+	return nil
+}
+
+func (prp *prPostgresRepository) SetPRDraft(ctx context.Context, prID string, isDraft bool) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetPRDraft")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	query := prp.sq.Update("pull_requests").
+		Set("is_draft", isDraft).
+		Where(squirrel.Eq{"pull_request_id": prID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[PRRepo | SetPRDraft]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[PRRepo | SetPRDraft]: %w", err)
+	}
+
+	return nil
+}
+
+func (prp *prPostgresRepository) ReplaceReviewersBatch(ctx context.Context, replacements []enteties.ReviewerReplacement) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.ReplaceReviewersBatch")
+	defer span.End()
+
+	batch := &pgx.Batch{}
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	for _, r := range replacements {
+		if r.ReplacedBy == "" {
+			batch.Queue(`DELETE FROM assigned_reviewers WHERE pull_request_id = $1 AND user_id = $2`,
+				r.PullRequestID, r.OldUserID)
+			continue
+		}
+
+		batch.Queue(`UPDATE assigned_reviewers SET user_id = $3, review_state = $4, reviewed_at = NULL
+		WHERE pull_request_id = $1 AND user_id = $2`, r.PullRequestID, r.OldUserID, r.ReplacedBy,
+			enteties.ReviewStatePending)
+	}
+	results := db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for i := 0; i < batch.Len(); i++ {
+		_, err := results.Exec()
+		if err != nil {
+			return fmt.Errorf("[PRRepo | ReplaceReviewersBatch]: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (prp *prPostgresRepository) SetReviewState(ctx context.Context, prID, userID string, state enteties.ReviewState) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.SetReviewState")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	query := prp.sq.Update("assigned_reviewers").
+		Set("review_state", state).
+		Set("reviewed_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"pull_request_id": prID}).
+		Where(squirrel.Eq{"user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[PRRepo | SetReviewState]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[PRRepo | SetReviewState]: %w", err)
+	}
+
+	return nil
+}
+
+func (prp *prPostgresRepository) AddAssignmentEvents(ctx context.Context, events []enteties.AssignmentEvent) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.AddAssignmentEvents")
+	defer span.End()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	query := prp.sq.Insert("assignment_events").
+		Columns("pull_request_id", "event_type", "old_user_id", "new_user_id", "actor", "reason")
+
+	for _, e := range events {
+		query = query.Values(e.PullRequestID, e.EventType, nullIfEmpty(e.OldUserID), nullIfEmpty(e.NewUserID),
+			e.Actor, e.Reason)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[PRRepo | AddAssignmentEvents]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[PRRepo | AddAssignmentEvents]: %w", err)
+	}
+
+	return nil
+}
+
+func (prp *prPostgresRepository) GetAssignmentEvents(ctx context.Context, prID string) ([]enteties.AssignmentEvent, error) {
+	ctx, span := tracing.Start(ctx, "PRRepository.GetAssignmentEvents")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	query := prp.sq.Select(
+		"id",
+		"pull_request_id",
+		"event_type",
+		"COALESCE(old_user_id, '')",
+		"COALESCE(new_user_id, '')",
+		"actor",
+		"reason",
+		"created_at").
+		From("assignment_events").
+		Where(squirrel.Eq{"pull_request_id": prID}).
+		OrderBy("created_at", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetAssignmentEvents]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetAssignmentEvents]: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]enteties.AssignmentEvent, 0)
+	for rows.Next() {
+		var e enteties.AssignmentEvent
+		err := rows.Scan(&e.EventID, &e.PullRequestID, &e.EventType, &e.OldUserID, &e.NewUserID,
+			&e.Actor, &e.Reason, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[PRRepo | GetAssignmentEvents]: %w", err)
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[PRRepo | GetAssignmentEvents]: %w", err)
+	}
+
+	return events, nil
+}
+
+// вспомогательная функция возвращает nil для пустой строки, чтобы записать в колонку NULL
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (prp *prPostgresRepository) LockPRs(ctx context.Context, prIDs []string) error {
+	ctx, span := tracing.Start(ctx, "PRRepository.LockPRs")
+	defer span.End()
+
+	if len(prIDs) == 0 {
+		return nil
+	}
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, prp.Db)
+
+	// единый порядок блокировки исключает взаимные блокировки между такими запросами
+	query := prp.sq.Select("pull_request_id").
+		From("pull_requests").
+		Where(squirrel.Eq{"pull_request_id": prIDs}).
+		OrderBy("pull_request_id").
+		Suffix("FOR UPDATE")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[PRRepo | LockPRs]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[PRRepo | LockPRs]: %w", err)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("[PRRepo | LockPRs]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+	"sort"
+)
+
+type roleMemoryRepository struct {
+	Storage *MemoryStorage
+}
+
+func NewRoleMemoryRepository(storage *MemoryStorage) *roleMemoryRepository {
+	return &roleMemoryRepository{
+		Storage: storage,
+	}
+}
+
+func (rmr *roleMemoryRepository) SetRole(ctx context.Context, role *enteties.UserRole) (*enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.SetRole")
+	defer span.End()
+
+	unlock := rmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &rmr.Storage.data
+
+	// ограничения таблицы user_roles
+	switch {
+	case role.Role == enteties.RoleAdmin && role.TeamName == "":
+	case role.Role == enteties.RoleTeamLead && role.TeamName != "":
+		if _, ok := data.teams[role.TeamName]; !ok {
+			return nil, fmt.Errorf("[RoleRepo | SetRole]: %w", errMemoryForeignKey)
+		}
+	default:
+		return nil, fmt.Errorf("[RoleRepo | SetRole]: %w", errMemoryInvalidValue)
+	}
+
+	if _, ok := data.users[role.UserID]; !ok {
+		return nil, fmt.Errorf("[RoleRepo | SetRole]: %w", errMemoryForeignKey)
+	}
+
+	saved := *role
+	grantedAt := memoryNow()
+	saved.GrantedAt = &grantedAt
+	data.roles[saved.UserID] = saved
+
+	return &saved, nil
+}
+
+func (rmr *roleMemoryRepository) DeleteRole(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.DeleteRole")
+	defer span.End()
+
+	unlock := rmr.Storage.lock(ctx)
+	defer unlock()
+
+	_, ok := rmr.Storage.data.roles[userID]
+	delete(rmr.Storage.data.roles, userID)
+
+	return ok, nil
+}
+
+func (rmr *roleMemoryRepository) DeleteTeamLeadRoles(ctx context.Context, usersID []string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.DeleteTeamLeadRoles")
+	defer span.End()
+
+	unlock := rmr.Storage.lock(ctx)
+	defer unlock()
+
+	revoked := make([]string, 0)
+	for _, userID := range usersID {
+		role, ok := rmr.Storage.data.roles[userID]
+		if !ok || role.Role != enteties.RoleTeamLead {
+			continue
+		}
+
+		delete(rmr.Storage.data.roles, userID)
+		revoked = append(revoked, userID)
+	}
+
+	sort.Strings(revoked)
+
+	return revoked, nil
+}
+
+func (rmr *roleMemoryRepository) GetRole(ctx context.Context, userID string) (*enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.GetRole")
+	defer span.End()
+
+	unlock := rmr.Storage.lock(ctx)
+	defer unlock()
+
+	role, ok := rmr.Storage.data.roles[userID]
+	if !ok {
+		return &enteties.UserRole{UserID: userID, Role: enteties.RoleMember}, nil
+	}
+
+	return &role, nil
+}
+
+func (rmr *roleMemoryRepository) ListRoles(ctx context.Context, teamName string) ([]enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.ListRoles")
+	defer span.End()
+
+	unlock := rmr.Storage.lock(ctx)
+	defer unlock()
+
+	roles := make([]enteties.UserRole, 0)
+	for _, role := range rmr.Storage.data.roles {
+		if teamName != "" && role.TeamName != teamName {
+			continue
+		}
+
+		roles = append(roles, role)
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].UserID < roles[j].UserID
+	})
+
+	return roles, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RoleRepository interface {
+	/* метод выдает пользователю роль admin или team_lead, заменяя выданную ранее. Принимает на
+	вход модель enteties.UserRole, возвращает сохраненную роль с временем выдачи*/
+	SetRole(ctx context.Context, role *enteties.UserRole) (*enteties.UserRole, error)
+
+	/* метод отзывает выданную роль. Возвращает true, если роль была выдана*/
+	DeleteRole(ctx context.Context, userID string) (bool, error)
+
+	/* метод отзывает роль team_lead у пользователей, роль admin не меняется. Принимает на вход
+	список user_id, возвращает user_id тех, у кого роль team_lead была выдана, в порядке user_id*/
+	DeleteTeamLeadRoles(ctx context.Context, usersID []string) ([]string, error)
+
+	/* метод возвращает роль пользователя, без выданной роли - member. Существование
+	пользователя не проверяется. Принимает на вход user_id, возвращает модель enteties.UserRole*/
+	GetRole(ctx context.Context, userID string) (*enteties.UserRole, error)
+
+	/* метод возвращает выданные роли в порядке user_id. Принимает на вход название команды,
+	при непустом названии возвращаются только руководители этой команды*/
+	ListRoles(ctx context.Context, teamName string) ([]enteties.UserRole, error)
+}
+
+type rolePostgresRepository struct {
+	Db *pgxpool.Pool
+	sq squirrel.StatementBuilderType
+}
+
+func NewRolePostgresRepository(db *pgxpool.Pool) *rolePostgresRepository {
+	return &rolePostgresRepository{
+		Db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (rp *rolePostgresRepository) SetRole(ctx context.Context, role *enteties.UserRole) (*enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.SetRole")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, rp.Db)
+
+	// у admin команды нет, в базе данных NULL
+	var teamName *string
+	if role.TeamName != "" {
+		teamName = &role.TeamName
+	}
+
+	query := rp.sq.Insert("user_roles").
+		Columns("user_id", "role", "team_name", "granted_by").
+		Values(role.UserID, string(role.Role), teamName, role.GrantedBy).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET role = EXCLUDED.role, team_name = EXCLUDED.team_name,
+			granted_by = EXCLUDED.granted_by, granted_at = CURRENT_TIMESTAMP RETURNING granted_at`)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[RoleRepo | SetRole]: %w", err)
+	}
+
+	saved := *role
+	saved.GrantedAt = new(time.Time)
+
+	err = db.QueryRow(ctx, sql, args...).Scan(saved.GrantedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[RoleRepo | SetRole]: %w", err)
+	}
+
+	return &saved, nil
+}
+
+func (rp *rolePostgresRepository) DeleteRole(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.DeleteRole")
+	defer span.End()
+
+	query := `DELETE FROM user_roles WHERE user_id = $1`
+
+	tag, err := GetQuerier(ctx, rp.Db).Exec(ctx, query, userID)
+	if err != nil {
+		return false, fmt.Errorf("[RoleRepo | DeleteRole]: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (rp *rolePostgresRepository) DeleteTeamLeadRoles(ctx context.Context, usersID []string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.DeleteTeamLeadRoles")
+	defer span.End()
+
+	query := `DELETE FROM user_roles WHERE user_id = ANY($1) AND role = $2 RETURNING user_id`
+
+	rows, err := GetQuerier(ctx, rp.Db).Query(ctx, query, usersID, string(enteties.RoleTeamLead))
+	if err != nil {
+		return nil, fmt.Errorf("[RoleRepo | DeleteTeamLeadRoles]: %w", err)
+	}
+
+	defer rows.Close()
+
+	revoked := make([]string, 0)
+	for rows.Next() {
+		var userID string
+
+		err = rows.Scan(&userID)
+		if err != nil {
+			return nil, fmt.Errorf("[RoleRepo | DeleteTeamLeadRoles]: %w", err)
+		}
+
+		revoked = append(revoked, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[RoleRepo | DeleteTeamLeadRoles]: %w", err)
+	}
+
+	sort.Strings(revoked)
+
+	return revoked, nil
+}
+
+func (rp *rolePostgresRepository) GetRole(ctx context.Context, userID string) (*enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.GetRole")
+	defer span.End()
+
+	query := rp.sq.Select("user_id", "role", "COALESCE(team_name, '')", "granted_by", "granted_at").
+		From("user_roles").
+		Where(squirrel.Eq{"user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[RoleRepo | GetRole]: %w", err)
+	}
+
+	role, err := scanRole(GetQuerier(ctx, rp.Db).QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &enteties.UserRole{UserID: userID, Role: enteties.RoleMember}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[RoleRepo | GetRole]: %w", err)
+	}
+
+	return role, nil
+}
+
+func (rp *rolePostgresRepository) ListRoles(ctx context.Context, teamName string) ([]enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleRepository.ListRoles")
+	defer span.End()
+
+	query := rp.sq.Select("user_id", "role", "COALESCE(team_name, '')", "granted_by", "granted_at").
+		From("user_roles").
+		OrderBy("user_id")
+
+	if teamName != "" {
+		query = query.Where(squirrel.Eq{"team_name": teamName})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[RoleRepo | ListRoles]: %w", err)
+	}
+
+	rows, err := GetQuerier(ctx, rp.Db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[RoleRepo | ListRoles]: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]enteties.UserRole, 0)
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("[RoleRepo | ListRoles]: %w", err)
+		}
+
+		roles = append(roles, *role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[RoleRepo | ListRoles]: %w", err)
+	}
+
+	return roles, nil
+}
+
+// вспомогательная функция читает строку таблицы user_roles
+func scanRole(row pgx.Row) (*enteties.UserRole, error) {
+	var (
+		role      enteties.UserRole
+		roleName  string
+		grantedAt time.Time
+	)
+
+	err := row.Scan(&role.UserID, &roleName, &role.TeamName, &role.GrantedBy, &grantedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Role = enteties.Role(roleName)
+	role.GrantedAt = &grantedAt
+
+	return &role, nil
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+type statsMemoryRepository struct {
+	Storage *MemoryStorage
+}
+
+func NewStatsMemoryRepository(storage *MemoryStorage) *statsMemoryRepository {
+	return &statsMemoryRepository{
+		Storage: storage,
+	}
+}
+
+func (smr *statsMemoryRepository) GetReviewerStats(ctx context.Context, filter *enteties.ReviewerStatsFilter) ([]enteties.ReviewerStat, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.GetReviewerStats")
+	defer span.End()
+
+	unlock := smr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &smr.Storage.data
+
+	stats := make(map[string]*enteties.ReviewerStat)
+	result := make([]enteties.ReviewerStat, 0)
+	for _, user := range data.sortedUsers() {
+		if filter.TeamName != "" && user.TeamName != filter.TeamName {
+			continue
+		}
+
+		result = append(result, enteties.ReviewerStat{
+			UserID:   user.UserID,
+			UserName: user.UserName,
+			TeamName: user.TeamName,
+		})
+	}
+	for i := range result {
+		stats[result[i].UserID] = &result[i]
+	}
+
+	// текущие назначения учитываются по дате создания pull request
+	for _, pr := range data.prs {
+		if !inTimeRange(pr.CreatedAt, filter.From, filter.To) {
+			continue
+		}
+
+		for _, reviewer := range pr.AssignedReviewers {
+			stat, ok := stats[reviewer]
+			if !ok {
+				continue
+			}
+
+			stat.TotalAssignments++
+			switch pr.Status {
+			case enteties.PullRequestStatusOpen:
+				stat.OpenReviews++
+			case enteties.PullRequestStatusMerged:
+				stat.MergedReviews++
+			}
+		}
+	}
+
+	// снятия с ревью учитываются по дате события
+	for _, e := range data.events {
+		if e.EventType != enteties.AssignmentEventReassign && e.EventType != enteties.AssignmentEventUnassign {
+			continue
+		}
+
+		createdAt := e.CreatedAt
+		if !inTimeRange(&createdAt, filter.From, filter.To) {
+			continue
+		}
+
+		if stat, ok := stats[e.OldUserID]; ok {
+			stat.TotalAssignments++
+			stat.ReassignedAway++
+		}
+	}
+
+	return result, nil
+}
+
+func (smr *statsMemoryRepository) GetPullRequestStats(ctx context.Context, filter *enteties.PullRequestStatsFilter) ([]enteties.PullRequestGroupStats, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.GetPullRequestStats")
+	defer span.End()
+
+	unlock := smr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &smr.Storage.data
+
+	groups := make(map[string]*enteties.PullRequestGroupStats)
+	mergeTimes := make(map[string][]float64)
+	keys := make([]string, 0)
+	for _, pr := range data.prs {
+		author := data.users[pr.AuthorID]
+
+		if filter.TeamName != "" && author.TeamName != filter.TeamName {
+			continue
+		}
+
+		if !inTimeRange(pr.CreatedAt, filter.From, filter.To) {
+			continue
+		}
+
+		group, err := memoryStatsGroupKey(filter.GroupBy, pr, author)
+		if err != nil {
+			return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+		}
+
+		stats, ok := groups[group]
+		if !ok {
+			stats = &enteties.PullRequestGroupStats{
+				Group:        group,
+				StatusCounts: make(map[enteties.PullRequestStatus]int),
+			}
+			groups[group] = stats
+			keys = append(keys, group)
+		}
+
+		stats.StatusCounts[pr.Status]++
+		stats.Total++
+
+		if pr.Status == enteties.PullRequestStatusMerged && pr.CreatedAt != nil && pr.MergedAt != nil {
+			mergeTimes[group] = append(mergeTimes[group], pr.MergedAt.Sub(*pr.CreatedAt).Seconds())
+		}
+	}
+
+	for group, times := range mergeTimes {
+		sort.Float64s(times)
+
+		median := percentileCont(times, 0.5)
+		p90 := percentileCont(times, 0.9)
+		groups[group].MedianTimeToMerge = &median
+		groups[group].P90TimeToMerge = &p90
+	}
+
+	sort.Strings(keys)
+	result := make([]enteties.PullRequestGroupStats, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *groups[key])
+	}
+
+	return result, nil
+}
+
+func (smr *statsMemoryRepository) GetCurrentCounts(ctx context.Context) (*enteties.CurrentCounts, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.GetCurrentCounts")
+	defer span.End()
+
+	unlock := smr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &smr.Storage.data
+
+	var counts enteties.CurrentCounts
+	for _, pr := range data.prs {
+		if pr.Status == enteties.PullRequestStatusOpen {
+			counts.OpenPullRequests++
+		}
+	}
+
+	for _, user := range data.users {
+		if user.IsActive {
+			counts.ActiveUsers++
+		}
+	}
+
+	return &counts, nil
+}
+
+// вспомогательная функция проверяет, что время попадает в полуинтервал [from, to)
+func inTimeRange(t *time.Time, from, to *time.Time) bool {
+	if from == nil && to == nil {
+		return true
+	}
+
+	if t == nil {
+		return false
+	}
+
+	if from != nil && t.Before(*from) {
+		return false
+	}
+
+	if to != nil && !t.Before(*to) {
+		return false
+	}
+
+	return true
+}
+
+// вспомогательная функция возвращает ключ группировки статистики pull request
+func memoryStatsGroupKey(groupBy enteties.PullRequestStatsGroupBy, pr enteties.PullRequest, author enteties.User) (string, error) {
+	switch groupBy {
+	case enteties.PullRequestStatsGroupByTeam:
+		return author.TeamName, nil
+	case enteties.PullRequestStatsGroupByAuthor:
+		return pr.AuthorID, nil
+	case enteties.PullRequestStatsGroupByWeek:
+		if pr.CreatedAt == nil {
+			return "", nil
+		}
+
+		// неделя начинается с понедельника, как в date_trunc('week', ...)
+		day := time.Date(pr.CreatedAt.Year(), pr.CreatedAt.Month(), pr.CreatedAt.Day(), 0, 0, 0, 0, time.UTC)
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset).Format("2006-01-02"), nil
+	default:
+		return "", errors.New("unknown group_by: " + string(groupBy))
+	}
+}
+
+// вспомогательная функция считает перцентиль с линейной интерполяцией, как percentile_cont.
+// Принимает на вход отсортированные значения
+func percentileCont(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepository interface {
+	/* метод возвращает статистику ревью по каждому пользователю. Назначения учитываются по
+	дате создания pull request, переназначения по дате переназначения. Принимает на вход
+	модель enteties.ReviewerStatsFilter, возвращает список моделей enteties.ReviewerStat*/
+	GetReviewerStats(ctx context.Context, filter *enteties.ReviewerStatsFilter) ([]enteties.ReviewerStat, error)
+
+	/* метод возвращает количество pull request по статусам, медиану и 90-й перцентиль времени
+	от создания до мерджа в секундах для каждой группы (команды автора, автора или недели
+	создания). Принимает на вход модель enteties.PullRequestStatsFilter, возвращает список
+	моделей enteties.PullRequestGroupStats, отсортированный по группе*/
+	GetPullRequestStats(ctx context.Context, filter *enteties.PullRequestStatsFilter) ([]enteties.PullRequestGroupStats, error)
+
+	/* метод возвращает текущее количество OPEN pull request (включая черновики) и активных
+	пользователей, возвращает модель enteties.CurrentCounts*/
+	GetCurrentCounts(ctx context.Context) (*enteties.CurrentCounts, error)
+}
+
+type statsPostgresRepository struct {
+	Db *pgxpool.Pool
+	sq squirrel.StatementBuilderType
+}
+
+func NewStatsPostgresRepository(db *pgxpool.Pool) *statsPostgresRepository {
+	return &statsPostgresRepository{
+		Db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (sp *statsPostgresRepository) GetReviewerStats(ctx context.Context, filter *enteties.ReviewerStatsFilter) ([]enteties.ReviewerStat, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.GetReviewerStats")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, sp.Db)
+
+	// текущие назначения пользователей по статусам pull request
+	assignments := sp.sq.Select(
+		"ar.user_id",
+		"COUNT(*) AS total",
+		fmt.Sprintf("COUNT(*) FILTER (WHERE p.status = '%s') AS open", enteties.PullRequestStatusOpen),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE p.status = '%s') AS merged", enteties.PullRequestStatusMerged)).
+		From("assigned_reviewers ar").
+		Join("pull_requests p ON ar.pull_request_id = p.pull_request_id").
+		GroupBy("ar.user_id")
+
+	// количество снятий пользователя с ревью по истории назначений
+	reassignments := sp.sq.Select("e.old_user_id AS user_id", "COUNT(*) AS total").
+		From("assignment_events e").
+		Where(squirrel.Eq{"e.event_type": []enteties.AssignmentEventType{
+			enteties.AssignmentEventReassign, enteties.AssignmentEventUnassign}}).
+		GroupBy("e.old_user_id")
+
+	if filter.From != nil {
+		assignments = assignments.Where(squirrel.GtOrEq{"p.created_at": *filter.From})
+		reassignments = reassignments.Where(squirrel.GtOrEq{"e.created_at": *filter.From})
+	}
+
+	if filter.To != nil {
+		assignments = assignments.Where(squirrel.Lt{"p.created_at": *filter.To})
+		reassignments = reassignments.Where(squirrel.Lt{"e.created_at": *filter.To})
+	}
+
+	query := sp.sq.Select(
+		"u.user_id",
+		"u.username",
+		"COALESCE(u.team_name, '')",
+		"COALESCE(a.total, 0) + COALESCE(r.total, 0)",
+		"COALESCE(a.open, 0)",
+		"COALESCE(a.merged, 0)",
+		"COALESCE(r.total, 0)").
+		From("users u").
+		JoinClause(assignments.Prefix("LEFT JOIN (").Suffix(") a ON a.user_id = u.user_id")).
+		JoinClause(reassignments.Prefix("LEFT JOIN (").Suffix(") r ON r.user_id = u.user_id")).
+		OrderBy("u.user_id")
+
+	if filter.TeamName != "" {
+		query = query.Where(squirrel.Eq{"u.team_name": filter.TeamName})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetReviewerStats]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetReviewerStats]: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]enteties.ReviewerStat, 0)
+	for rows.Next() {
+		var stat enteties.ReviewerStat
+		err := rows.Scan(&stat.UserID, &stat.UserName, &stat.TeamName, &stat.TotalAssignments,
+			&stat.OpenReviews, &stat.MergedReviews, &stat.ReassignedAway)
+		if err != nil {
+			return nil, fmt.Errorf("[StatsRepo | GetReviewerStats]: %w", err)
+		}
+
+		result = append(result, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetReviewerStats]: %w", err)
+	}
+
+	return result, nil
+}
+
+func (sp *statsPostgresRepository) GetPullRequestStats(ctx context.Context, filter *enteties.PullRequestStatsFilter) ([]enteties.PullRequestGroupStats, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.GetPullRequestStats")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, sp.Db)
+
+	groupKey, err := prStatsGroupKey(filter.GroupBy)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+	}
+
+	// количество pull request по группам и статусам
+	counts := sp.filterPullRequests(sp.sq.Select(groupKey, "p.status", "COUNT(*)"), filter).
+		GroupBy("1", "2")
+
+	sql, args, err := counts.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make(map[string]*enteties.PullRequestGroupStats)
+	keys := make([]string, 0)
+	for rows.Next() {
+		var group string
+		var status enteties.PullRequestStatus
+		var count int
+		err := rows.Scan(&group, &status, &count)
+		if err != nil {
+			return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+		}
+
+		stats, ok := groups[group]
+		if !ok {
+			stats = &enteties.PullRequestGroupStats{
+				Group:        group,
+				StatusCounts: make(map[enteties.PullRequestStatus]int),
+			}
+			groups[group] = stats
+			keys = append(keys, group)
+		}
+
+		stats.StatusCounts[status] += count
+		stats.Total += count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+	}
+
+	// медиана и 90-й перцентиль времени до мерджа по группам
+	timeToMerge := "EXTRACT(EPOCH FROM p.merged_at - p.created_at)::float8"
+	percentiles := sp.filterPullRequests(sp.sq.Select(
+		groupKey,
+		fmt.Sprintf("percentile_cont(0.5) WITHIN GROUP (ORDER BY %s)", timeToMerge),
+		fmt.Sprintf("percentile_cont(0.9) WITHIN GROUP (ORDER BY %s)", timeToMerge)), filter).
+		Where(squirrel.Eq{"p.status": enteties.PullRequestStatusMerged}).
+		Where(squirrel.NotEq{"p.merged_at": nil}).
+		Where(squirrel.NotEq{"p.created_at": nil}).
+		GroupBy("1")
+
+	sql, args, err = percentiles.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+	}
+
+	mergeRows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+	}
+	defer mergeRows.Close()
+
+	for mergeRows.Next() {
+		var group string
+		var median, p90 float64
+		err := mergeRows.Scan(&group, &median, &p90)
+		if err != nil {
+			return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+		}
+
+		// группа всегда есть в первом запросе, так как фильтры одинаковые
+		if stats, ok := groups[group]; ok {
+			stats.MedianTimeToMerge = &median
+			stats.P90TimeToMerge = &p90
+		}
+	}
+
+	if err := mergeRows.Err(); err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetPullRequestStats]: %w", err)
+	}
+
+	sort.Strings(keys)
+	result := make([]enteties.PullRequestGroupStats, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *groups[key])
+	}
+
+	return result, nil
+}
+
+// вспомогательный метод добавляет к запросу по pull_requests общие фильтры статистики
+func (sp *statsPostgresRepository) filterPullRequests(query squirrel.SelectBuilder, filter *enteties.PullRequestStatsFilter) squirrel.SelectBuilder {
+	query = query.From("pull_requests p").
+		LeftJoin("users u ON p.author_id = u.user_id")
+
+	if filter.TeamName != "" {
+		query = query.Where(squirrel.Eq{"u.team_name": filter.TeamName})
+	}
+
+	if filter.From != nil {
+		query = query.Where(squirrel.GtOrEq{"p.created_at": *filter.From})
+	}
+
+	if filter.To != nil {
+		query = query.Where(squirrel.Lt{"p.created_at": *filter.To})
+	}
+
+	return query
+}
+
+// вспомогательная функция возвращает SQL выражение ключа группировки статистики pull request
+func prStatsGroupKey(groupBy enteties.PullRequestStatsGroupBy) (string, error) {
+	switch groupBy {
+	case enteties.PullRequestStatsGroupByTeam:
+		return "COALESCE(u.team_name, '')", nil
+	case enteties.PullRequestStatsGroupByAuthor:
+		return "p.author_id", nil
+	case enteties.PullRequestStatsGroupByWeek:
+		return "COALESCE(to_char(date_trunc('week', p.created_at), 'YYYY-MM-DD'), '')", nil
+	default:
+		return "", errors.New("unknown group_by: " + string(groupBy))
+	}
+}
+
+func (sp *statsPostgresRepository) GetCurrentCounts(ctx context.Context) (*enteties.CurrentCounts, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.GetCurrentCounts")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, sp.Db)
+
+	openPRs := sp.sq.Select("COUNT(*)").From("pull_requests").
+		Where(squirrel.Eq{"status": enteties.PullRequestStatusOpen})
+	activeUsers := sp.sq.Select("COUNT(*)").From("users").
+		Where(squirrel.Eq{"is_active": true})
+
+	openSQL, openArgs, err := openPRs.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetCurrentCounts]: %w", err)
+	}
+
+	usersSQL, usersArgs, err := activeUsers.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetCurrentCounts]: %w", err)
+	}
+
+	var counts enteties.CurrentCounts
+
+	err = db.QueryRow(ctx, openSQL, openArgs...).Scan(&counts.OpenPullRequests)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetCurrentCounts]: %w", err)
+	}
+
+	err = db.QueryRow(ctx, usersSQL, usersArgs...).Scan(&counts.ActiveUsers)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsRepo | GetCurrentCounts]: %w", err)
+	}
+
+	return &counts, nil
+}
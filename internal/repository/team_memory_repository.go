@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+)
+
+type teamMemoryRepository struct {
+	Storage *MemoryStorage
+}
+
+func NewTeamMemoryRepository(storage *MemoryStorage) *teamMemoryRepository {
+	return &teamMemoryRepository{
+		Storage: storage,
+	}
+}
+
+func (tmr *teamMemoryRepository) CreateTeam(ctx context.Context, teamName string) (string, error) {
+	ctx, span := tracing.Start(ctx, "TeamRepository.CreateTeam")
+	defer span.End()
+
+	unlock := tmr.Storage.lock(ctx)
+	defer unlock()
+
+	if _, ok := tmr.Storage.data.teams[teamName]; ok {
+		return "", fmt.Errorf("[TeamRepo | CreateTeam]: %w", ErrorDuplicate)
+	}
+
+	tmr.Storage.data.teams[teamName] = struct{}{}
+
+	return teamName, nil
+}
+
+func (tmr *teamMemoryRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "TeamRepository.TeamExists")
+	defer span.End()
+
+	unlock := tmr.Storage.lock(ctx)
+	defer unlock()
+
+	_, ok := tmr.Storage.data.teams[teamName]
+	return ok, nil
+}
+
+func (tmr *teamMemoryRepository) GetTeamSettings(ctx context.Context, teamName string) (*enteties.TeamSettings, bool, error) {
+	ctx, span := tracing.Start(ctx, "TeamRepository.GetTeamSettings")
+	defer span.End()
+
+	unlock := tmr.Storage.lock(ctx)
+	defer unlock()
+
+	settings, ok := tmr.Storage.data.teamSettings[teamName]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return &settings, true, nil
+}
+
+func (tmr *teamMemoryRepository) SetTeamSettings(ctx context.Context, settings *enteties.TeamSettings) (*enteties.TeamSettings, error) {
+	ctx, span := tracing.Start(ctx, "TeamRepository.SetTeamSettings")
+	defer span.End()
+
+	unlock := tmr.Storage.lock(ctx)
+	defer unlock()
+
+	if _, ok := tmr.Storage.data.teams[settings.TeamName]; !ok {
+		return nil, fmt.Errorf("[TeamRepo | SetTeamSettings]: %w", errMemoryForeignKey)
+	}
+
+	// ограничения таблицы team_settings
+	if settings.MinReviewers < 0 || settings.MaxReviewers < settings.MinReviewers {
+		return nil, fmt.Errorf("[TeamRepo | SetTeamSettings]: %w", errMemoryInvalidValue)
+	}
+
+	saved := *settings
+	tmr.Storage.data.teamSettings[settings.TeamName] = saved
+
+	return &saved, nil
+}
+
+func (tmr *teamMemoryRepository) DeleteTeam(ctx context.Context, teamName string) error {
+	ctx, span := tracing.Start(ctx, "TeamRepository.DeleteTeam")
+	defer span.End()
+
+	unlock := tmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &tmr.Storage.data
+
+	delete(data.teams, teamName)
+	delete(data.teamSettings, teamName)
+
+	for id, webhook := range data.webhooks {
+		if webhook.TeamName == teamName {
+			data.deleteWebhook(id)
+		}
+	}
+
+	// руководители команды теряют роль вместе с командой
+	for id, role := range data.roles {
+		if role.TeamName == teamName {
+			delete(data.roles, id)
+		}
+	}
+
+	// оставшиеся участники удаляются вместе с командой, как по ON DELETE CASCADE в базе данных
+	for _, user := range data.sortedUsers() {
+		if user.TeamName == teamName {
+			data.deleteUser(user.UserID)
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,15 @@
 package repository
 
 import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type TeamRepository interface {
@@ -16,14 +20,25 @@ type TeamRepository interface {
 	/* метод возвращает true, если команда уже существует, false, если нет.
 	Принимает на вход название команды */
 	TeamExists(ctx context.Context, teamName string) (bool, error)
+
+	/* метод возвращает настройки команды в виде модели enteties.TeamSettings и true, если
+	они заданы, иначе false. Принимает на вход название команды */
+	GetTeamSettings(ctx context.Context, teamName string) (*enteties.TeamSettings, bool, error)
+
+	/* метод создает или обновляет настройки команды в таблице team_settings. Принимает на вход
+	модель enteties.TeamSettings, возвращает сохраненные настройки*/
+	SetTeamSettings(ctx context.Context, settings *enteties.TeamSettings) (*enteties.TeamSettings, error)
+
+	/* метод удаляет запись о команде из таблицы teams. Принимает на вход название команды*/
+	DeleteTeam(ctx context.Context, teamName string) error
 }
 
 type teamPostgresRepository struct {
-	Db *pgx.Conn
+	Db *pgxpool.Pool
 	sq squirrel.StatementBuilderType
 }
 
-func NewTeamPostgresRepository(db *pgx.Conn) *teamPostgresRepository {
+func NewTeamPostgresRepository(db *pgxpool.Pool) *teamPostgresRepository {
 	return &teamPostgresRepository{
 		Db: db,
 		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
@@ -31,11 +46,11 @@ func NewTeamPostgresRepository(db *pgx.Conn) *teamPostgresRepository {
 }
 
 func (tp *teamPostgresRepository) CreateTeam(ctx context.Context, teamName string) (string, error) {
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return "", fmt.Errorf("[TeamRepo | CreateTeam]: can not get pgx.Tx")
-	}
+	ctx, span := tracing.Start(ctx, "TeamRepository.CreateTeam")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, tp.Db)
 
 	// создадим запись в таблице teams
 	query := tp.sq.Insert("teams").
@@ -47,7 +62,7 @@ func (tp *teamPostgresRepository) CreateTeam(ctx context.Context, teamName strin
 		return "", fmt.Errorf("[TeamRepo | CreateTeam]: %w", err)
 	}
 
-	_, err = tx.Exec(ctx, sql, args...)
+	_, err = db.Exec(ctx, sql, args...)
 	if err != nil {
 		return "", fmt.Errorf("[TeamRepo | CreateTeam]: %w", err)
 	}
@@ -56,11 +71,93 @@ func (tp *teamPostgresRepository) CreateTeam(ctx context.Context, teamName strin
 }
 
 func (tp *teamPostgresRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "TeamRepository.TeamExists")
+	defer span.End()
+
 	query := "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)"
 	var exists bool
-	err := tp.Db.QueryRow(ctx, query, teamName).Scan(&exists)
+	err := GetQuerier(ctx, tp.Db).QueryRow(ctx, query, teamName).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("[TeamRepo | TeamExists]: %w", err)
 	}
 	return exists, nil
 }
+
+func (tp *teamPostgresRepository) GetTeamSettings(ctx context.Context, teamName string) (*enteties.TeamSettings, bool, error) {
+	ctx, span := tracing.Start(ctx, "TeamRepository.GetTeamSettings")
+	defer span.End()
+
+	query := tp.sq.Select("min_reviewers", "max_reviewers").
+		From("team_settings").
+		Where(squirrel.Eq{"team_name": teamName})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("[TeamRepo | GetTeamSettings]: %w", err)
+	}
+
+	settings := enteties.TeamSettings{
+		TeamName: teamName,
+	}
+
+	err = GetQuerier(ctx, tp.Db).QueryRow(ctx, sql, args...).Scan(&settings.MinReviewers, &settings.MaxReviewers)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("[TeamRepo | GetTeamSettings]: %w", err)
+	}
+
+	return &settings, true, nil
+}
+
+func (tp *teamPostgresRepository) SetTeamSettings(ctx context.Context, settings *enteties.TeamSettings) (*enteties.TeamSettings, error) {
+	ctx, span := tracing.Start(ctx, "TeamRepository.SetTeamSettings")
+	defer span.End()
+
+	query := tp.sq.Insert("team_settings").
+		Columns("team_name", "min_reviewers", "max_reviewers").
+		Values(settings.TeamName, settings.MinReviewers, settings.MaxReviewers).
+		Suffix(`ON CONFLICT (team_name) DO UPDATE
+		SET min_reviewers = EXCLUDED.min_reviewers, max_reviewers = EXCLUDED.max_reviewers
+		RETURNING min_reviewers, max_reviewers`)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[TeamRepo | SetTeamSettings]: %w", err)
+	}
+
+	saved := enteties.TeamSettings{
+		TeamName: settings.TeamName,
+	}
+
+	err = GetQuerier(ctx, tp.Db).QueryRow(ctx, sql, args...).Scan(&saved.MinReviewers, &saved.MaxReviewers)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamRepo | SetTeamSettings]: %w", err)
+	}
+
+	return &saved, nil
+}
+
+func (tp *teamPostgresRepository) DeleteTeam(ctx context.Context, teamName string) error {
+	ctx, span := tracing.Start(ctx, "TeamRepository.DeleteTeam")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, tp.Db)
+
+	query := tp.sq.Delete("teams").
+		Where(squirrel.Eq{"team_name": teamName})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[TeamRepo | DeleteTeam]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[TeamRepo | DeleteTeam]: %w", err)
+	}
+
+	return nil
+}
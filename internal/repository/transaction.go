@@ -2,14 +2,27 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type contextKey string
 
 const txKey contextKey = "pgx_tx"
 
+// Querier общий интерфейс pgx.Tx и pgxpool.Pool, через который репозитории выполняют запросы
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
 // WithTx добавляет pgx.Tx в контекст
 func WithTx(ctx context.Context, tx pgx.Tx) context.Context {
 	return context.WithValue(ctx, txKey, tx)
@@ -20,3 +33,142 @@ func GetTx(ctx context.Context) (pgx.Tx, bool) {
 	tx, ok := ctx.Value(txKey).(pgx.Tx)
 	return tx, ok
 }
+
+// GetQuerier возвращает транзакцию из контекста, если она есть, иначе пул соединений
+func GetQuerier(ctx context.Context, pool *pgxpool.Pool) Querier {
+	if tx, ok := GetTx(ctx); ok {
+		return tx
+	}
+	return pool
+}
+
+// уровни изоляции транзакций
+const (
+	IsolationReadCommitted  = "read_committed"
+	IsolationRepeatableRead = "repeatable_read"
+	IsolationSerializable   = "serializable"
+)
+
+// коды ошибок Postgres, после которых транзакцию можно безопасно повторить
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// код ошибки Postgres при нарушении уникальности
+const pgUniqueViolation = "23505"
+
+// ErrorDuplicate запись с таким ключом уже есть, например ее вставила параллельная транзакция
+// после проверки существования
+var ErrorDuplicate = errors.New("duplicate key value")
+
+// TxManager выполняет функцию в транзакции хранилища. Репозитории находят транзакцию через
+// контекст, поэтому сервисы не зависят от конкретного хранилища. Если функция вернула ошибку,
+// транзакция откатывается
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type postgresTxManager struct {
+	Db         *pgxpool.Pool
+	IsoLevel   pgx.TxIsoLevel
+	MaxRetries int
+	RetryDelay time.Duration
+}
+
+// конструктор принимает уровень изоляции (read_committed, repeatable_read, serializable) и
+// количество повторов транзакции при ошибках сериализации и дедлоках
+func NewPostgresTxManager(db *pgxpool.Pool, isolation string, maxRetries int) (*postgresTxManager, error) {
+	isoLevel, err := parseIsolationLevel(isolation)
+	if err != nil {
+		return nil, fmt.Errorf("[TxManager | NewPostgresTxManager]: %w", err)
+	}
+
+	if maxRetries < 0 {
+		return nil, fmt.Errorf("[TxManager | NewPostgresTxManager]: negative max retries %d", maxRetries)
+	}
+
+	return &postgresTxManager{
+		Db:         db,
+		IsoLevel:   isoLevel,
+		MaxRetries: maxRetries,
+		RetryDelay: 10 * time.Millisecond,
+	}, nil
+}
+
+func (tm *postgresTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// вложенный вызов выполняется в уже открытой транзакции, повторяет ее внешний вызов
+	if _, ok := GetTx(ctx); ok {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 0; attempt <= tm.MaxRetries; attempt++ {
+		if attempt > 0 {
+			// экспоненциальная задержка перед повтором
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("[TxManager | WithinTransaction]: %w", errors.Join(err, ctx.Err()))
+			case <-time.After(tm.RetryDelay << (attempt - 1)):
+			}
+		}
+
+		err = tm.runTx(ctx, fn)
+		if err == nil || !isRetryableTxError(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("[TxManager | WithinTransaction]: retries exhausted: %w", err)
+}
+
+// выполняет одну попытку транзакции
+func (tm *postgresTxManager) runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := tm.Db.BeginTx(ctx, pgx.TxOptions{IsoLevel: tm.IsoLevel})
+	if err != nil {
+		return fmt.Errorf("[TxManager | WithinTransaction]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = fn(WithTx(ctx, tx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("[TxManager | WithinTransaction]: %w", err)
+	}
+
+	return nil
+}
+
+// вспомогательная функция возвращает true для ошибок сериализации и дедлоков
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+}
+
+// вспомогательная функция возвращает true для нарушения уникального ключа
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// вспомогательная функция переводит название уровня изоляции в pgx.TxIsoLevel
+func parseIsolationLevel(isolation string) (pgx.TxIsoLevel, error) {
+	switch isolation {
+	case IsolationReadCommitted:
+		return pgx.ReadCommitted, nil
+	case IsolationRepeatableRead:
+		return pgx.RepeatableRead, nil
+	case IsolationSerializable:
+		return pgx.Serializable, nil
+	default:
+		return "", errors.New("unknown isolation level: " + isolation)
+	}
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+)
+
+type userMemoryRepository struct {
+	Storage *MemoryStorage
+}
+
+func NewUserMemoryRepository(storage *MemoryStorage) *userMemoryRepository {
+	return &userMemoryRepository{
+		Storage: storage,
+	}
+}
+
+func (umr *userMemoryRepository) CreateUser(ctx context.Context, user *enteties.User) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.CreateUser")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &umr.Storage.data
+
+	if _, ok := data.users[user.UserID]; ok {
+		return nil, fmt.Errorf("[UserRepo | CreateUser]: %w", ErrorDuplicate)
+	}
+
+	if usernameTaken(data, user.UserName, "") {
+		return nil, fmt.Errorf("[UserRepo | CreateUser]: %w", ErrorDuplicate)
+	}
+
+	if _, ok := data.teams[user.TeamName]; user.TeamName != "" && !ok {
+		return nil, fmt.Errorf("[UserRepo | CreateUser]: %w", errMemoryForeignKey)
+	}
+
+	data.users[user.UserID] = *user
+
+	return user, nil
+}
+
+func (umr *userMemoryRepository) SetUserStatus(ctx context.Context, userID string, newStatus bool) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUserStatus")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	user, ok := umr.Storage.data.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("[UserRepo | SetUserStatus]: %w", errMemoryNotFound)
+	}
+
+	user.IsActive = newStatus
+	umr.Storage.data.users[userID] = user
+
+	return &user, nil
+}
+
+func (umr *userMemoryRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.UserExists")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	_, ok := umr.Storage.data.users[userID]
+	return ok, nil
+}
+
+func (umr *userMemoryRepository) UserExistsByUsername(ctx context.Context, userName string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.UserExistsByUsername")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	return usernameTaken(&umr.Storage.data, userName, ""), nil
+}
+
+func (umr *userMemoryRepository) GetTeamMembersByTeamName(ctx context.Context, teamName string) ([]*enteties.TeamMember, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetTeamMembersByTeamName")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	teamMembers := make([]*enteties.TeamMember, 0)
+	for _, user := range umr.Storage.data.sortedUsers() {
+		if user.TeamName == "" || user.TeamName != teamName {
+			continue
+		}
+
+		teamMembers = append(teamMembers, &enteties.TeamMember{
+			UserID:   user.UserID,
+			UserName: user.UserName,
+			IsActive: user.IsActive,
+		})
+	}
+
+	return teamMembers, nil
+}
+
+func (umr *userMemoryRepository) GetUserTeamName(ctx context.Context, userID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUserTeamName")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	user, ok := umr.Storage.data.users[userID]
+	if !ok {
+		return "", fmt.Errorf("[UserRepo | GetUsersTeamName]: %w", errMemoryNotFound)
+	}
+
+	return user.TeamName, nil
+}
+
+func (umr *userMemoryRepository) GetUser(ctx context.Context, userID string) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUser")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	user, ok := umr.Storage.data.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("[UserRepo | GetUser]: %w", errMemoryNotFound)
+	}
+
+	return &user, nil
+}
+
+func (umr *userMemoryRepository) SetUsername(ctx context.Context, userID, userName string) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUsername")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &umr.Storage.data
+
+	user, ok := data.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("[UserRepo | SetUsername]: %w", errMemoryNotFound)
+	}
+
+	if usernameTaken(data, userName, userID) {
+		return nil, fmt.Errorf("[UserRepo | SetUsername]: %w", ErrorDuplicate)
+	}
+
+	user.UserName = userName
+	data.users[userID] = user
+
+	return &user, nil
+}
+
+func (umr *userMemoryRepository) SetUserTeam(ctx context.Context, userID, teamName string) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUserTeam")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &umr.Storage.data
+
+	if _, ok := data.teams[teamName]; !ok {
+		return fmt.Errorf("[UserRepo | SetUserTeam]: %w", errMemoryForeignKey)
+	}
+
+	if user, ok := data.users[userID]; ok {
+		user.TeamName = teamName
+		data.users[userID] = user
+	}
+
+	return nil
+}
+
+func (umr *userMemoryRepository) RemoveUserFromTeam(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.RemoveUserFromTeam")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	if user, ok := umr.Storage.data.users[userID]; ok {
+		user.TeamName = ""
+		umr.Storage.data.users[userID] = user
+	}
+
+	return nil
+}
+
+func (umr *userMemoryRepository) GetActiveUsersOutsideTeam(ctx context.Context, teamName string) ([]*enteties.TeamMember, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetActiveUsersOutsideTeam")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	users := make([]*enteties.TeamMember, 0)
+	for _, user := range umr.Storage.data.sortedUsers() {
+		// пользователи без команды не учитываются, как и в запросе к базе данных
+		if !user.IsActive || user.TeamName == "" || user.TeamName == teamName {
+			continue
+		}
+
+		users = append(users, &enteties.TeamMember{
+			UserID:   user.UserID,
+			UserName: user.UserName,
+			IsActive: user.IsActive,
+		})
+	}
+
+	return users, nil
+}
+
+func (umr *userMemoryRepository) GetUsers(ctx context.Context, usersID []string) ([]*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUsers")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	users := make([]*enteties.User, 0, len(usersID))
+	seen := make(map[string]struct{}, len(usersID))
+	for _, userID := range usersID {
+		if _, ok := seen[userID]; ok {
+			continue
+		}
+		seen[userID] = struct{}{}
+
+		if user, ok := umr.Storage.data.users[userID]; ok {
+			users = append(users, &user)
+		}
+	}
+
+	return users, nil
+}
+
+func (umr *userMemoryRepository) SetUsersStatusBatch(ctx context.Context, usersID []string, newStatus bool) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUsersStatusBatch")
+	defer span.End()
+
+	unlock := umr.Storage.lock(ctx)
+	defer unlock()
+
+	for _, userID := range usersID {
+		if user, ok := umr.Storage.data.users[userID]; ok {
+			user.IsActive = newStatus
+			umr.Storage.data.users[userID] = user
+		}
+	}
+
+	return nil
+}
+
+func (umr *userMemoryRepository) LockTeamMembers(ctx context.Context, teamName string) ([]*enteties.TeamMember, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.LockTeamMembers")
+	defer span.End()
+
+	// транзакции in-memory хранилища и так выполняются по одной
+	return umr.GetTeamMembersByTeamName(ctx, teamName)
+}
+
+// вспомогательная функция проверяет, занят ли username другим пользователем
+func usernameTaken(data *memoryData, userName, exceptUserID string) bool {
+	for _, user := range data.users {
+		if user.UserName == userName && user.UserID != exceptUserID {
+			return true
+		}
+	}
+	return false
+}
@@ -2,11 +2,12 @@ package repository
 
 import (
 	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
 	"context"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserRepository interface {
@@ -33,14 +34,47 @@ type UserRepository interface {
 	/* метод возвращает название команды, в которой состоит пользователь.
 	Принимает на вход user_id*/
 	GetUserTeamName(ctx context.Context, userID string) (string, error)
+
+	/* метод возвращает пользователя в виде модели enteties.User. Принимает на вход user_id*/
+	GetUser(ctx context.Context, userID string) (*enteties.User, error)
+
+	/* метод изменяет username пользователя. Принимает на вход user_id и новый username,
+	возвращает модель enteties.User*/
+	SetUsername(ctx context.Context, userID, userName string) (*enteties.User, error)
+
+	/* метод переводит пользователя в другую команду. Принимает на вход user_id и
+	название команды*/
+	SetUserTeam(ctx context.Context, userID, teamName string) error
+
+	/* метод исключает пользователя из команды, сам пользователь и его pull request
+	сохраняются. Принимает на вход user_id*/
+	RemoveUserFromTeam(ctx context.Context, userID string) error
+
+	/* метод возвращает активных пользователей, которые не состоят в команде teamName (и
+	состоят в какой-либо другой команде). Возвращает список моделей enteties.TeamMember*/
+	GetActiveUsersOutsideTeam(ctx context.Context, teamName string) ([]*enteties.TeamMember, error)
+
+	/* метод возвращает существующих пользователей из списка. Принимает на вход список
+	user_id, возвращает список моделей enteties.User*/
+	GetUsers(ctx context.Context, usersID []string) ([]*enteties.User, error)
+
+	/* метод устанавливает поле is_active сразу у списка пользователей. Принимает на вход
+	список user_id и новое значение статуса*/
+	SetUsersStatusBatch(ctx context.Context, usersID []string, newStatus bool) error
+
+	/* метод возвращает участников команды, как GetTeamMembersByTeamName, и блокирует их строки
+	до конца транзакции (SELECT ... FOR SHARE), чтобы параллельная деактивация или перевод
+	участника дождались назначения ревьюеров. Должен вызываться внутри транзакции (tx в
+	контексте). Принимает на вход название команды, возвращает список моделей enteties.TeamMember*/
+	LockTeamMembers(ctx context.Context, teamName string) ([]*enteties.TeamMember, error)
 }
 
 type userPostgresRepository struct {
-	Db *pgx.Conn
+	Db *pgxpool.Pool
 	sq squirrel.StatementBuilderType
 }
 
-func NewUserPostgresRepository(db *pgx.Conn) *userPostgresRepository {
+func NewUserPostgresRepository(db *pgxpool.Pool) *userPostgresRepository {
 	return &userPostgresRepository{
 		Db: db,
 		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
@@ -48,12 +82,11 @@ func NewUserPostgresRepository(db *pgx.Conn) *userPostgresRepository {
 }
 
 func (urp *userPostgresRepository) CreateUser(ctx context.Context, user *enteties.User) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.CreateUser")
+	defer span.End()
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return nil, fmt.Errorf("[UserRepo | CreateUser]: can not get pgx.Tx")
-	}
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, urp.Db)
 
 	query := urp.sq.Insert("users").
 		Columns("user_id", "username", "team_name", "is_active").
@@ -64,7 +97,7 @@ func (urp *userPostgresRepository) CreateUser(ctx context.Context, user *entetie
 		return nil, fmt.Errorf("[UserRepo | CreateUser]: %w", err)
 	}
 
-	_, err = tx.Exec(ctx, sql, args...)
+	_, err = db.Exec(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("[UserRepo | CreateUser]: %w", err)
 	}
@@ -73,18 +106,20 @@ func (urp *userPostgresRepository) CreateUser(ctx context.Context, user *entetie
 }
 
 func (urp *userPostgresRepository) SetUserStatus(ctx context.Context, userID string, newStatus bool) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUserStatus")
+	defer span.End()
 
 	query := urp.sq.Update("users").
 		Set("is_active", newStatus).
 		Where(squirrel.Eq{"user_id": userID}).
-		Suffix("RETURNING username , team_name,is_active")
+		Suffix("RETURNING username, COALESCE(team_name, ''), is_active")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("[UserRepo | SetUserStatus]: %w", err)
 	}
 
-	row := urp.Db.QueryRow(ctx, sql, args...)
+	row := GetQuerier(ctx, urp.Db).QueryRow(ctx, sql, args...)
 
 	var userResponce enteties.User
 	userResponce.UserID = userID
@@ -99,10 +134,12 @@ func (urp *userPostgresRepository) SetUserStatus(ctx context.Context, userID str
 }
 
 func (urp *userPostgresRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.UserExists")
+	defer span.End()
 
 	query := "SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)"
 	var exists bool
-	err := urp.Db.QueryRow(ctx, query, userID).Scan(&exists)
+	err := GetQuerier(ctx, urp.Db).QueryRow(ctx, query, userID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("[UserRepo | UserExists]: %w", err)
 	}
@@ -110,10 +147,12 @@ func (urp *userPostgresRepository) UserExists(ctx context.Context, userID string
 }
 
 func (urp *userPostgresRepository) UserExistsByUsername(ctx context.Context, userName string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.UserExistsByUsername")
+	defer span.End()
 
 	query := "SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)"
 	var exists bool
-	err := urp.Db.QueryRow(ctx, query, userName).Scan(&exists)
+	err := GetQuerier(ctx, urp.Db).QueryRow(ctx, query, userName).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("[UserRepo | UserExistsByUsername]: %w", err)
 	}
@@ -121,13 +160,36 @@ func (urp *userPostgresRepository) UserExistsByUsername(ctx context.Context, use
 }
 
 func (urp *userPostgresRepository) GetTeamMembersByTeamName(ctx context.Context, teamName string) ([]*enteties.TeamMember, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetTeamMembersByTeamName")
+	defer span.End()
 
-	// получим транзакцию из контекста
-	tx, ok := GetTx(ctx)
-	if !ok {
-		return nil, fmt.Errorf("[UserRepo | GetTeamMembersByTeamName]: can not get pgx.Tx")
+	teamMembers, err := urp.queryTeamMembers(ctx, teamName, "")
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetTeamMembersByTeamName]: %w", err)
 	}
 
+	return teamMembers, nil
+}
+
+func (urp *userPostgresRepository) LockTeamMembers(ctx context.Context, teamName string) ([]*enteties.TeamMember, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.LockTeamMembers")
+	defer span.End()
+
+	// FOR SHARE не мешает другим назначениям, но UPDATE строк участников будет ждать конца транзакции
+	teamMembers, err := urp.queryTeamMembers(ctx, teamName, "ORDER BY user_id FOR SHARE")
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | LockTeamMembers]: %w", err)
+	}
+
+	return teamMembers, nil
+}
+
+// вспомогательный метод для получения участников команды, suffix дописывается в конец запроса
+func (urp *userPostgresRepository) queryTeamMembers(ctx context.Context, teamName, suffix string) ([]*enteties.TeamMember, error) {
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, urp.Db)
+
 	teamMembers := make([]*enteties.TeamMember, 0)
 
 	query := urp.sq.Select(
@@ -137,38 +199,256 @@ func (urp *userPostgresRepository) GetTeamMembersByTeamName(ctx context.Context,
 		From("users").
 		Where(squirrel.Eq{"team_name": teamName})
 
+	if suffix != "" {
+		query = query.Suffix(suffix)
+	}
+
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("[UserRepo | GetTeamMembersByTeamName]: %w", err)
+		return nil, fmt.Errorf("[UserRepo | queryTeamMembers]: %w", err)
 	}
 
-	rows, err := tx.Query(ctx, sql, args...)
+	rows, err := db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("[UserRepo | GetTeamMembersByTeamName]: %w", err)
+		return nil, fmt.Errorf("[UserRepo | queryTeamMembers]: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var tm enteties.TeamMember
 
-		rows.Scan(&tm.UserID, &tm.UserName, &tm.IsActive)
+		err := rows.Scan(&tm.UserID, &tm.UserName, &tm.IsActive)
 		if err != nil {
-			return nil, fmt.Errorf("[UserRepo | GetTeamMembersByTeamName]: %w", err)
+			return nil, fmt.Errorf("[UserRepo | queryTeamMembers]: %w", err)
 		}
 
 		teamMembers = append(teamMembers, &tm)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[UserRepo | queryTeamMembers]: %w", err)
+	}
+
 	return teamMembers, nil
 }
 
 func (urp *userPostgresRepository) GetUserTeamName(ctx context.Context, userID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUserTeamName")
+	defer span.End()
+
 	var teamName string
-	query := `SELECT team_name FROM users WHERE user_id = $1`
+	query := `SELECT COALESCE(team_name, '') FROM users WHERE user_id = $1`
 
-	err := urp.Db.QueryRow(ctx, query, userID).Scan(&teamName)
+	err := GetQuerier(ctx, urp.Db).QueryRow(ctx, query, userID).Scan(&teamName)
 	if err != nil {
 		return "", fmt.Errorf("[UserRepo | GetUsersTeamName]: %w", err)
 	}
 
 	return teamName, nil
 }
+
+func (urp *userPostgresRepository) GetUser(ctx context.Context, userID string) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUser")
+	defer span.End()
+
+	query := urp.sq.Select("user_id", "username", "COALESCE(team_name, '')", "is_active").
+		From("users").
+		Where(squirrel.Eq{"user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetUser]: %w", err)
+	}
+
+	var user enteties.User
+
+	err = GetQuerier(ctx, urp.Db).QueryRow(ctx, sql, args...).Scan(&user.UserID, &user.UserName, &user.TeamName, &user.IsActive)
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetUser]: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (urp *userPostgresRepository) SetUsername(ctx context.Context, userID, userName string) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUsername")
+	defer span.End()
+
+	query := urp.sq.Update("users").
+		Set("username", userName).
+		Where(squirrel.Eq{"user_id": userID}).
+		Suffix("RETURNING username, COALESCE(team_name, ''), is_active")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | SetUsername]: %w", err)
+	}
+
+	row := GetQuerier(ctx, urp.Db).QueryRow(ctx, sql, args...)
+
+	var userResponce enteties.User
+	userResponce.UserID = userID
+
+	err = row.Scan(&userResponce.UserName, &userResponce.TeamName, &userResponce.IsActive)
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | SetUsername]: %w", err)
+	}
+
+	return &userResponce, nil
+}
+
+func (urp *userPostgresRepository) SetUserTeam(ctx context.Context, userID, teamName string) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUserTeam")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, urp.Db)
+
+	query := urp.sq.Update("users").
+		Set("team_name", teamName).
+		Where(squirrel.Eq{"user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[UserRepo | SetUserTeam]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[UserRepo | SetUserTeam]: %w", err)
+	}
+
+	return nil
+}
+
+func (urp *userPostgresRepository) RemoveUserFromTeam(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.RemoveUserFromTeam")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, urp.Db)
+
+	query := urp.sq.Update("users").
+		Set("team_name", nil).
+		Where(squirrel.Eq{"user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[UserRepo | RemoveUserFromTeam]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[UserRepo | RemoveUserFromTeam]: %w", err)
+	}
+
+	return nil
+}
+
+func (urp *userPostgresRepository) GetActiveUsersOutsideTeam(ctx context.Context, teamName string) ([]*enteties.TeamMember, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetActiveUsersOutsideTeam")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, urp.Db)
+
+	users := make([]*enteties.TeamMember, 0)
+
+	query := urp.sq.Select(
+		"user_id",
+		"username",
+		"is_active").
+		From("users").
+		Where(squirrel.NotEq{"team_name": teamName}).
+		Where(squirrel.Eq{"is_active": true})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetActiveUsersOutsideTeam]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetActiveUsersOutsideTeam]: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tm enteties.TeamMember
+
+		err := rows.Scan(&tm.UserID, &tm.UserName, &tm.IsActive)
+		if err != nil {
+			return nil, fmt.Errorf("[UserRepo | GetActiveUsersOutsideTeam]: %w", err)
+		}
+
+		users = append(users, &tm)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetActiveUsersOutsideTeam]: %w", err)
+	}
+
+	return users, nil
+}
+
+func (urp *userPostgresRepository) GetUsers(ctx context.Context, usersID []string) ([]*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUsers")
+	defer span.End()
+
+	query := urp.sq.Select("user_id", "username", "COALESCE(team_name, '')", "is_active").
+		From("users").
+		Where(squirrel.Eq{"user_id": usersID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetUsers]: %w", err)
+	}
+
+	rows, err := GetQuerier(ctx, urp.Db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetUsers]: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*enteties.User, 0, len(usersID))
+	for rows.Next() {
+		var user enteties.User
+
+		err := rows.Scan(&user.UserID, &user.UserName, &user.TeamName, &user.IsActive)
+		if err != nil {
+			return nil, fmt.Errorf("[UserRepo | GetUsers]: %w", err)
+		}
+
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[UserRepo | GetUsers]: %w", err)
+	}
+
+	return users, nil
+}
+
+func (urp *userPostgresRepository) SetUsersStatusBatch(ctx context.Context, usersID []string, newStatus bool) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.SetUsersStatusBatch")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, urp.Db)
+
+	query := urp.sq.Update("users").
+		Set("is_active", newStatus).
+		Where(squirrel.Eq{"user_id": usersID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[UserRepo | SetUsersStatusBatch]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[UserRepo | SetUsersStatusBatch]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+type webhookMemoryRepository struct {
+	Storage *MemoryStorage
+}
+
+func NewWebhookMemoryRepository(storage *MemoryStorage) *webhookMemoryRepository {
+	return &webhookMemoryRepository{
+		Storage: storage,
+	}
+}
+
+func (wmr *webhookMemoryRepository) CreateSubscription(ctx context.Context,
+	req *enteties.CreateWebhookSubscription) (*enteties.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateSubscription")
+	defer span.End()
+
+	unlock := wmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &wmr.Storage.data
+
+	if _, ok := data.teams[req.TeamName]; !ok {
+		return nil, fmt.Errorf("[WebhookRepo | CreateSubscription]: %w", errMemoryForeignKey)
+	}
+
+	data.lastWebhookID++
+	subscription := enteties.WebhookSubscription{
+		SubscriptionID: data.lastWebhookID,
+		TeamName:       req.TeamName,
+		URL:            req.URL,
+		Secret:         req.Secret,
+		EventTypes:     append([]enteties.WebhookEventType{}, req.EventTypes...),
+		CreatedAt:      memoryNow(),
+	}
+	data.webhooks[subscription.SubscriptionID] = subscription
+
+	subscription.EventTypes = append([]enteties.WebhookEventType{}, subscription.EventTypes...)
+	return &subscription, nil
+}
+
+func (wmr *webhookMemoryRepository) SubscriptionExists(ctx context.Context, subscriptionID int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.SubscriptionExists")
+	defer span.End()
+
+	unlock := wmr.Storage.lock(ctx)
+	defer unlock()
+
+	_, ok := wmr.Storage.data.webhooks[subscriptionID]
+	return ok, nil
+}
+
+func (wmr *webhookMemoryRepository) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.DeleteSubscription")
+	defer span.End()
+
+	unlock := wmr.Storage.lock(ctx)
+	defer unlock()
+
+	wmr.Storage.data.deleteWebhook(subscriptionID)
+	return nil
+}
+
+func (wmr *webhookMemoryRepository) GetTeamSubscriptions(ctx context.Context, teamName string) ([]enteties.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetTeamSubscriptions")
+	defer span.End()
+
+	unlock := wmr.Storage.lock(ctx)
+	defer unlock()
+
+	result := make([]enteties.WebhookSubscription, 0)
+	for _, webhook := range wmr.Storage.data.webhooks {
+		if webhook.TeamName != teamName {
+			continue
+		}
+
+		webhook.EventTypes = append([]enteties.WebhookEventType{}, webhook.EventTypes...)
+		result = append(result, webhook)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SubscriptionID < result[j].SubscriptionID
+	})
+
+	return result, nil
+}
+
+func (wmr *webhookMemoryRepository) AddDelivery(ctx context.Context, delivery *enteties.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.AddDelivery")
+	defer span.End()
+
+	unlock := wmr.Storage.lock(ctx)
+	defer unlock()
+
+	data := &wmr.Storage.data
+
+	// подписку могли удалить, пока событие доставлялось
+	if _, ok := data.webhooks[delivery.SubscriptionID]; !ok {
+		return fmt.Errorf("[WebhookRepo | AddDelivery]: %w", errMemoryForeignKey)
+	}
+
+	data.lastDeliveryID++
+	saved := *delivery
+	saved.DeliveryID = data.lastDeliveryID
+	saved.CreatedAt = memoryNow()
+	data.deliveries = append(data.deliveries, saved)
+
+	return nil
+}
+
+func (wmr *webhookMemoryRepository) GetDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]enteties.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetDeliveries")
+	defer span.End()
+
+	unlock := wmr.Storage.lock(ctx)
+	defer unlock()
+
+	result := make([]enteties.WebhookDelivery, 0)
+
+	// журнал хранится в порядке записи, новые попытки в конце
+	deliveries := wmr.Storage.data.deliveries
+	for i := len(deliveries) - 1; i >= 0 && len(result) < limit; i-- {
+		if deliveries[i].SubscriptionID == subscriptionID {
+			result = append(result, deliveries[i])
+		}
+	}
+
+	return result, nil
+}
+
+func (wmr *webhookMemoryRepository) GetDeliveredSubscriptions(ctx context.Context, eventID string) ([]int64, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetDeliveredSubscriptions")
+	defer span.End()
+
+	unlock := wmr.Storage.lock(ctx)
+	defer unlock()
+
+	subscriptionsID := make([]int64, 0)
+	for _, delivery := range wmr.Storage.data.deliveries {
+		if delivery.EventID == eventID && delivery.Success && !slices.Contains(subscriptionsID, delivery.SubscriptionID) {
+			subscriptionsID = append(subscriptionsID, delivery.SubscriptionID)
+		}
+	}
+
+	return subscriptionsID, nil
+}
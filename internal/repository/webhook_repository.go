@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookRepository interface {
+	/* метод создает подписку команды в таблице webhook_subscriptions. Принимает на вход модель
+	enteties.CreateWebhookSubscription, возвращает модель enteties.WebhookSubscription*/
+	CreateSubscription(ctx context.Context, req *enteties.CreateWebhookSubscription) (*enteties.WebhookSubscription, error)
+
+	/* метод возвращает true, если подписка с заданным id существует, false иначе*/
+	SubscriptionExists(ctx context.Context, subscriptionID int64) (bool, error)
+
+	/* метод удаляет подписку вместе с журналом доставок. Принимает на вход id подписки*/
+	DeleteSubscription(ctx context.Context, subscriptionID int64) error
+
+	/* метод возвращает подписки команды (вместе с секретами) в порядке создания. Принимает
+	на вход название команды, возвращает список моделей enteties.WebhookSubscription*/
+	GetTeamSubscriptions(ctx context.Context, teamName string) ([]enteties.WebhookSubscription, error)
+
+	/* метод записывает попытку доставки события в журнал webhook_deliveries. Принимает на
+	вход модель enteties.WebhookDelivery*/
+	AddDelivery(ctx context.Context, delivery *enteties.WebhookDelivery) error
+
+	/* метод возвращает последние limit попыток доставки по подписке, новые первыми. Принимает
+	на вход id подписки, возвращает список моделей enteties.WebhookDelivery*/
+	GetDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]enteties.WebhookDelivery, error)
+
+	/* метод возвращает id подписок, которые уже приняли событие (в журнале есть успешная
+	попытка). Принимает на вход id события*/
+	GetDeliveredSubscriptions(ctx context.Context, eventID string) ([]int64, error)
+}
+
+type webhookPostgresRepository struct {
+	Db *pgxpool.Pool
+	sq squirrel.StatementBuilderType
+}
+
+func NewWebhookPostgresRepository(db *pgxpool.Pool) *webhookPostgresRepository {
+	return &webhookPostgresRepository{
+		Db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (wp *webhookPostgresRepository) CreateSubscription(ctx context.Context,
+	req *enteties.CreateWebhookSubscription) (*enteties.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateSubscription")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, wp.Db)
+
+	query := wp.sq.Insert("webhook_subscriptions").
+		Columns("team_name", "url", "secret", "event_types").
+		Values(req.TeamName, req.URL, req.Secret, eventTypesToStrings(req.EventTypes)).
+		Suffix("RETURNING id, created_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | CreateSubscription]: %w", err)
+	}
+
+	subscription := enteties.WebhookSubscription{
+		TeamName:   req.TeamName,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: append([]enteties.WebhookEventType{}, req.EventTypes...),
+	}
+
+	err = db.QueryRow(ctx, sql, args...).Scan(&subscription.SubscriptionID, &subscription.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | CreateSubscription]: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+func (wp *webhookPostgresRepository) SubscriptionExists(ctx context.Context, subscriptionID int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.SubscriptionExists")
+	defer span.End()
+
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM webhook_subscriptions WHERE id = $1)`
+
+	err := GetQuerier(ctx, wp.Db).QueryRow(ctx, query, subscriptionID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("[WebhookRepo | SubscriptionExists]: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (wp *webhookPostgresRepository) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.DeleteSubscription")
+	defer span.End()
+
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	_, err := GetQuerier(ctx, wp.Db).Exec(ctx, query, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("[WebhookRepo | DeleteSubscription]: %w", err)
+	}
+
+	return nil
+}
+
+func (wp *webhookPostgresRepository) GetTeamSubscriptions(ctx context.Context, teamName string) ([]enteties.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetTeamSubscriptions")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, wp.Db)
+
+	query := wp.sq.Select("id", "team_name", "url", "secret", "event_types", "created_at").
+		From("webhook_subscriptions").
+		Where(squirrel.Eq{"team_name": teamName}).
+		OrderBy("id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetTeamSubscriptions]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetTeamSubscriptions]: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]enteties.WebhookSubscription, 0)
+	for rows.Next() {
+		var (
+			s          enteties.WebhookSubscription
+			eventTypes []string
+		)
+
+		err := rows.Scan(&s.SubscriptionID, &s.TeamName, &s.URL, &s.Secret, &eventTypes, &s.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[WebhookRepo | GetTeamSubscriptions]: %w", err)
+		}
+
+		for _, eventType := range eventTypes {
+			s.EventTypes = append(s.EventTypes, enteties.WebhookEventType(eventType))
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetTeamSubscriptions]: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (wp *webhookPostgresRepository) AddDelivery(ctx context.Context, delivery *enteties.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.AddDelivery")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, wp.Db)
+
+	var statusCode any
+	if delivery.StatusCode != 0 {
+		statusCode = delivery.StatusCode
+	}
+
+	query := wp.sq.Insert("webhook_deliveries").
+		Columns("subscription_id", "event_id", "event_type", "attempt", "status_code", "error", "success").
+		Values(delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.Attempt, statusCode,
+			delivery.Error, delivery.Success)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("[WebhookRepo | AddDelivery]: %w", err)
+	}
+
+	_, err = db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("[WebhookRepo | AddDelivery]: %w", err)
+	}
+
+	return nil
+}
+
+func (wp *webhookPostgresRepository) GetDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]enteties.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetDeliveries")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, wp.Db)
+
+	query := wp.sq.Select(
+		"id",
+		"subscription_id",
+		"event_id",
+		"event_type",
+		"attempt",
+		"COALESCE(status_code, 0)",
+		"error",
+		"success",
+		"created_at").
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"subscription_id": subscriptionID}).
+		OrderBy("id DESC").
+		Limit(uint64(limit))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetDeliveries]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetDeliveries]: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]enteties.WebhookDelivery, 0)
+	for rows.Next() {
+		var d enteties.WebhookDelivery
+		err := rows.Scan(&d.DeliveryID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Attempt, &d.StatusCode,
+			&d.Error, &d.Success, &d.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[WebhookRepo | GetDeliveries]: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetDeliveries]: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (wp *webhookPostgresRepository) GetDeliveredSubscriptions(ctx context.Context, eventID string) ([]int64, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetDeliveredSubscriptions")
+	defer span.End()
+
+	// получим транзакцию из контекста или пул соединений
+	db := GetQuerier(ctx, wp.Db)
+
+	query := wp.sq.Select("DISTINCT subscription_id").
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"event_id": eventID, "success": true})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetDeliveredSubscriptions]: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetDeliveredSubscriptions]: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptionsID := make([]int64, 0)
+	for rows.Next() {
+		var subscriptionID int64
+		err := rows.Scan(&subscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("[WebhookRepo | GetDeliveredSubscriptions]: %w", err)
+		}
+
+		subscriptionsID = append(subscriptionsID, subscriptionID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[WebhookRepo | GetDeliveredSubscriptions]: %w", err)
+	}
+
+	return subscriptionsID, nil
+}
+
+// вспомогательная функция переводит типы событий в []string для колонки TEXT[]
+func eventTypesToStrings(eventTypes []enteties.WebhookEventType) []string {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		result = append(result, string(eventType))
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+)
+
+// инициатор действий, выполненных без указания пользователя
+const ActorSystem = "system"
+
+type actorContextKey struct{}
+
+// ActorKey ключ контекста, по которому хранится инициатор действия (user_id)
+var ActorKey = actorContextKey{}
+
+// WithActor добавляет инициатора действия в контекст
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ActorKey, actor)
+}
+
+// ActorFromContext извлекает инициатора действия из контекста, по умолчанию ActorSystem
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(ActorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorSystem
+}
+
+// вспомогательная функция формирует события истории назначений по заменам ревьюеров
+func replacementEvents(ctx context.Context, replacements []enteties.ReviewerReplacement, reason string) []enteties.AssignmentEvent {
+	actor := ActorFromContext(ctx)
+
+	events := make([]enteties.AssignmentEvent, 0, len(replacements))
+	for _, r := range replacements {
+		event := enteties.AssignmentEvent{
+			PullRequestID: r.PullRequestID,
+			EventType:     enteties.AssignmentEventReassign,
+			OldUserID:     r.OldUserID,
+			NewUserID:     r.ReplacedBy,
+			Actor:         actor,
+			Reason:        reason,
+		}
+
+		if r.ReplacedBy == "" {
+			event.EventType = enteties.AssignmentEventUnassign
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// вспомогательная функция формирует события назначения ревьюеров на pull request
+func assignEvents(ctx context.Context, prID string, reviewers []string, reason string) []enteties.AssignmentEvent {
+	actor := ActorFromContext(ctx)
+
+	events := make([]enteties.AssignmentEvent, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		events = append(events, enteties.AssignmentEvent{
+			PullRequestID: prID,
+			EventType:     enteties.AssignmentEventAssign,
+			NewUserID:     reviewer,
+			Actor:         actor,
+			Reason:        reason,
+		})
+	}
+
+	return events
+}
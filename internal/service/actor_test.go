@@ -0,0 +1,41 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActor_SetIsActiveRecordsActor(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2")
+
+	_, err := s.outbox.ClaimEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+
+	user, err := s.user.SetIsActive(WithActor(ctx, "u1"), "u2", false)
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+
+	// неизвестный пользователь не меняет состояние и не пишет событие
+	_, err = s.user.SetIsActive(ctx, "unknown", false)
+	require.ErrorIs(t, err, ErrorUserNotFound)
+
+	events, err := s.outbox.ClaimEvents(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, enteties.OutboxEventUserStatusChanged, events[0].EventType)
+	assert.Equal(t, "u1", events[0].Actor)
+
+	var changed enteties.User
+	require.NoError(t, json.Unmarshal(events[0].Payload, &changed))
+	assert.Equal(t, "u2", changed.UserID)
+	assert.False(t, changed.IsActive)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrorInvalidCursor = errors.New("invalid cursor")
+
+// размер страницы списков pull request
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 100
+)
+
+// содержимое курсора, клиент получает его в виде непрозрачной строки
+type cursorPayload struct {
+	CreatedAt     time.Time `json:"c"`
+	PullRequestID string    `json:"id"`
+}
+
+// вспомогательная функция кодирует позицию последнего pull request страницы в курсор
+func encodeCursor(cursor enteties.PullRequestCursor) string {
+	raw, _ := json.Marshal(cursorPayload{
+		CreatedAt:     cursor.CreatedAt,
+		PullRequestID: cursor.PullRequestID,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// вспомогательная функция разбирает курсор из запроса, пустой курсор - первая страница (nil)
+func decodeCursor(cursor string) (*enteties.PullRequestCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("[decodeCursor]: %w", ErrorInvalidCursor)
+	}
+
+	var payload cursorPayload
+	err = json.Unmarshal(raw, &payload)
+	if err != nil || payload.PullRequestID == "" || payload.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("[decodeCursor]: %w", ErrorInvalidCursor)
+	}
+
+	return &enteties.PullRequestCursor{
+		CreatedAt:     payload.CreatedAt,
+		PullRequestID: payload.PullRequestID,
+	}, nil
+}
+
+// вспомогательная функция возвращает размер страницы, 0 - размер по умолчанию
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+
+	return min(limit, maxPageLimit)
+}
+
+// вспомогательная функция отрезает от выборки лишний pull request (репозиторий читает limit+1)
+// и возвращает курсор следующей страницы, пустой если страница последняя
+func cutPage(prs []*enteties.PullRequestShort, limit int) ([]*enteties.PullRequestShort, string) {
+	if len(prs) <= limit {
+		return prs, ""
+	}
+
+	prs = prs[:limit]
+
+	last := prs[len(prs)-1]
+	if last.CreatedAt == nil {
+		return prs, ""
+	}
+
+	return prs, encodeCursor(enteties.PullRequestCursor{
+		CreatedAt:     *last.CreatedAt,
+		PullRequestID: last.PullRequestID,
+	})
+}
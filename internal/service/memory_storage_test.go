@@ -0,0 +1,146 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_RollbackOnError(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2")
+
+	minReviewers, maxReviewers := 2, 2
+	_, err := s.team.UpdateTeamSettings(ctx, &enteties.UpdateTeamSettings{
+		TeamName:     "backend",
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	})
+	require.NoError(t, err)
+
+	// в команде только один кандидат, pull request не должен сохраниться
+	_, err = s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	assert.ErrorIs(t, err, ErrorNotEnoughReviewers)
+
+	_, err = s.pr.GetHistory(ctx, "pr1")
+	assert.ErrorIs(t, err, ErrorPRNotFound)
+}
+
+func TestMemory_SnapshotRollback(t *testing.T) {
+	ctx := context.Background()
+	storage := repository.NewMemoryStorage()
+	teamRepo := repository.NewTeamMemoryRepository(storage)
+
+	errRollback := errors.New("rollback")
+
+	// изменения видны внутри транзакции и отменяются при ошибке
+	err := storage.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := teamRepo.CreateTeam(ctx, "backend")
+		require.NoError(t, err)
+
+		exists, err := teamRepo.TeamExists(ctx, "backend")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+
+	exists, err := teamRepo.TeamExists(ctx, "backend")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// паника внутри транзакции тоже восстанавливает снимок и освобождает хранилище
+	assert.Panics(t, func() {
+		_ = storage.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := teamRepo.CreateTeam(ctx, "frontend")
+			require.NoError(t, err)
+			panic("boom")
+		})
+	})
+
+	exists, err = teamRepo.TeamExists(ctx, "frontend")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// после успешной транзакции контекст транзакции больше не удерживает хранилище
+	var txCtx context.Context
+	err = storage.WithinTransaction(ctx, func(ctx context.Context) error {
+		txCtx = ctx
+		_, err := teamRepo.CreateTeam(ctx, "backend")
+		return err
+	})
+	require.NoError(t, err)
+
+	exists, err = teamRepo.TeamExists(txCtx, "backend")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestMemory_NestedTransactions(t *testing.T) {
+	ctx := context.Background()
+	storage := repository.NewMemoryStorage()
+	teamRepo := repository.NewTeamMemoryRepository(storage)
+
+	errRollback := errors.New("rollback")
+
+	teamExists := func(teamName string) bool {
+		exists, err := teamRepo.TeamExists(ctx, teamName)
+		require.NoError(t, err)
+		return exists
+	}
+
+	// вложенная транзакция выполняется в открытой и фиксируется вместе с ней
+	err := storage.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := storage.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := teamRepo.CreateTeam(ctx, "t1")
+			return err
+		})
+		require.NoError(t, err)
+
+		_, err = teamRepo.CreateTeam(ctx, "t2")
+		return err
+	})
+	require.NoError(t, err)
+	assert.True(t, teamExists("t1"))
+	assert.True(t, teamExists("t2"))
+
+	// ошибка внешней транзакции отменяет и изменения вложенной
+	err = storage.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := storage.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := teamRepo.CreateTeam(ctx, "t3")
+			return err
+		})
+		require.NoError(t, err)
+
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+	assert.False(t, teamExists("t3"))
+
+	// точек сохранения нет: если внешняя транзакция не вернула ошибку вложенной,
+	// изменения вложенной фиксируются
+	err = storage.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := storage.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := teamRepo.CreateTeam(ctx, "t4")
+			require.NoError(t, err)
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, teamExists("t4"))
+}
@@ -0,0 +1,50 @@
+package service
+
+import "avito_intern/internal/enteties"
+
+// Metrics учитывает бизнес-события сервисов. Методы вызываются после коммита транзакции, поэтому
+// откаченные изменения и повторы транзакции не учитываются
+type Metrics interface {
+	// создан pull request
+	PRCreated()
+	// на pull request назначены ревьюеры (при создании или переводе черновика в готовый)
+	ReviewersAssigned(count int)
+	// ревьюеры заменены другими участниками. reason - причина из истории назначений, для ReassignPR
+	// причина из запроса (по умолчанию manual_reassign)
+	ReviewersReassigned(reason string, count int)
+	// ReassignPR завершился ошибкой NO_CANDIDATE
+	NoCandidate()
+	// pull request смерджен
+	PRMerged()
+}
+
+// реализация Metrics, которая ничего не учитывает (метрики выключены и тесты)
+type nopMetrics struct{}
+
+func (nopMetrics) PRCreated()                      {}
+func (nopMetrics) ReviewersAssigned(int)           {}
+func (nopMetrics) ReviewersReassigned(string, int) {}
+func (nopMetrics) NoCandidate()                    {}
+func (nopMetrics) PRMerged()                       {}
+
+// вспомогательная функция возвращает metrics или реализацию без учета, если metrics не задан
+func metricsOrNop(metrics Metrics) Metrics {
+	if metrics == nil {
+		return nopMetrics{}
+	}
+	return metrics
+}
+
+// вспомогательная функция учитывает замены ревьюеров. Снятия без замены не учитываются
+func recordReassigned(metrics Metrics, replacements []enteties.ReviewerReplacement, reason string) {
+	count := 0
+	for _, r := range replacements {
+		if r.ReplacedBy != "" {
+			count++
+		}
+	}
+
+	if count > 0 {
+		metrics.ReviewersReassigned(reason, count)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_PRService(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+	createTestTeam(t, s, "frontend", "f1", "f2", "f3")
+
+	pr, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	// черновик учитывается как созданный, ревьюеры - после MarkReady
+	_, err = s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr2",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+		IsDraft:         true,
+	})
+	require.NoError(t, err)
+
+	ready, err := s.pr.MarkReady(ctx, &enteties.MarkReadyPullRequest{PullRequestID: "pr2"})
+	require.NoError(t, err)
+	_, err = s.pr.MarkReady(ctx, &enteties.MarkReadyPullRequest{PullRequestID: "pr2"})
+	require.NoError(t, err)
+
+	// замена учитывается с причиной из запроса, без причины - manual_reassign
+	_, err = s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     pr.AssignedReviewers[0],
+		Reason:        "vacation",
+	})
+	require.NoError(t, err)
+	_, err = s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     pr.AssignedReviewers[1],
+	})
+	require.NoError(t, err)
+
+	// в команде из трех человек оба кандидата уже назначены
+	_, err = s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr3",
+		PullRequestName: "name",
+		AuthorID:        "f1",
+	})
+	require.NoError(t, err)
+
+	_, err = s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr3",
+		OldUserID:     "f2",
+	})
+	assert.ErrorIs(t, err, ErrorNoCandidateToReassign)
+
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+
+	// замены при деактивации учитываются с причиной из истории назначений
+	deactivated, err := s.team.DeactivateUsers(ctx, &enteties.DeactivateUsers{UsersID: []string{ready.AssignedReviewers[0]}})
+	require.NoError(t, err)
+	require.Len(t, deactivated.Reassigned, 1)
+
+	// ошибка до коммита не учитывается
+	_, err = s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	assert.ErrorIs(t, err, ErrorPRAlreadyExists)
+
+	assert.Equal(t, 3, s.metrics.created)
+	assert.Equal(t, 6, s.metrics.assigned)
+	assert.Equal(t, map[string]int{
+		"vacation":                               1,
+		enteties.AssignmentReasonManualReassign:  1,
+		enteties.AssignmentReasonUserDeactivated: 1,
+	}, s.metrics.reassigned)
+	assert.Equal(t, 1, s.metrics.noCandidate)
+	assert.Equal(t, 1, s.metrics.merged)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// вспомогательная функция записывает доменное событие в outbox с инициатором из контекста.
+// Должна вызываться внутри транзакции изменения состояния (tx в контексте), тогда событие
+// фиксируется или откатывается вместе с изменением
+func addOutboxEvent(ctx context.Context, outboxRepo repository.OutboxRepository, eventType enteties.OutboxEventType,
+	payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("[addOutboxEvent]: %w", err)
+	}
+
+	err = outboxRepo.AddEvent(ctx, &enteties.OutboxEvent{
+		EventType: eventType,
+		Actor:     ActorFromContext(ctx),
+		Payload:   body,
+	})
+	if err != nil {
+		return fmt.Errorf("[addOutboxEvent]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_Events(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3")
+
+	pr, err := s.pr.CreatePR(WithActor(ctx, "u1"), &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	// откаченная транзакция не оставляет событий
+	_, err = s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr2",
+		PullRequestName: "name",
+		AuthorID:        "unknown",
+	})
+	require.ErrorIs(t, err, ErrorUserNotFound)
+
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+
+	// повторный мердж состояние не меняет и событие не пишет
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+
+	_, err = s.team.DeactivateUsers(ctx, &enteties.DeactivateUsers{UsersID: []string{"u2"}})
+	require.NoError(t, err)
+
+	events, err := s.outbox.ClaimEvents(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 8)
+
+	types := make([]enteties.OutboxEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.EventType)
+		assert.Equal(t, 1, event.Attempt)
+	}
+	// события для подписчиков webhook пишутся в той же транзакции, что и изменение
+	assert.Equal(t, []enteties.OutboxEventType{
+		enteties.OutboxEventTeamCreated,
+		enteties.OutboxEventPRCreated,
+		enteties.OutboxEventWebhook,
+		enteties.OutboxEventWebhook,
+		enteties.OutboxEventWebhook,
+		enteties.OutboxEventPRMerged,
+		enteties.OutboxEventWebhook,
+		enteties.OutboxEventUsersDeactivated,
+	}, types)
+
+	var created enteties.PullRequest
+	require.NoError(t, json.Unmarshal(events[1].Payload, &created))
+	assert.Equal(t, "u1", events[1].Actor)
+	assert.Equal(t, pr.PullRequestID, created.PullRequestID)
+	assert.ElementsMatch(t, pr.AssignedReviewers, created.AssignedReviewers)
+
+	var webhookEvent enteties.WebhookEvent
+	require.NoError(t, json.Unmarshal(events[2].Payload, &webhookEvent))
+	assert.Equal(t, enteties.WebhookEventPRCreated, webhookEvent.EventType)
+	assert.Equal(t, "backend", webhookEvent.TeamName)
+	assert.Equal(t, "u1", webhookEvent.Actor)
+
+	var deactivated enteties.DeactivateUsersResponce
+	require.NoError(t, json.Unmarshal(events[7].Payload, &deactivated))
+	assert.Equal(t, []string{"u2"}, deactivated.DeactivatedUsers)
+	assert.Equal(t, ActorSystem, events[7].Actor)
+
+	// выбранные события не выдаются повторно, пока не истек lease
+	events, err = s.outbox.ClaimEvents(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
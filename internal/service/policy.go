@@ -0,0 +1,115 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrorForbidden = errors.New("action is not allowed for the user role")
+
+// Subject аутентифицированный инициатор запроса, права которого проверяет Policy. Admin задан
+// у администраторов по токену, UserID - у пользователей, чья роль хранится в базе данных
+type Subject struct {
+	UserID string
+	Admin  bool
+}
+
+type subjectContextKey struct{}
+
+// SubjectKey ключ контекста, по которому хранится инициатор запроса для проверки прав
+var SubjectKey = subjectContextKey{}
+
+// WithSubject добавляет инициатора запроса в контекст
+func WithSubject(ctx context.Context, subject Subject) context.Context {
+	return context.WithValue(ctx, SubjectKey, subject)
+}
+
+// SubjectFromContext извлекает инициатора запроса из контекста
+func SubjectFromContext(ctx context.Context) (Subject, bool) {
+	subject, ok := ctx.Value(SubjectKey).(Subject)
+	return subject, ok
+}
+
+// Action действие, право на которое проверяет Policy
+type Action string
+
+const (
+	ActionCreateTeam  Action = "team.create"
+	ActionManageTeam  Action = "team.manage" // просмотр и настройки команды, добавление и исключение участников
+	ActionSetIsActive Action = "user.set_is_active"
+	ActionCreatePR    Action = "pr.create"
+	ActionUpdatePR    Action = "pr.update" // перевод из черновика, закрытие и переоткрытие
+	ActionReviewPR    Action = "pr.review"
+	ActionReassignPR  Action = "pr.reassign"
+	ActionMergePR     Action = "pr.merge"
+)
+
+// Resource объект действия: команда, к которой он относится (для pull request - команда автора),
+// и пользователь, которого касается действие
+type Resource struct {
+	TeamName string
+	UserID   string
+}
+
+type Policy interface {
+	/* метод проверяет, может ли инициатор запроса из контекста выполнить действие над ресурсом.
+	Возвращает ErrorForbidden, если не может. Запрос без инициатора (внутренние вызовы)
+	выполняется без ограничений*/
+	Authorize(ctx context.Context, action Action, resource Resource) error
+
+	/* метод возвращает true, если пользователю выдана роль admin*/
+	IsAdmin(ctx context.Context, userID string) (bool, error)
+}
+
+// rolePolicy проверяет права по ролям из базы данных: admin может все, team_lead - все, кроме
+// создания команд, в пределах своей команды, member - только снимать себя с ревью и принимать
+// решение по своему ревью
+type rolePolicy struct {
+	RoleRepo repository.RoleRepository
+}
+
+func NewRolePolicy(roleRepo repository.RoleRepository) *rolePolicy {
+	return &rolePolicy{
+		RoleRepo: roleRepo,
+	}
+}
+
+func (rp *rolePolicy) Authorize(ctx context.Context, action Action, resource Resource) error {
+	subject, ok := SubjectFromContext(ctx)
+	if !ok || subject.Admin {
+		return nil
+	}
+
+	role, err := rp.RoleRepo.GetRole(ctx, subject.UserID)
+	if err != nil {
+		return fmt.Errorf("[Policy | Authorize]: %w", err)
+	}
+
+	switch role.Role {
+	case enteties.RoleAdmin:
+		return nil
+	case enteties.RoleTeamLead:
+		if action != ActionCreateTeam && resource.TeamName != "" && resource.TeamName == role.TeamName {
+			return nil
+		}
+	}
+
+	// любой пользователь может снять себя с ревью и принять решение по своему ревью
+	if (action == ActionReassignPR || action == ActionReviewPR) && resource.UserID == subject.UserID {
+		return nil
+	}
+
+	return fmt.Errorf("[Policy | Authorize]: %w: %s", ErrorForbidden, action)
+}
+
+func (rp *rolePolicy) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	role, err := rp.RoleRepo.GetRole(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("[Policy | IsAdmin]: %w", err)
+	}
+
+	return role.Role == enteties.RoleAdmin, nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_TeamLeadLeavesTeam(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4", "u5")
+	createTestTeam(t, s, "frontend", "f1", "f2")
+
+	adminCtx := WithSubject(ctx, Subject{Admin: true})
+	for _, userID := range []string{"u1", "u2", "u3", "u4"} {
+		_, err := s.role.GrantRole(adminCtx, &enteties.GrantRole{UserID: userID, Role: enteties.RoleTeamLead, TeamName: "backend"})
+		require.NoError(t, err)
+	}
+	_, err := s.role.GrantRole(adminCtx, &enteties.GrantRole{UserID: "u5", Role: enteties.RoleAdmin})
+	require.NoError(t, err)
+
+	leadCtx := WithSubject(ctx, Subject{UserID: "u1"})
+	_, err = s.team.GetTeamSettings(leadCtx, "backend")
+	require.NoError(t, err)
+
+	// переведенный руководитель не управляет прежней командой и не становится руководителем новой
+	_, err = s.team.MoveMember(adminCtx, &enteties.MoveTeamMember{UserID: "u1", TeamName: "frontend"})
+	require.NoError(t, err)
+
+	minReviewers, maxReviewers := 0, 1
+	_, err = s.team.UpdateTeamSettings(leadCtx, &enteties.UpdateTeamSettings{
+		TeamName:     "backend",
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.team.GetTeamSettings(leadCtx, "backend")
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.team.GetTeamSettings(leadCtx, "frontend")
+	require.ErrorIs(t, err, ErrorForbidden)
+
+	// исключение из команды и деактивация тоже отзывают роль
+	_, err = s.team.RemoveMembers(adminCtx, &enteties.RemoveTeamMembers{TeamName: "backend", UsersID: []string{"u2"}})
+	require.NoError(t, err)
+	_, err = s.team.DeactivateUsers(adminCtx, &enteties.DeactivateUsers{UsersID: []string{"u3", "u5"}})
+	require.NoError(t, err)
+	_, err = s.user.SetIsActive(adminCtx, "u4", false)
+	require.NoError(t, err)
+
+	for _, userID := range []string{"u2", "u3", "u4"} {
+		_, err = s.team.GetTeam(WithSubject(ctx, Subject{UserID: userID}), "backend")
+		require.ErrorIs(t, err, ErrorForbidden, userID)
+	}
+
+	// роль admin деактивацией не отзывается
+	roles, err := s.role.ListRoles(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, roles.Roles, 1)
+	assert.Equal(t, "u5", roles.Roles[0].UserID)
+	assert.Equal(t, enteties.RoleAdmin, roles.Roles[0].Role)
+
+	events, err := s.outbox.ClaimEvents(ctx, 100, time.Minute)
+	require.NoError(t, err)
+
+	var revoked int
+	for _, event := range events {
+		if event.EventType == enteties.OutboxEventRoleRevoked {
+			revoked++
+		}
+	}
+	assert.Equal(t, 4, revoked)
+}
+
+func TestPolicy_PRMethodsAsMember(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4", "u5")
+
+	adminCtx := WithSubject(ctx, Subject{Admin: true})
+	_, err := s.role.GrantRole(adminCtx, &enteties.GrantRole{UserID: "u1", Role: enteties.RoleTeamLead, TeamName: "backend"})
+	require.NoError(t, err)
+
+	for _, req := range []*enteties.CreatePullRequest{
+		{PullRequestID: "pr1", PullRequestName: "name", AuthorID: "u2"},
+		{PullRequestID: "pr2", PullRequestName: "name", AuthorID: "u2", IsDraft: true},
+		{PullRequestID: "pr3", PullRequestName: "name", AuthorID: "u2"},
+	} {
+		_, err = s.pr.CreatePR(adminCtx, req)
+		require.NoError(t, err)
+	}
+	_, err = s.pr.ClosePR(adminCtx, &enteties.ClosePullRequest{PullRequestID: "pr3"})
+	require.NoError(t, err)
+
+	// участник, который не назначен ревьюером pr1
+	pr1, err := s.pr.GetPR(ctx, "pr1")
+	require.NoError(t, err)
+
+	var member string
+	for _, userID := range []string{"u3", "u4", "u5"} {
+		if !slices.Contains(pr1.AssignedReviewers, userID) {
+			member = userID
+		}
+	}
+	memberCtx := WithSubject(ctx, Subject{UserID: member})
+	reviewer := pr1.AssignedReviewers[0]
+
+	// каждый изменяющий pull request метод отклоняет участника без роли
+	_, err = s.pr.CreatePR(memberCtx, &enteties.CreatePullRequest{PullRequestID: "pr4", PullRequestName: "name", AuthorID: member})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.MarkReady(memberCtx, &enteties.MarkReadyPullRequest{PullRequestID: "pr2"})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.ClosePR(memberCtx, &enteties.ClosePullRequest{PullRequestID: "pr1"})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.ReopenPR(memberCtx, &enteties.ReopenPullRequest{PullRequestID: "pr3"})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.SubmitReview(memberCtx, &enteties.ReviewPullRequest{
+		PullRequestID: "pr1",
+		UserID:        reviewer,
+		State:         enteties.ReviewStateApproved,
+	})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.ReassignPR(memberCtx, &enteties.ReassignPullRequest{PullRequestID: "pr1", OldUserID: reviewer})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.MergePR(memberCtx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.ErrorIs(t, err, ErrorForbidden)
+
+	// отклоненные вызовы ничего не изменили
+	_, err = s.pr.GetPR(ctx, "pr4")
+	require.ErrorIs(t, err, ErrorPRNotFound)
+	pr2, err := s.pr.GetPR(ctx, "pr2")
+	require.NoError(t, err)
+	assert.True(t, pr2.IsDraft)
+
+	// ревьюер принимает решение по своему ревью, руководитель управляет pull request команды
+	_, err = s.pr.SubmitReview(WithSubject(ctx, Subject{UserID: reviewer}), &enteties.ReviewPullRequest{
+		PullRequestID: "pr1",
+		UserID:        reviewer,
+		State:         enteties.ReviewStateApproved,
+	})
+	require.NoError(t, err)
+
+	leadCtx := WithSubject(ctx, Subject{UserID: "u1"})
+	_, err = s.pr.MarkReady(leadCtx, &enteties.MarkReadyPullRequest{PullRequestID: "pr2"})
+	require.NoError(t, err)
+	_, err = s.pr.ReopenPR(leadCtx, &enteties.ReopenPullRequest{PullRequestID: "pr3"})
+	require.NoError(t, err)
+	_, err = s.pr.ClosePR(leadCtx, &enteties.ClosePullRequest{PullRequestID: "pr3"})
+	require.NoError(t, err)
+	_, err = s.pr.CreatePR(leadCtx, &enteties.CreatePullRequest{PullRequestID: "pr4", PullRequestName: "name", AuthorID: member})
+	require.NoError(t, err)
+}
+
+func TestPolicy_RolePolicy(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+	createTestTeam(t, s, "frontend", "f1", "f2", "f3")
+
+	// pr1 относится к команде backend, pr2 - к frontend
+	for id, author := range map[string]string{"pr1": "u2", "pr2": "f1"} {
+		_, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+			PullRequestID:   id,
+			PullRequestName: "name",
+			AuthorID:        author,
+		})
+		require.NoError(t, err)
+	}
+
+	// роли выдает администратор по токену
+	adminCtx := WithActor(WithSubject(ctx, Subject{Admin: true}), "root")
+
+	lead, err := s.role.GrantRole(adminCtx, &enteties.GrantRole{UserID: "u1", Role: enteties.RoleTeamLead, TeamName: "backend"})
+	require.NoError(t, err)
+	assert.Equal(t, "root", lead.GrantedBy)
+	require.NotNil(t, lead.GrantedAt)
+
+	_, err = s.role.GrantRole(adminCtx, &enteties.GrantRole{UserID: "f3", Role: enteties.RoleAdmin})
+	require.NoError(t, err)
+
+	_, err = s.role.GrantRole(adminCtx, &enteties.GrantRole{UserID: "unknown", Role: enteties.RoleAdmin})
+	require.ErrorIs(t, err, ErrorUserNotFound)
+	_, err = s.role.GrantRole(adminCtx, &enteties.GrantRole{UserID: "u2", Role: enteties.RoleTeamLead, TeamName: "unknown"})
+	require.ErrorIs(t, err, ErrorTeamNotFound)
+
+	roles, err := s.role.ListRoles(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, roles.Roles, 1)
+	assert.Equal(t, "u1", roles.Roles[0].UserID)
+
+	leadCtx := WithSubject(ctx, Subject{UserID: "u1"})
+	memberCtx := WithSubject(ctx, Subject{UserID: "u4"})
+
+	// руководитель управляет только своей командой и не создает команды
+	_, err = s.team.CreateTeam(leadCtx, &enteties.Team{TeamName: "mobile", Members: []enteties.TeamMember{}})
+	require.ErrorIs(t, err, ErrorForbidden)
+
+	_, err = s.team.GetTeam(leadCtx, "backend")
+	require.NoError(t, err)
+	_, err = s.team.GetTeam(leadCtx, "frontend")
+	require.ErrorIs(t, err, ErrorForbidden)
+
+	_, err = s.user.SetIsActive(leadCtx, "u3", true)
+	require.NoError(t, err)
+	_, err = s.user.SetIsActive(leadCtx, "f2", true)
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.user.SetIsActive(memberCtx, "u3", true)
+	require.ErrorIs(t, err, ErrorForbidden)
+
+	// участник может снять с ревью только себя
+	pr2, err := s.pr.GetPR(ctx, "pr2")
+	require.NoError(t, err)
+
+	_, err = s.pr.ReassignPR(memberCtx, &enteties.ReassignPullRequest{PullRequestID: "pr2", OldUserID: pr2.AssignedReviewers[0]})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.ReassignPR(WithSubject(ctx, Subject{UserID: pr2.AssignedReviewers[0]}), &enteties.ReassignPullRequest{
+		PullRequestID: "pr2",
+		OldUserID:     pr2.AssignedReviewers[0],
+	})
+	require.NotErrorIs(t, err, ErrorForbidden)
+
+	_, err = s.pr.MergePR(memberCtx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.ErrorIs(t, err, ErrorForbidden)
+	_, err = s.pr.MergePR(leadCtx, &enteties.MergePullRequest{PullRequestID: "pr2"})
+	require.ErrorIs(t, err, ErrorForbidden)
+
+	merged, err := s.pr.MergePR(leadCtx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusMerged, merged.Status)
+
+	// администратор по роли может все
+	_, err = s.pr.MergePR(WithSubject(ctx, Subject{UserID: "f3"}), &enteties.MergePullRequest{PullRequestID: "pr2"})
+	require.NoError(t, err)
+
+	// после отзыва роли руководитель становится участником
+	revoked, err := s.role.RevokeRole(adminCtx, &enteties.RevokeRole{UserID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.RoleMember, revoked.Role)
+
+	_, err = s.user.SetIsActive(leadCtx, "u3", true)
+	require.ErrorIs(t, err, ErrorForbidden)
+
+	// повторный отзыв состояние не меняет
+	_, err = s.role.RevokeRole(adminCtx, &enteties.RevokeRole{UserID: "u1"})
+	require.NoError(t, err)
+
+	events, err := s.outbox.ClaimEvents(ctx, 100, time.Minute)
+	require.NoError(t, err)
+
+	var granted, revokedEvents int
+	for _, event := range events {
+		switch event.EventType {
+		case enteties.OutboxEventRoleGranted:
+			granted++
+			assert.Equal(t, "root", event.Actor)
+		case enteties.OutboxEventRoleRevoked:
+			revokedEvents++
+		}
+	}
+	assert.Equal(t, 2, granted)
+	assert.Equal(t, 1, revokedEvents)
+}
@@ -3,291 +3,1051 @@ package service
 import (
 	"avito_intern/internal/enteties"
 	"avito_intern/internal/repository"
+	"avito_intern/internal/tracing"
 	"context"
 	"errors"
 	"fmt"
-	"math/rand/v2"
-
-	"github.com/jackc/pgx/v5"
 )
 
 var (
 	ErrorPRAlreadyExists       = errors.New("PR already exists")
 	ErrorPRNotFound            = errors.New("PR not found")
 	ErrorPRIsMerged            = errors.New("PR is merged")
+	ErrorPRIsClosed            = errors.New("PR is closed")
+	ErrorPRIsArchived          = errors.New("PR is archived")
+	ErrorPRIsDraft             = errors.New("PR is draft")
 	ErrorUserNotAssigned       = errors.New("user not assigned to PR")
 	ErrorNoCandidateToReassign = errors.New("no candiate to reassign")
+	ErrorNotEnoughReviewers    = errors.New("not enough reviewers in team")
+	ErrorNotEnoughApprovals    = errors.New("not enough approvals to merge PR")
 )
 
 //go:generate mockgen -source=pr_service.go -destination=../../mocks/pr_service.go -package=mocks
 type PRService interface {
 	/* метод создает новый pull request, занося информацию в таблицу pull_requests
-	и автоматически определяя на него до двух ревьюеров из команды автора.
-	Принимает на вход модель enteties.CreatePullRequest, возвращает инфо о созданном
-	pull request в виде модели enteties.PullRequest */
+	и автоматически определяя на него ревьюеров из команды автора согласно стратегии
+	выбора ревьюеров и настройкам команды (минимальное и максимальное количество).
+	Черновик (is_draft) сохраняется без ревьюеров. Принимает на вход модель
+	enteties.CreatePullRequest, возвращает инфо о созданном pull request в виде модели
+	enteties.PullRequest */
 	CreatePR(ctx context.Context, pr *enteties.CreatePullRequest) (*enteties.PullRequest, error)
 
-	/* идемпотентный метод cтавит статус pull_request в MERGED. Принимаем на вход запрос
-	в виде модели enteties.MergePullRequest , возвращает pull request enteties.PullRequest*/
+	/* идемпотентный метод переводит черновик pull request в готовый к ревью и назначает на
+	него ревьюеров так же, как CreatePR. Принимает на вход модель enteties.MarkReadyPullRequest,
+	возвращает pull request enteties.PullRequest*/
+	MarkReady(ctx context.Context, readyReq *enteties.MarkReadyPullRequest) (*enteties.PullRequest, error)
+
+	/* идемпотентный метод cтавит статус pull_request в MERGED. CLOSED и ARCHIVED pull request
+	смерджить нельзя. Если задано обязательное количество одобрений, OPEN pull request мерджится
+	только после набора одобрений. Принимаем на вход запрос в виде модели enteties.MergePullRequest,
+	возвращает pull request enteties.PullRequest*/
 	MergePR(ctx context.Context, mergeReq *enteties.MergePullRequest) (*enteties.PullRequest, error)
 
-	/* метод переназначает ревьюера на pull_request. Принимает на вход модель
+	/* идемпотентный метод закрывает OPEN pull request без мерджа (статус CLOSED), ревьюеры
+	сохраняются. Принимает на вход модель enteties.ClosePullRequest, возвращает pull request
+	enteties.PullRequest*/
+	ClosePR(ctx context.Context, closeReq *enteties.ClosePullRequest) (*enteties.PullRequest, error)
+
+	/* идемпотентный метод переоткрывает CLOSED pull request (статус OPEN). Ревьюеры, которые
+	стали неактивными, пока pull request был закрыт, заменяются участниками команды автора
+	или снимаются без замены. Принимает на вход модель enteties.ReopenPullRequest, возвращает
+	модель enteties.ReopenPullRequestResponce*/
+	ReopenPR(ctx context.Context, reopenReq *enteties.ReopenPullRequest) (*enteties.ReopenPullRequestResponce, error)
+
+	/* метод переназначает ревьюера на pull_request. Если ревьюеров на pull request больше,
+	чем допускают настройки команды, ревьюер снимается без замены. Принимает на вход модель
 	enteties.ReassignPullRequest, возвращает модель enteties.ReassignPullRequestResponce*/
 	ReassignPR(ctx context.Context, resp *enteties.ReassignPullRequest) (*enteties.ReassignPullRequestResponce, error)
+
+	/* метод сохраняет решение ревьюера (APPROVED или CHANGES_REQUESTED) по OPEN pull request.
+	Повторное решение заменяет предыдущее. Принимает на вход модель enteties.ReviewPullRequest,
+	возвращает pull request enteties.PullRequest с решениями ревьюеров*/
+	SubmitReview(ctx context.Context, reviewReq *enteties.ReviewPullRequest) (*enteties.PullRequest, error)
+
+	/* метод возвращает историю назначений ревьюеров на pull request (назначения, замены и
+	снятия с инициатором и причиной). Принимает на вход pull_request_id, возвращает модель
+	enteties.PullRequestHistory*/
+	GetHistory(ctx context.Context, prID string) (*enteties.PullRequestHistory, error)
+
+	/* метод возвращает pull request вместе с ревьюерами и их решениями. Принимает на вход
+	pull_request_id, возвращает pull request enteties.PullRequest*/
+	GetPR(ctx context.Context, prID string) (*enteties.PullRequest, error)
+
+	/* метод ищет pull request по фильтрам и возвращает одну страницу результатов с курсором
+	следующей. Принимает на вход модель enteties.PullRequestListFilter, возвращает модель
+	enteties.PullRequestList*/
+	ListPRs(ctx context.Context, filter *enteties.PullRequestListFilter) (*enteties.PullRequestList, error)
 }
 
 type prService struct {
-	Db       *pgx.Conn
-	UserRepo repository.UserRepository
-	TeamRepo repository.TeamRepository
-	PRRepo   repository.PRRepository
+	TxManager      repository.TxManager
+	UserRepo       repository.UserRepository
+	TeamRepo       repository.TeamRepository
+	PRRepo         repository.PRRepository
+	OutboxRepo     repository.OutboxRepository
+	Policy         Policy
+	Strategy       ReviewerStrategy
+	ReviewersCount int
+	// количество одобрений, необходимое для мерджа (0 - мердж без одобрений)
+	RequiredApprovals int
+	Metrics           Metrics
 }
 
-func NewPRService(db *pgx.Conn, userRepo repository.UserRepository, teamRepo repository.TeamRepository,
-	prRepo repository.PRRepository) *prService {
+func NewPRService(txManager repository.TxManager, userRepo repository.UserRepository, teamRepo repository.TeamRepository,
+	prRepo repository.PRRepository, outboxRepo repository.OutboxRepository, policy Policy, strategy ReviewerStrategy,
+	reviewersCount, requiredApprovals int, metrics Metrics) *prService {
 	return &prService{
-		Db:       db,
-		UserRepo: userRepo,
-		TeamRepo: teamRepo,
-		PRRepo:   prRepo,
+		TxManager:         txManager,
+		UserRepo:          userRepo,
+		TeamRepo:          teamRepo,
+		PRRepo:            prRepo,
+		OutboxRepo:        outboxRepo,
+		Policy:            policy,
+		Strategy:          strategy,
+		ReviewersCount:    reviewersCount,
+		RequiredApprovals: requiredApprovals,
+		Metrics:           metricsOrNop(metrics),
 	}
 }
 
-func (prs *prService) CreatePR(ctx context.Context, pr *enteties.CreatePullRequest) (*enteties.PullRequest, error) {
-
-	// проверим, существует ли уже PR с таким id
-	exists, err := prs.PRRepo.PRExists(ctx, pr.PullRequestID)
+// вспомогательный метод проверяет право инициатора на действие с pull request автора authorID.
+// Pull request относится к команде автора, userID - пользователь, которого касается действие
+func (prs *prService) authorize(ctx context.Context, action Action, authorID, userID string) error {
+	teamName, err := prs.UserRepo.GetUserTeamName(ctx, authorID)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return err
 	}
 
-	if exists {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", ErrorPRAlreadyExists)
-	}
-	// проверим что пользователь с таким id есть
-	exists, err = prs.UserRepo.UserExists(ctx, pr.AuthorID)
+	return prs.Policy.Authorize(ctx, action, Resource{TeamName: teamName, UserID: userID})
+}
+
+func (prs *prService) CreatePR(ctx context.Context, pr *enteties.CreatePullRequest) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRService.CreatePR")
+	defer span.End()
+
+	var respPR *enteties.PullRequest
+
+	// проверки выполняются в той же транзакции, что и назначение ревьюеров
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// проверим, существует ли уже PR с таким id
+		exists, err := prs.PRRepo.PRExists(ctx, pr.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | CreatePR]: %w", err)
+		}
+
+		if exists {
+			return fmt.Errorf("[PRService | CreatePR]: %w", ErrorPRAlreadyExists)
+		}
+		// проверим что пользователь с таким id есть
+		exists, err = prs.UserRepo.UserExists(ctx, pr.AuthorID)
+		if err != nil {
+			return fmt.Errorf("[PRService | CreatePR]: %w", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("[PRService | CreatePR]: %w", ErrorUserNotFound)
+		}
+
+		// создавать pull request может руководитель команды автора
+		err = prs.authorize(ctx, ActionCreatePR, pr.AuthorID, "")
+		if err != nil {
+			return fmt.Errorf("[PRService | CreatePR]: %w", err)
+		}
+
+		// занесем инфо в таблицу. Проверка выше не видит незакоммиченный pull request параллельного
+		// создания, такая вставка отклоняется первичным ключом
+		prShort, err := prs.PRRepo.CreatePR(ctx, pr)
+		if errors.Is(err, repository.ErrorDuplicate) {
+			return fmt.Errorf("[PRService | CreatePR]: %w", ErrorPRAlreadyExists)
+		}
+		if err != nil {
+			return fmt.Errorf("[PRService | CreatePR]: %w", err)
+		}
+
+		// черновик сохраняется без ревьюеров, они назначаются в MarkReady
+		reviewers := []string{}
+		if !pr.IsDraft {
+			reviewers, err = prs.assignReviewers(ctx, pr.PullRequestID, pr.AuthorID, enteties.AssignmentReasonPRCreated)
+			if err != nil {
+				return fmt.Errorf("[PRService | CreatePR]: %w", err)
+			}
+		}
+
+		respPR = &enteties.PullRequest{
+			PullRequestID:     prShort.PullRequestID,
+			PulRequestName:    prShort.PulRequestName,
+			AuthorID:          prShort.AuthorID,
+			Status:            prShort.Status,
+			AssignedReviewers: reviewers,
+			IsDraft:           prShort.IsDraft,
+			Reviews:           pendingReviews(reviewers),
+		}
+
+		// событие outbox фиксируется вместе с pull request
+		err = addOutboxEvent(ctx, prs.OutboxRepo, enteties.OutboxEventPRCreated, respPR)
+		if err != nil {
+			return fmt.Errorf("[PRService | CreatePR]: %w", err)
+		}
+
+		// события для подписчиков команды автора
+		teamName, err := prs.UserRepo.GetUserTeamName(ctx, pr.AuthorID)
+		if err != nil {
+			return fmt.Errorf("[PRService | CreatePR]: %w", err)
+		}
+
+		err = addWebhookEvents(ctx, prs.OutboxRepo, append([]enteties.WebhookEvent{
+			newWebhookEvent(ctx, enteties.WebhookEventPRCreated, teamName, respPR),
+		}, reviewerAssignedEvents(ctx, teamName, respPR, reviewers)...))
+		if err != nil {
+			return fmt.Errorf("[PRService | CreatePR]: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return nil, err
 	}
 
-	if !exists {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", ErrorUserNotFound)
-	}
+	prs.Metrics.PRCreated()
+	prs.Metrics.ReviewersAssigned(len(respPR.AssignedReviewers))
 
-	// найдем команду автора
-	teamName, err := prs.UserRepo.GetUserTeamName(ctx, pr.AuthorID)
+	return respPR, nil
+}
+
+func (prs *prService) MarkReady(ctx context.Context, readyReq *enteties.MarkReadyPullRequest) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRService.MarkReady")
+	defer span.End()
+
+	var (
+		respPR *enteties.PullRequest
+		// количество назначенных ревьюеров, 0 при повторном вызове
+		assigned int
+	)
+
+	// репозитории получают транзакцию через контекст
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// заблокируем pull request, повторный MarkReady дождется назначения ревьюеров
+		err := prs.PRRepo.LockPRs(ctx, []string{readyReq.PullRequestID})
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		exists, err := prs.PRRepo.PRExists(ctx, readyReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("[PRService | MarkReady]: %w", ErrorPRNotFound)
+		}
+
+		currentPR, err := prs.PRRepo.GetPR(ctx, readyReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		// переводить из черновика может руководитель команды автора
+		err = prs.authorize(ctx, ActionUpdatePR, currentPR.AuthorID, "")
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		switch currentPR.Status {
+		case enteties.PullRequestStatusMerged:
+			return fmt.Errorf("[PRService | MarkReady]: %w", ErrorPRIsMerged)
+		case enteties.PullRequestStatusClosed:
+			return fmt.Errorf("[PRService | MarkReady]: %w", ErrorPRIsClosed)
+		case enteties.PullRequestStatusArchived:
+			return fmt.Errorf("[PRService | MarkReady]: %w", ErrorPRIsArchived)
+		}
+
+		// уже готов к ревью
+		if !currentPR.IsDraft {
+			respPR = currentPR
+			assigned = 0
+			return nil
+		}
+
+		err = prs.PRRepo.SetPRDraft(ctx, readyReq.PullRequestID, false)
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		reviewers, err := prs.assignReviewers(ctx, readyReq.PullRequestID, currentPR.AuthorID,
+			enteties.AssignmentReasonPRReady)
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		currentPR.IsDraft = false
+		currentPR.AssignedReviewers = reviewers
+		assigned = len(reviewers)
+		currentPR.Reviews = pendingReviews(reviewers)
+		respPR = currentPR
+
+		err = addOutboxEvent(ctx, prs.OutboxRepo, enteties.OutboxEventPRReady, respPR)
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		teamName, err := prs.UserRepo.GetUserTeamName(ctx, currentPR.AuthorID)
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		err = addWebhookEvents(ctx, prs.OutboxRepo, reviewerAssignedEvents(ctx, teamName, respPR, reviewers))
+		if err != nil {
+			return fmt.Errorf("[PRService | MarkReady]: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return nil, err
 	}
 
-	tx, err := prs.Db.Begin(ctx)
+	prs.Metrics.ReviewersAssigned(assigned)
+
+	return respPR, nil
+}
+
+// вспомогательный метод назначает ревьюеров на pull request из активных участников команды
+// автора согласно стратегии и настройкам команды и записывает назначения в историю с причиной
+// reason. Должен вызываться внутри транзакции (tx в контексте)
+func (prs *prService) assignReviewers(ctx context.Context, prID, authorID, reason string) ([]string, error) {
+	// найдем команду автора
+	teamName, err := prs.UserRepo.GetUserTeamName(ctx, authorID)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return nil, fmt.Errorf("[PRService | assignReviewers]: %w", err)
 	}
-	defer tx.Rollback(ctx)
 
-	// добавим tx в контекст
-	ctx = repository.WithTx(ctx, tx)
-
-	// занесем инфо в таблицу
-	prShort, err := prs.PRRepo.CreatePR(ctx, pr)
+	// получим настройки количества ревьюеров команды
+	settings, err := getTeamSettingsOrDefault(ctx, prs.TeamRepo, teamName, prs.ReviewersCount)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return nil, fmt.Errorf("[PRService | assignReviewers]: %w", err)
 	}
 
 	// назначим ревьюеров ( получим список юзеров которые:
 	//1) в той же команде 2) со статусом is_active)
 
-	// найдем сначала всех сокомандников
-	teamMembers, err := prs.UserRepo.GetTeamMembersByTeamName(ctx, teamName)
+	// найдем сначала всех сокомандников, параллельная деактивация дождется конца транзакции
+	teamMembers, err := prs.UserRepo.LockTeamMembers(ctx, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return nil, fmt.Errorf("[PRService | assignReviewers]: %w", err)
 	}
 
 	// получим список доступных к назначению на ревьюера (статус is_active и исполючим автора)
 	futureReviewers := []string{}
 	for _, tm := range teamMembers {
-		if tm.IsActive == true && tm.UserID != pr.AuthorID {
+		if tm.IsActive == true && tm.UserID != authorID {
 			futureReviewers = append(futureReviewers, tm.UserID)
 		}
 	}
 
-	// назначим ревьюеров
-	reviewers := []string{}
-
-	// если их меньше двух доступных, назначаем их ( 0 или 1 )
-	if len(futureReviewers) < 2 {
-		reviewers = futureReviewers
-	} else {
-		// если ревьюеров >= 2 случайным образом выберем количество ревьюеров (0 , 1 , 2)
-		randomNumber := rand.IntN(3)
-
-		for i := 0; i < randomNumber; i++ {
-			// выберем случайного пользователя из списка доступных
-			randomIndexRev := rand.Int32N(int32(len(futureReviewers)))
-			reviewers = append(reviewers, futureReviewers[randomIndexRev])
+	// выберем ревьюеров согласно стратегии
+	reviewers, err := prs.Strategy.SelectReviewers(ctx, futureReviewers, settings.MaxReviewers)
+	if err != nil {
+		return nil, fmt.Errorf("[PRService | assignReviewers]: %w", err)
+	}
 
-			// уберем из списка доступных, чтобы больше его не выбрать
-			futureReviewers = append(futureReviewers[:randomIndexRev], futureReviewers[randomIndexRev+1:]...)
-		}
+	// доступных ревьюеров меньше, чем требуется в настройках команды
+	if len(reviewers) < settings.MinReviewers {
+		return nil, fmt.Errorf("[PRService | assignReviewers]: %w", ErrorNotEnoughReviewers)
 	}
 
 	// занесем назначенных ревьюеров
-	err = prs.PRRepo.SetReviewersBatch(ctx, pr.PullRequestID, reviewers)
+	err = prs.PRRepo.SetReviewersBatch(ctx, prID, reviewers)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return nil, fmt.Errorf("[PRService | assignReviewers]: %w", err)
 	}
 
-	err = tx.Commit(ctx)
+	// запишем назначения в историю
+	err = prs.PRRepo.AddAssignmentEvents(ctx, assignEvents(ctx, prID, reviewers, reason))
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | CreatePR]: %w", err)
+		return nil, fmt.Errorf("[PRService | assignReviewers]: %w", err)
 	}
 
-	return &enteties.PullRequest{
-		PullRequestID:     prShort.PullRequestID,
-		PulRequestName:    prShort.PulRequestName,
-		AuthorID:          prShort.AuthorID,
-		Status:            prShort.Status,
-		AssignedReviewers: reviewers,
-	}, nil
-
+	return reviewers, nil
 }
 
 func (prs *prService) MergePR(ctx context.Context, mergeReq *enteties.MergePullRequest) (*enteties.PullRequest, error) {
-	// проверим, существует ли уже PR с таким id
-	exists, err := prs.PRRepo.PRExists(ctx, mergeReq.PullRequestID)
+	ctx, span := tracing.Start(ctx, "PRService.MergePR")
+	defer span.End()
+
+	var (
+		respPR *enteties.PullRequest
+		// pull request смерджен этим вызовом, а не раньше
+		merged bool
+	)
+
+	// репозитории получают транзакцию через контекст
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// заблокируем pull request, параллельное переназначение дождется мерджа
+		err := prs.PRRepo.LockPRs(ctx, []string{mergeReq.PullRequestID})
+		if err != nil {
+			return fmt.Errorf("[PRService | MergePR]: %w", err)
+		}
+
+		// проверим, существует ли уже PR с таким id
+		exists, err := prs.PRRepo.PRExists(ctx, mergeReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | MergePR]: %w", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("[PRService | MergePR]: %w", ErrorPRNotFound)
+		}
+
+		currentPR, err := prs.PRRepo.GetPR(ctx, mergeReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | MergePR]: %w", err)
+		}
+
+		// мерджить может руководитель команды автора
+		err = prs.authorize(ctx, ActionMergePR, currentPR.AuthorID, "")
+		if err != nil {
+			return fmt.Errorf("[PRService | MergePR]: %w", err)
+		}
+
+		// закрытый pull request нужно сначала переоткрыть
+		switch currentPR.Status {
+		case enteties.PullRequestStatusClosed:
+			return fmt.Errorf("[PRService | MergePR]: %w", ErrorPRIsClosed)
+		case enteties.PullRequestStatusArchived:
+			return fmt.Errorf("[PRService | MergePR]: %w", ErrorPRIsArchived)
+		}
+
+		// черновик без ревьюеров смерджить нельзя
+		if currentPR.IsDraft {
+			return fmt.Errorf("[PRService | MergePR]: %w", ErrorPRIsDraft)
+		}
+
+		// уже смердженный pull request возвращается как есть, одобрения проверяются только у OPEN
+		if currentPR.Status == enteties.PullRequestStatusOpen &&
+			countApprovals(currentPR.Reviews) < prs.RequiredApprovals {
+			return fmt.Errorf("[PRService | MergePR]: %w", ErrorNotEnoughApprovals)
+		}
+
+		// мерджим
+		respPR, err = prs.PRRepo.MergePR(ctx, mergeReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | MergePR]: %w", err)
+		}
+
+		// повторный мердж не отправляет событие
+		merged = currentPR.Status == enteties.PullRequestStatusOpen
+		if merged {
+			teamName, err := prs.UserRepo.GetUserTeamName(ctx, currentPR.AuthorID)
+			if err != nil {
+				return fmt.Errorf("[PRService | MergePR]: %w", err)
+			}
+
+			err = addOutboxEvent(ctx, prs.OutboxRepo, enteties.OutboxEventPRMerged, respPR)
+			if err != nil {
+				return fmt.Errorf("[PRService | MergePR]: %w", err)
+			}
+
+			err = addWebhookEvents(ctx, prs.OutboxRepo, []enteties.WebhookEvent{
+				newWebhookEvent(ctx, enteties.WebhookEventPRMerged, teamName, respPR),
+			})
+			if err != nil {
+				return fmt.Errorf("[PRService | MergePR]: %w", err)
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | MergePR]: %w", err)
+		return nil, err
 	}
 
-	if !exists {
-		return nil, fmt.Errorf("[PRService | MergePR]: %w", ErrorPRNotFound)
+	if merged {
+		prs.Metrics.PRMerged()
 	}
 
-	tx, err := prs.Db.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("[PRService | MergePR]: %w", err)
-	}
-	defer tx.Rollback(ctx)
+	return respPR, nil
+}
 
-	// добавим tx в контекст
-	ctx = repository.WithTx(ctx, tx)
+func (prs *prService) ClosePR(ctx context.Context, closeReq *enteties.ClosePullRequest) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRService.ClosePR")
+	defer span.End()
 
-	// мерджим
-	respPR, err := prs.PRRepo.MergePR(ctx, mergeReq.PullRequestID)
-	if err != nil {
-		return nil, fmt.Errorf("[PRService | MergePR]: %w", err)
-	}
+	var respPR *enteties.PullRequest
+
+	// репозитории получают транзакцию через контекст
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// заблокируем pull request, параллельные мердж и переназначение дождутся закрытия
+		err := prs.PRRepo.LockPRs(ctx, []string{closeReq.PullRequestID})
+		if err != nil {
+			return fmt.Errorf("[PRService | ClosePR]: %w", err)
+		}
+
+		exists, err := prs.PRRepo.PRExists(ctx, closeReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ClosePR]: %w", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("[PRService | ClosePR]: %w", ErrorPRNotFound)
+		}
+
+		currentPR, err := prs.PRRepo.GetPR(ctx, closeReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ClosePR]: %w", err)
+		}
+
+		// закрывать может руководитель команды автора
+		err = prs.authorize(ctx, ActionUpdatePR, currentPR.AuthorID, "")
+		if err != nil {
+			return fmt.Errorf("[PRService | ClosePR]: %w", err)
+		}
+
+		switch currentPR.Status {
+		case enteties.PullRequestStatusMerged:
+			return fmt.Errorf("[PRService | ClosePR]: %w", ErrorPRIsMerged)
+		case enteties.PullRequestStatusArchived:
+			return fmt.Errorf("[PRService | ClosePR]: %w", ErrorPRIsArchived)
+		case enteties.PullRequestStatusClosed:
+			// уже закрыт
+			respPR = currentPR
+			return nil
+		}
+
+		err = prs.PRRepo.SetPRStatus(ctx, closeReq.PullRequestID, enteties.PullRequestStatusClosed)
+		if err != nil {
+			return fmt.Errorf("[PRService | ClosePR]: %w", err)
+		}
 
-	err = tx.Commit(ctx)
+		currentPR.Status = enteties.PullRequestStatusClosed
+		respPR = currentPR
+
+		err = addOutboxEvent(ctx, prs.OutboxRepo, enteties.OutboxEventPRClosed, respPR)
+		if err != nil {
+			return fmt.Errorf("[PRService | ClosePR]: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | MergePR]: %w", err)
+		return nil, err
 	}
 
 	return respPR, nil
 }
 
-func (prs *prService) ReassignPR(ctx context.Context, resp *enteties.ReassignPullRequest) (*enteties.ReassignPullRequestResponce, error) {
-	// проверим, существует ли pr
-	exists, err := prs.PRRepo.PRExists(ctx, resp.PullRequestID)
+func (prs *prService) ReopenPR(ctx context.Context, reopenReq *enteties.ReopenPullRequest) (*enteties.ReopenPullRequestResponce, error) {
+	ctx, span := tracing.Start(ctx, "PRService.ReopenPR")
+	defer span.End()
+
+	var reopened *enteties.ReopenPullRequestResponce
+
+	// репозитории получают транзакцию через контекст
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := prs.PRRepo.LockPRs(ctx, []string{reopenReq.PullRequestID})
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		exists, err := prs.PRRepo.PRExists(ctx, reopenReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", ErrorPRNotFound)
+		}
+
+		currentPR, err := prs.PRRepo.GetPR(ctx, reopenReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		// переоткрывать может руководитель команды автора
+		err = prs.authorize(ctx, ActionUpdatePR, currentPR.AuthorID, "")
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		switch currentPR.Status {
+		case enteties.PullRequestStatusMerged:
+			return fmt.Errorf("[PRService | ReopenPR]: %w", ErrorPRIsMerged)
+		case enteties.PullRequestStatusArchived:
+			// команда автора удалена, переоткрывать pull request некому
+			return fmt.Errorf("[PRService | ReopenPR]: %w", ErrorPRIsArchived)
+		case enteties.PullRequestStatusOpen:
+			// уже открыт
+			reopened = &enteties.ReopenPullRequestResponce{
+				PR:               *currentPR,
+				Reassigned:       make([]enteties.ReviewerReplacement, 0),
+				WithoutCandidate: make([]enteties.ReviewerReplacement, 0),
+			}
+			return nil
+		}
+
+		err = prs.PRRepo.SetPRStatus(ctx, reopenReq.PullRequestID, enteties.PullRequestStatusOpen)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		// пока pull request был закрыт, деактивация не снимала с него ревьюеров
+		replacements, err := prs.replaceInactiveReviewers(ctx, currentPR)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		err = prs.PRRepo.ReplaceReviewersBatch(ctx, replacements)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		err = prs.PRRepo.AddAssignmentEvents(ctx, replacementEvents(ctx, replacements,
+			enteties.AssignmentReasonPRReopened))
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		pr, err := prs.PRRepo.GetPR(ctx, reopenReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		reassigned, withoutCandidate := splitReplacements(replacements)
+
+		reopened = &enteties.ReopenPullRequestResponce{
+			PR:               *pr,
+			Reassigned:       reassigned,
+			WithoutCandidate: withoutCandidate,
+		}
+
+		err = addOutboxEvent(ctx, prs.OutboxRepo, enteties.OutboxEventPRReopened, reopened)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		teamName, err := prs.UserRepo.GetUserTeamName(ctx, pr.AuthorID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		err = addWebhookEvents(ctx, prs.OutboxRepo, reviewerReassignedEvents(ctx, teamName, pr, replacements))
+		if err != nil {
+			return fmt.Errorf("[PRService | ReopenPR]: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		return nil, err
 	}
-	if !exists {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", ErrorPRNotFound)
+
+	recordReassigned(prs.Metrics, reopened.Reassigned, enteties.AssignmentReasonPRReopened)
+
+	return reopened, nil
+}
+
+// вспомогательный метод подбирает замены неактивным ревьюерам pull request из команды автора.
+// Если кандидата нет, ревьюер снимается без замены (ReplacedBy пустой). Должен вызываться
+// внутри транзакции (tx в контексте)
+func (prs *prService) replaceInactiveReviewers(ctx context.Context, pr *enteties.PullRequest) ([]enteties.ReviewerReplacement, error) {
+	replacements := make([]enteties.ReviewerReplacement, 0)
+	if len(pr.AssignedReviewers) == 0 {
+		return replacements, nil
 	}
 
-	// проверим, существует ли пользователь
-	exists, err = prs.UserRepo.UserExists(ctx, resp.OldUserID)
+	reviewers, err := prs.UserRepo.GetUsers(ctx, pr.AssignedReviewers)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
-	}
-	if !exists {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", ErrorUserNotFound)
+		return nil, fmt.Errorf("[PRService | replaceInactiveReviewers]: %w", err)
 	}
 
-	// проверим, не смерджен ли уже pr
-	isMerged, err := prs.PRRepo.IsMerged(ctx, resp.PullRequestID)
-	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+	inactive := make([]string, 0)
+	for _, reviewer := range reviewers {
+		if !reviewer.IsActive {
+			inactive = append(inactive, reviewer.UserID)
+		}
 	}
-	if isMerged {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", ErrorPRIsMerged)
+
+	if len(inactive) == 0 {
+		return replacements, nil
 	}
 
-	// проверим, назначен ли пользователь ревьюером на этот pr
-	isReviewed, err := prs.PRRepo.IsUserAssignedToPR(ctx, resp.OldUserID, resp.PullRequestID)
+	teamName, err := prs.UserRepo.GetUserTeamName(ctx, pr.AuthorID)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		return nil, fmt.Errorf("[PRService | replaceInactiveReviewers]: %w", err)
 	}
-	if !isReviewed {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", ErrorUserNotAssigned)
+
+	teamMembers := make([]*enteties.TeamMember, 0)
+	if teamName != "" {
+		teamMembers, err = prs.UserRepo.LockTeamMembers(ctx, teamName)
+		if err != nil {
+			return nil, fmt.Errorf("[PRService | replaceInactiveReviewers]: %w", err)
+		}
 	}
 
-	// найдем команду заменяемого
-	teamName, err := prs.UserRepo.GetUserTeamName(ctx, resp.OldUserID)
-	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+	assigned := append([]string{}, pr.AssignedReviewers...)
+	for _, userID := range inactive {
+		replacement := enteties.ReviewerReplacement{
+			PullRequestID: pr.PullRequestID,
+			OldUserID:     userID,
+		}
+
+		newReviewer, err := selectReplacement(ctx, prs.Strategy, teamMembers, pr.AuthorID, assigned)
+		switch {
+		case err == nil:
+			replacement.ReplacedBy = newReviewer
+			assigned = append(assigned, newReviewer)
+		case !errors.Is(err, ErrorNoCandidateToReassign):
+			return nil, fmt.Errorf("[PRService | replaceInactiveReviewers]: %w", err)
+		}
+
+		replacements = append(replacements, replacement)
 	}
 
-	// найдем автора pr, чтобы его не поставить ревьюером
-	author, err := prs.PRRepo.GetAuthorPR(ctx, resp.PullRequestID)
+	return replacements, nil
+}
+
+func (prs *prService) ReassignPR(ctx context.Context, resp *enteties.ReassignPullRequest) (*enteties.ReassignPullRequestResponce, error) {
+	ctx, span := tracing.Start(ctx, "PRService.ReassignPR")
+	defer span.End()
+
+	var (
+		reassigned *enteties.ReassignPullRequestResponce
+		reason     string
+	)
+
+	// все проверки выполняются под блокировкой pull request, поэтому параллельные мердж и
+	// переназначение не могут изменить его между проверкой и заменой ревьюера
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := prs.PRRepo.LockPRs(ctx, []string{resp.PullRequestID})
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// проверим, существует ли pr
+		exists, err := prs.PRRepo.PRExists(ctx, resp.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", ErrorPRNotFound)
+		}
+
+		// проверим, существует ли пользователь
+		exists, err = prs.UserRepo.UserExists(ctx, resp.OldUserID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", ErrorUserNotFound)
+		}
+
+		// получим текущее состояние pr (статус, автора и ревьюеров)
+		currentPR, err := prs.PRRepo.GetPR(ctx, resp.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// переназначать может руководитель команды автора или сам ревьюер
+		err = prs.authorize(ctx, ActionReassignPR, currentPR.AuthorID, resp.OldUserID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// переназначать ревьюеров можно только на OPEN pr
+		switch currentPR.Status {
+		case enteties.PullRequestStatusMerged:
+			return fmt.Errorf("[PRService | ReassignPR]: %w", ErrorPRIsMerged)
+		case enteties.PullRequestStatusClosed:
+			return fmt.Errorf("[PRService | ReassignPR]: %w", ErrorPRIsClosed)
+		case enteties.PullRequestStatusArchived:
+			return fmt.Errorf("[PRService | ReassignPR]: %w", ErrorPRIsArchived)
+		}
+
+		// проверим, назначен ли пользователь ревьюером на этот pr
+		isReviewed, err := prs.PRRepo.IsUserAssignedToPR(ctx, resp.OldUserID, resp.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+		if !isReviewed {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", ErrorUserNotAssigned)
+		}
+
+		// найдем команду заменяемого
+		teamName, err := prs.UserRepo.GetUserTeamName(ctx, resp.OldUserID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// причина для истории назначений и метрик
+		reason = resp.Reason
+		if reason == "" {
+			reason = enteties.AssignmentReasonManualReassign
+		}
+
+		// ревьюер заменяется один на один, поэтому количество ревьюеров не меняется и настройки команды
+		// автора не проверяются. Новый максимум применяется при следующем назначении ревьюеров
+
+		// найдем сначала всех сокомандников, параллельная деактивация дождется конца транзакции
+		teamMembers, err := prs.UserRepo.LockTeamMembers(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// автора pr не ставим ревьюером
+		newReviewer, err := selectReplacement(ctx, prs.Strategy, teamMembers, currentPR.AuthorID,
+			currentPR.AssignedReviewers)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// перезапишем связь в таблице assigned_reviewers
+		err = prs.PRRepo.ReassignReviewer(ctx, resp.PullRequestID, resp.OldUserID, newReviewer)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		replacements := []enteties.ReviewerReplacement{{
+			PullRequestID: resp.PullRequestID,
+			OldUserID:     resp.OldUserID,
+			ReplacedBy:    newReviewer,
+		}}
+
+		// запишем замену ревьюера в историю
+		err = prs.PRRepo.AddAssignmentEvents(ctx, replacementEvents(ctx, replacements, reason))
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// получим новый PR
+		pr, err := prs.PRRepo.GetPR(ctx, resp.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		reassigned = &enteties.ReassignPullRequestResponce{
+			PR:         *pr,
+			ReplacedBy: newReviewer,
+		}
+
+		err = addOutboxEvent(ctx, prs.OutboxRepo, enteties.OutboxEventReviewerReassigned, reassigned)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		// подписчики - команда автора, а не заменяемого ревьюера
+		authorTeamName, err := prs.UserRepo.GetUserTeamName(ctx, pr.AuthorID)
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		err = addWebhookEvents(ctx, prs.OutboxRepo, reviewerReassignedEvents(ctx, authorTeamName, pr, replacements))
+		if err != nil {
+			return fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		if errors.Is(err, ErrorNoCandidateToReassign) {
+			prs.Metrics.NoCandidate()
+		}
+		return nil, err
 	}
 
-	tx, err := prs.Db.Begin(ctx)
+	prs.Metrics.ReviewersReassigned(reason, 1)
+
+	return reassigned, nil
+}
+
+func (prs *prService) SubmitReview(ctx context.Context, reviewReq *enteties.ReviewPullRequest) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRService.SubmitReview")
+	defer span.End()
+
+	var respPR *enteties.PullRequest
+
+	// репозитории получают транзакцию через контекст
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// заблокируем pull request, параллельные мердж и переназначение дождутся решения
+		err := prs.PRRepo.LockPRs(ctx, []string{reviewReq.PullRequestID})
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+
+		exists, err := prs.PRRepo.PRExists(ctx, reviewReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", ErrorPRNotFound)
+		}
+
+		exists, err = prs.UserRepo.UserExists(ctx, reviewReq.UserID)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", ErrorUserNotFound)
+		}
+
+		currentPR, err := prs.PRRepo.GetPR(ctx, reviewReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+
+		// решение принимает сам ревьюер или руководитель команды автора
+		err = prs.authorize(ctx, ActionReviewPR, currentPR.AuthorID, reviewReq.UserID)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+
+		// решения принимаются только по OPEN pr
+		switch currentPR.Status {
+		case enteties.PullRequestStatusMerged:
+			return fmt.Errorf("[PRService | SubmitReview]: %w", ErrorPRIsMerged)
+		case enteties.PullRequestStatusClosed:
+			return fmt.Errorf("[PRService | SubmitReview]: %w", ErrorPRIsClosed)
+		case enteties.PullRequestStatusArchived:
+			return fmt.Errorf("[PRService | SubmitReview]: %w", ErrorPRIsArchived)
+		}
+
+		// решение может принять только назначенный ревьюер
+		isReviewed, err := prs.PRRepo.IsUserAssignedToPR(ctx, reviewReq.UserID, reviewReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+		if !isReviewed {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", ErrorUserNotAssigned)
+		}
+
+		err = prs.PRRepo.SetReviewState(ctx, reviewReq.PullRequestID, reviewReq.UserID, reviewReq.State)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+
+		respPR, err = prs.PRRepo.GetPR(ctx, reviewReq.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+
+		err = addOutboxEvent(ctx, prs.OutboxRepo, enteties.OutboxEventReviewSubmitted, respPR)
+		if err != nil {
+			return fmt.Errorf("[PRService | SubmitReview]: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback(ctx)
 
-	// добавим tx в контекст
-	ctx = repository.WithTx(ctx, tx)
+	return respPR, nil
+}
 
-	// найдем сначала всех сокомандников
-	teamMembers, err := prs.UserRepo.GetTeamMembersByTeamName(ctx, teamName)
-	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+// вспомогательная функция возвращает решения только что назначенных ревьюеров
+func pendingReviews(reviewers []string) []enteties.ReviewerReview {
+	reviews := make([]enteties.ReviewerReview, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		reviews = append(reviews, enteties.ReviewerReview{
+			UserID: reviewer,
+			State:  enteties.ReviewStatePending,
+		})
 	}
 
-	// получим список доступных к назначению на ревьюера (статус is_active и исполючим автора)
-	futureReviewers := []string{}
-	for _, tm := range teamMembers {
-		if tm.IsActive == true && tm.UserID != resp.OldUserID && tm.UserID != author {
-			futureReviewers = append(futureReviewers, tm.UserID)
+	return reviews
+}
+
+// вспомогательная функция считает одобрения назначенных ревьюеров
+func countApprovals(reviews []enteties.ReviewerReview) int {
+	approvals := 0
+	for _, review := range reviews {
+		if review.State == enteties.ReviewStateApproved {
+			approvals++
 		}
 	}
 
-	// не доступных сокомандников для замены
-	if len(futureReviewers) == 0 {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", ErrorNoCandidateToReassign)
+	return approvals
+}
+
+func (prs *prService) GetHistory(ctx context.Context, prID string) (*enteties.PullRequestHistory, error) {
+	ctx, span := tracing.Start(ctx, "PRService.GetHistory")
+	defer span.End()
+
+	// проверим, существует ли pr
+	exists, err := prs.PRRepo.PRExists(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("[PRService | GetHistory]: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("[PRService | GetHistory]: %w", ErrorPRNotFound)
+	}
+
+	events, err := prs.PRRepo.GetAssignmentEvents(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("[PRService | GetHistory]: %w", err)
 	}
 
-	// выберем случайного пользователя из списка доступных
-	randomIndexRev := rand.Int32N(int32(len(futureReviewers)))
+	return &enteties.PullRequestHistory{
+		PullRequestID: prID,
+		Events:        events,
+	}, nil
+}
+
+func (prs *prService) GetPR(ctx context.Context, prID string) (*enteties.PullRequest, error) {
+	ctx, span := tracing.Start(ctx, "PRService.GetPR")
+	defer span.End()
+
+	var result *enteties.PullRequest
+
+	// pull request и его ревьюеры читаются в одной транзакции
+	err := prs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// проверим, существует ли pr
+		exists, err := prs.PRRepo.PRExists(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("[PRService | GetPR]: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("[PRService | GetPR]: %w", ErrorPRNotFound)
+		}
 
-	newReviewer := futureReviewers[randomIndexRev]
+		result, err = prs.PRRepo.GetPR(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("[PRService | GetPR]: %w", err)
+		}
 
-	// перезапишем связь в таблице assigned_reviewers
-	err = prs.PRRepo.ReassignReviewer(ctx, resp.PullRequestID, resp.OldUserID, newReviewer)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		return nil, err
 	}
 
-	// получим новый PR
-	pr, err := prs.PRRepo.GetPR(ctx, resp.PullRequestID)
+	return result, nil
+}
+
+func (prs *prService) ListPRs(ctx context.Context, filter *enteties.PullRequestListFilter) (*enteties.PullRequestList, error) {
+	ctx, span := tracing.Start(ctx, "PRService.ListPRs")
+	defer span.End()
+
+	after, err := decodeCursor(filter.Cursor)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		return nil, fmt.Errorf("[PRService | ListPRs]: %w", err)
 	}
 
-	err = tx.Commit(ctx)
+	limit := pageLimit(filter.Limit)
+
+	// читаем на один pull request больше, чтобы понять, есть ли следующая страница
+	shortPRptrs, err := prs.PRRepo.ListPRs(ctx, filter, after, limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("[PRService | ReassignPR]: %w", err)
+		return nil, fmt.Errorf("[PRService | ListPRs]: %w", err)
+	}
+
+	page, nextCursor := cutPage(shortPRptrs, limit)
+
+	shortPRs := make([]enteties.PullRequestShort, 0, len(page))
+	for _, pr := range page {
+		shortPRs = append(shortPRs, *pr)
 	}
 
-	return &enteties.ReassignPullRequestResponce{
-		PR:         *pr,
-		ReplacedBy: newReviewer,
+	return &enteties.PullRequestList{
+		PullRequests: shortPRs,
+		NextCursor:   nextCursor,
 	}, nil
 }
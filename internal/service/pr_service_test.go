@@ -0,0 +1,371 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRService_PullRequestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+
+	pr, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusOpen, pr.Status)
+	assert.Len(t, pr.AssignedReviewers, 2)
+	assert.NotContains(t, pr.AssignedReviewers, "u1")
+
+	oldReviewer := pr.AssignedReviewers[0]
+	reassigned, err := s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     oldReviewer,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, "u1", reassigned.ReplacedBy)
+	assert.NotContains(t, reassigned.PR.AssignedReviewers, oldReviewer)
+	assert.Contains(t, reassigned.PR.AssignedReviewers, reassigned.ReplacedBy)
+	assert.Len(t, reassigned.PR.AssignedReviewers, 2)
+
+	reviews, err := s.user.GetReviews(ctx, &enteties.UserReviewsFilter{UserID: reassigned.ReplacedBy})
+	require.NoError(t, err)
+	require.Len(t, reviews.PullRequests, 1)
+	assert.Equal(t, "pr1", reviews.PullRequests[0].PullRequestID)
+
+	merged, err := s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusMerged, merged.Status)
+	require.NotNil(t, merged.MergedAt)
+
+	// повторный мердж не меняет дату
+	mergedAgain, err := s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.Equal(t, *merged.MergedAt, *mergedAgain.MergedAt)
+
+	_, err = s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     reassigned.ReplacedBy,
+	})
+	assert.ErrorIs(t, err, ErrorPRIsMerged)
+
+	history, err := s.pr.GetHistory(ctx, "pr1")
+	require.NoError(t, err)
+	require.Len(t, history.Events, 3)
+	assert.Equal(t, enteties.AssignmentEventAssign, history.Events[0].EventType)
+	assert.Equal(t, enteties.AssignmentEventAssign, history.Events[1].EventType)
+	assert.Equal(t, enteties.AssignmentEventReassign, history.Events[2].EventType)
+	assert.Equal(t, oldReviewer, history.Events[2].OldUserID)
+}
+
+func TestPRService_CloseAndReopen(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+
+	pr, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	require.Len(t, pr.AssignedReviewers, 2)
+
+	closed, err := s.pr.ClosePR(ctx, &enteties.ClosePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusClosed, closed.Status)
+
+	// закрытый pull request пропадает из очереди ревью
+	reviews, err := s.user.GetReviews(ctx, &enteties.UserReviewsFilter{UserID: pr.AssignedReviewers[0]})
+	require.NoError(t, err)
+	assert.Empty(t, reviews.PullRequests)
+
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	assert.ErrorIs(t, err, ErrorPRIsClosed)
+
+	_, err = s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     pr.AssignedReviewers[0],
+	})
+	assert.ErrorIs(t, err, ErrorPRIsClosed)
+
+	// пока pull request закрыт, деактивация не трогает его ревьюеров
+	inactive := pr.AssignedReviewers[0]
+	_, err = s.user.SetIsActive(ctx, inactive, false)
+	require.NoError(t, err)
+
+	reopened, err := s.pr.ReopenPR(ctx, &enteties.ReopenPullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusOpen, reopened.PR.Status)
+	require.Len(t, reopened.Reassigned, 1)
+	assert.Empty(t, reopened.WithoutCandidate)
+	assert.Equal(t, inactive, reopened.Reassigned[0].OldUserID)
+	assert.NotContains(t, reopened.PR.AssignedReviewers, inactive)
+	assert.NotContains(t, reopened.PR.AssignedReviewers, "u1")
+	assert.Len(t, reopened.PR.AssignedReviewers, 2)
+
+	// повторное переоткрытие ничего не меняет
+	again, err := s.pr.ReopenPR(ctx, &enteties.ReopenPullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, reopened.PR.AssignedReviewers, again.PR.AssignedReviewers)
+	assert.Empty(t, again.Reassigned)
+
+	merged, err := s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusMerged, merged.Status)
+
+	_, err = s.pr.ClosePR(ctx, &enteties.ClosePullRequest{PullRequestID: "pr1"})
+	assert.ErrorIs(t, err, ErrorPRIsMerged)
+
+	_, err = s.pr.ReopenPR(ctx, &enteties.ReopenPullRequest{PullRequestID: "pr1"})
+	assert.ErrorIs(t, err, ErrorPRIsMerged)
+}
+
+func TestPRService_DraftPullRequest(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+
+	draft, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+		IsDraft:         true,
+	})
+	require.NoError(t, err)
+	assert.True(t, draft.IsDraft)
+	assert.Empty(t, draft.AssignedReviewers)
+
+	// черновик нельзя смерджить
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	assert.ErrorIs(t, err, ErrorPRIsDraft)
+
+	ready, err := s.pr.MarkReady(ctx, &enteties.MarkReadyPullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.False(t, ready.IsDraft)
+	assert.Len(t, ready.AssignedReviewers, 2)
+	assert.NotContains(t, ready.AssignedReviewers, "u1")
+
+	// повторный вызов не переназначает ревьюеров
+	again, err := s.pr.MarkReady(ctx, &enteties.MarkReadyPullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, ready.AssignedReviewers, again.AssignedReviewers)
+
+	history, err := s.pr.GetHistory(ctx, "pr1")
+	require.NoError(t, err)
+	require.Len(t, history.Events, 2)
+	assert.Equal(t, enteties.AssignmentReasonPRReady, history.Events[0].Reason)
+
+	// ревьюер видит pull request в очереди после перевода в готовый к ревью
+	reviews, err := s.user.GetReviews(ctx, &enteties.UserReviewsFilter{UserID: ready.AssignedReviewers[0]})
+	require.NoError(t, err)
+	require.Len(t, reviews.PullRequests, 1)
+	assert.False(t, reviews.PullRequests[0].IsDraft)
+
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+
+	_, err = s.pr.MarkReady(ctx, &enteties.MarkReadyPullRequest{PullRequestID: "pr1"})
+	assert.ErrorIs(t, err, ErrorPRIsMerged)
+
+	_, err = s.pr.MarkReady(ctx, &enteties.MarkReadyPullRequest{PullRequestID: "unknown"})
+	assert.ErrorIs(t, err, ErrorPRNotFound)
+}
+
+func TestPRService_ReviewApprovals(t *testing.T) {
+	ctx := context.Background()
+	// мердж требует два одобрения
+	s := newMemoryServicesWithApprovals(t, 2)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+
+	pr, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	require.Len(t, pr.Reviews, 2)
+	assert.Equal(t, enteties.ReviewStatePending, pr.Reviews[0].State)
+
+	first, second := pr.AssignedReviewers[0], pr.AssignedReviewers[1]
+
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	assert.ErrorIs(t, err, ErrorNotEnoughApprovals)
+
+	// решение может принять только назначенный ревьюер
+	_, err = s.pr.SubmitReview(ctx, &enteties.ReviewPullRequest{
+		PullRequestID: "pr1",
+		UserID:        "u1",
+		State:         enteties.ReviewStateApproved,
+	})
+	assert.ErrorIs(t, err, ErrorUserNotAssigned)
+
+	reviewed, err := s.pr.SubmitReview(ctx, &enteties.ReviewPullRequest{
+		PullRequestID: "pr1",
+		UserID:        first,
+		State:         enteties.ReviewStateApproved,
+	})
+	require.NoError(t, err)
+	require.Len(t, reviewed.Reviews, 2)
+	assert.Equal(t, enteties.ReviewStateApproved, reviewed.Reviews[0].State)
+	assert.NotNil(t, reviewed.Reviews[0].ReviewedAt)
+	assert.Equal(t, enteties.ReviewStatePending, reviewed.Reviews[1].State)
+	assert.Nil(t, reviewed.Reviews[1].ReviewedAt)
+
+	_, err = s.pr.SubmitReview(ctx, &enteties.ReviewPullRequest{
+		PullRequestID: "pr1",
+		UserID:        second,
+		State:         enteties.ReviewStateChangesRequested,
+	})
+	require.NoError(t, err)
+
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	assert.ErrorIs(t, err, ErrorNotEnoughApprovals)
+
+	// решение заменяемого ревьюера к новому не переходит
+	reassigned, err := s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     first,
+	})
+	require.NoError(t, err)
+	for _, review := range reassigned.PR.Reviews {
+		if review.UserID == reassigned.ReplacedBy {
+			assert.Equal(t, enteties.ReviewStatePending, review.State)
+		}
+	}
+
+	for _, reviewer := range reassigned.PR.AssignedReviewers {
+		_, err = s.pr.SubmitReview(ctx, &enteties.ReviewPullRequest{
+			PullRequestID: "pr1",
+			UserID:        reviewer,
+			State:         enteties.ReviewStateApproved,
+		})
+		require.NoError(t, err)
+	}
+
+	merged, err := s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusMerged, merged.Status)
+
+	_, err = s.pr.SubmitReview(ctx, &enteties.ReviewPullRequest{
+		PullRequestID: "pr1",
+		UserID:        second,
+		State:         enteties.ReviewStateApproved,
+	})
+	assert.ErrorIs(t, err, ErrorPRIsMerged)
+}
+
+func TestPRService_ListPullRequests(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3")
+	createTestTeam(t, s, "frontend", "u4", "u5", "u6")
+
+	for _, req := range []enteties.CreatePullRequest{
+		{PullRequestID: "pr1", PullRequestName: "Fix login", AuthorID: "u1"},
+		{PullRequestID: "pr2", PullRequestName: "Add feature", AuthorID: "u1"},
+		{PullRequestID: "pr3", PullRequestName: "fix build", AuthorID: "u4", IsDraft: true},
+	} {
+		_, err := s.pr.CreatePR(ctx, &req)
+		require.NoError(t, err)
+	}
+
+	start := time.Now().UTC().Add(-time.Minute)
+
+	_, err := s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+
+	list := func(filter enteties.PullRequestListFilter) []string {
+		result := make([]string, 0)
+		for {
+			page, err := s.pr.ListPRs(ctx, &filter)
+			require.NoError(t, err)
+
+			for _, pr := range page.PullRequests {
+				result = append(result, pr.PullRequestID)
+			}
+
+			if page.NextCursor == "" {
+				return result
+			}
+			filter.Cursor = page.NextCursor
+		}
+	}
+
+	// без фильтров возвращаются все pull request, включая черновики
+	assert.Equal(t, []string{"pr3", "pr2", "pr1"}, list(enteties.PullRequestListFilter{Limit: 1}))
+	assert.Equal(t, []string{"pr1", "pr2", "pr3"}, list(enteties.PullRequestListFilter{Limit: 2, Sort: enteties.SortOrderAsc}))
+
+	assert.Equal(t, []string{"pr3", "pr1"}, list(enteties.PullRequestListFilter{Name: "FIX"}))
+	assert.Equal(t, []string{"pr3"}, list(enteties.PullRequestListFilter{TeamName: "frontend"}))
+	assert.Equal(t, []string{"pr2", "pr1"}, list(enteties.PullRequestListFilter{ReviewerID: "u2"}))
+	assert.Equal(t, []string{"pr1"}, list(enteties.PullRequestListFilter{Status: enteties.PullRequestStatusMerged, AuthorID: "u1"}))
+	assert.Equal(t, []string{"pr1"}, list(enteties.PullRequestListFilter{MergedFrom: &start}))
+	assert.Empty(t, list(enteties.PullRequestListFilter{CreatedTo: &start}))
+
+	pr, err := s.pr.GetPR(ctx, "pr1")
+	require.NoError(t, err)
+	assert.Equal(t, enteties.PullRequestStatusMerged, pr.Status)
+	assert.Len(t, pr.Reviews, 2)
+
+	_, err = s.pr.GetPR(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrorPRNotFound)
+
+	_, err = s.pr.ListPRs(ctx, &enteties.PullRequestListFilter{Cursor: "broken"})
+	assert.ErrorIs(t, err, ErrorInvalidCursor)
+}
+
+func TestPRService_ReassignAboveMaxReviewers(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+
+	pr, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	require.Len(t, pr.AssignedReviewers, 2)
+
+	// максимум команды уменьшен после назначения ревьюеров
+	minReviewers, maxReviewers := 0, 1
+	_, err = s.team.UpdateTeamSettings(ctx, &enteties.UpdateTeamSettings{
+		TeamName:     "backend",
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	})
+	require.NoError(t, err)
+
+	// переназначение заменяет ревьюера, а не снимает его
+	oldReviewer := pr.AssignedReviewers[0]
+	reassigned, err := s.pr.ReassignPR(ctx, &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     oldReviewer,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, reassigned.ReplacedBy)
+	assert.Len(t, reassigned.PR.AssignedReviewers, 2)
+	assert.NotContains(t, reassigned.PR.AssignedReviewers, oldReviewer)
+
+	history, err := s.pr.GetHistory(ctx, "pr1")
+	require.NoError(t, err)
+	require.Len(t, history.Events, 3)
+	assert.Equal(t, enteties.AssignmentEventReassign, history.Events[2].EventType)
+	assert.Equal(t, enteties.AssignmentReasonManualReassign, history.Events[2].Reason)
+	assert.Equal(t, map[string]int{enteties.AssignmentReasonManualReassign: 1}, s.metrics.reassigned)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"context"
+	"fmt"
+)
+
+// вспомогательная функция возвращает user_id кандидатов на замену ревьюера: активных участников,
+// которые не являются автором и еще не назначены на pull request
+func replacementCandidates(teamMembers []*enteties.TeamMember, author string, assigned []string) []string {
+
+	// уже назначенных ревьюеров (в том числе заменяемого) повторно не назначаем
+	isAssigned := make(map[string]bool, len(assigned))
+	for _, userID := range assigned {
+		isAssigned[userID] = true
+	}
+
+	// получим список доступных к назначению на ревьюера (статус is_active и исполючим автора)
+	futureReviewers := []string{}
+	for _, tm := range teamMembers {
+		if tm.IsActive && tm.UserID != author && !isAssigned[tm.UserID] {
+			futureReviewers = append(futureReviewers, tm.UserID)
+		}
+	}
+
+	return futureReviewers
+}
+
+// вспомогательная функция выбирает замену ревьюеру из участников команды согласно стратегии
+func selectReplacement(ctx context.Context, strategy ReviewerStrategy, teamMembers []*enteties.TeamMember,
+	author string, assigned []string) (string, error) {
+
+	futureReviewers := replacementCandidates(teamMembers, author, assigned)
+
+	// не доступных сокомандников для замены
+	if len(futureReviewers) == 0 {
+		return "", fmt.Errorf("[selectReplacement]: %w", ErrorNoCandidateToReassign)
+	}
+
+	// выберем нового ревьюера согласно стратегии
+	newReviewers, err := strategy.SelectReviewers(ctx, futureReviewers, 1)
+	if err != nil {
+		return "", fmt.Errorf("[selectReplacement]: %w", err)
+	}
+
+	return newReviewers[0], nil
+}
+
+// вспомогательная функция переназначает все OPEN ревью пользователя на кандидатов из candidates
+// (например, участников команды). Если кандидата для pull request нет, пользователь снимается
+// с ревью без замены. Замены записываются в историю назначений с причиной reason. Должна
+// вызываться внутри транзакции (tx в контексте)
+func reassignOpenReviews(ctx context.Context, prRepo repository.PRRepository, strategy ReviewerStrategy,
+	userID string, candidates []*enteties.TeamMember, reason string) ([]enteties.ReviewerReplacement, error) {
+
+	replacements, err := reassignOpenReviewsBatch(ctx, prRepo, strategy,
+		map[string][]*enteties.TeamMember{userID: candidates}, reason)
+	if err != nil {
+		return nil, fmt.Errorf("[reassignOpenReviews]: %w", err)
+	}
+
+	return replacements, nil
+}
+
+// вспомогательная функция переназначает все OPEN ревью сразу нескольких пользователей.
+// candidatesByUser содержит для каждого снимаемого с ревью user_id список кандидатов ему на замену.
+// Все pull request, загрузка кандидатов и замены читаются и записываются пачками, поэтому
+// количество запросов к базе не зависит от количества pull request. Если кандидата нет,
+// пользователь снимается с ревью без замены (ReplacedBy пустой). Замены записываются в историю
+// назначений с причиной reason. Должна вызываться внутри транзакции (tx в контексте)
+func reassignOpenReviewsBatch(ctx context.Context, prRepo repository.PRRepository, strategy ReviewerStrategy,
+	candidatesByUser map[string][]*enteties.TeamMember, reason string) ([]enteties.ReviewerReplacement, error) {
+
+	replacements := make([]enteties.ReviewerReplacement, 0)
+	if len(candidatesByUser) == 0 {
+		return replacements, nil
+	}
+
+	usersID := make([]string, 0, len(candidatesByUser))
+	for userID := range candidatesByUser {
+		usersID = append(usersID, userID)
+	}
+
+	openPRs, err := prRepo.GetOpenPRsByReviewers(ctx, usersID)
+	if err != nil {
+		return nil, fmt.Errorf("[reassignOpenReviewsBatch]: %w", err)
+	}
+
+	if len(openPRs) == 0 {
+		return replacements, nil
+	}
+
+	// заблокируем найденные pull request и перечитаем их: за время между чтением и блокировкой
+	// pull request мог быть смерджен или его ревьюеры могли смениться
+	prIDs := make([]string, 0, len(openPRs))
+	for _, pr := range openPRs {
+		prIDs = append(prIDs, pr.PullRequestID)
+	}
+
+	err = prRepo.LockPRs(ctx, prIDs)
+	if err != nil {
+		return nil, fmt.Errorf("[reassignOpenReviewsBatch]: %w", err)
+	}
+
+	openPRs, err = prRepo.GetOpenPRsByReviewers(ctx, usersID)
+	if err != nil {
+		return nil, fmt.Errorf("[reassignOpenReviewsBatch]: %w", err)
+	}
+
+	// загрузку всех возможных кандидатов получим одним запросом и будем обновлять по ходу
+	var allCandidates []string
+	seen := make(map[string]bool)
+	for _, candidates := range candidatesByUser {
+		for _, tm := range candidates {
+			if !seen[tm.UserID] {
+				seen[tm.UserID] = true
+				allCandidates = append(allCandidates, tm.UserID)
+			}
+		}
+	}
+
+	load := make(map[string]int)
+	if len(allCandidates) > 0 {
+		load, err = prRepo.CountOpenReviewsByUsers(ctx, allCandidates)
+		if err != nil {
+			return nil, fmt.Errorf("[reassignOpenReviewsBatch]: %w", err)
+		}
+	}
+
+	for _, pr := range openPRs {
+		assigned := append([]string{}, pr.AssignedReviewers...)
+
+		for _, reviewer := range pr.AssignedReviewers {
+			candidates, ok := candidatesByUser[reviewer]
+			if !ok {
+				continue
+			}
+
+			replacement := enteties.ReviewerReplacement{
+				PullRequestID: pr.PullRequestID,
+				OldUserID:     reviewer,
+			}
+
+			// снимаемые с ревью пользователи не могут заменить друг друга
+			futureReviewers := make([]string, 0)
+			for _, userID := range replacementCandidates(candidates, pr.AuthorID, assigned) {
+				if _, removed := candidatesByUser[userID]; !removed {
+					futureReviewers = append(futureReviewers, userID)
+				}
+			}
+
+			if newReviewers := strategy.SelectReviewersByLoad(futureReviewers, 1, load); len(newReviewers) > 0 {
+				replacement.ReplacedBy = newReviewers[0]
+				assigned = append(assigned, replacement.ReplacedBy)
+				load[replacement.ReplacedBy]++
+			}
+
+			replacements = append(replacements, replacement)
+		}
+	}
+
+	err = prRepo.ReplaceReviewersBatch(ctx, replacements)
+	if err != nil {
+		return nil, fmt.Errorf("[reassignOpenReviewsBatch]: %w", err)
+	}
+
+	err = prRepo.AddAssignmentEvents(ctx, replacementEvents(ctx, replacements, reason))
+	if err != nil {
+		return nil, fmt.Errorf("[reassignOpenReviewsBatch]: %w", err)
+	}
+
+	return replacements, nil
+}
+
+// вспомогательная функция делит замены на успешные и оставшиеся без кандидата
+func splitReplacements(replacements []enteties.ReviewerReplacement) (reassigned, withoutCandidate []enteties.ReviewerReplacement) {
+	reassigned = make([]enteties.ReviewerReplacement, 0, len(replacements))
+	withoutCandidate = make([]enteties.ReviewerReplacement, 0)
+
+	for _, r := range replacements {
+		if r.ReplacedBy == "" {
+			withoutCandidate = append(withoutCandidate, r)
+			continue
+		}
+
+		reassigned = append(reassigned, r)
+	}
+
+	return reassigned, withoutCandidate
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"avito_intern/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+)
+
+const (
+	ReviewerStrategyRandom           = "random"
+	ReviewerStrategyLeastOpenReviews = "least_open_reviews"
+)
+
+var (
+	ErrorUnknownReviewerStrategy = errors.New("unknown reviewer strategy")
+)
+
+type ReviewerStrategy interface {
+	/* метод выбирает ревьюеров из списка кандидатов. Принимает на вход список user_id
+	кандидатов и необходимое количество ревьюеров. Если кандидатов не меньше count,
+	возвращает ровно count ревьюеров, иначе всех кандидатов*/
+	SelectReviewers(ctx context.Context, candidates []string, count int) ([]string, error)
+
+	/* метод выбирает ревьюеров так же, как SelectReviewers, но по заранее известной загрузке
+	кандидатов (user_id -> количество OPEN ревью) без обращения к базе. Используется при
+	массовом переназначении ревью*/
+	SelectReviewersByLoad(candidates []string, count int, load map[string]int) []string
+}
+
+// NewReviewerStrategy возвращает стратегию выбора ревьюеров по ее названию из конфигурации
+func NewReviewerStrategy(name string, prRepo repository.PRRepository) (ReviewerStrategy, error) {
+	switch name {
+	case ReviewerStrategyRandom:
+		return &randomReviewerStrategy{}, nil
+	case ReviewerStrategyLeastOpenReviews:
+		return &leastOpenReviewsStrategy{PRRepo: prRepo}, nil
+	default:
+		return nil, fmt.Errorf("[NewReviewerStrategy]: %w: %s", ErrorUnknownReviewerStrategy, name)
+	}
+}
+
+// стратегия выбирает случайных ревьюеров из кандидатов
+type randomReviewerStrategy struct{}
+
+func (rs *randomReviewerStrategy) SelectReviewers(ctx context.Context, candidates []string, count int) ([]string, error) {
+	shuffled := shuffleCandidates(candidates)
+
+	return shuffled[:min(count, len(shuffled))], nil
+}
+
+func (rs *randomReviewerStrategy) SelectReviewersByLoad(candidates []string, count int, load map[string]int) []string {
+	shuffled := shuffleCandidates(candidates)
+
+	return shuffled[:min(count, len(shuffled))]
+}
+
+// стратегия выбирает ревьюеров с наименьшим количеством OPEN pull request на ревью
+type leastOpenReviewsStrategy struct {
+	PRRepo repository.PRRepository
+}
+
+func (ls *leastOpenReviewsStrategy) SelectReviewers(ctx context.Context, candidates []string, count int) ([]string, error) {
+	if len(candidates) <= count {
+		return shuffleCandidates(candidates), nil
+	}
+
+	// получим количество открытых ревью у каждого кандидата
+	openReviews, err := ls.PRRepo.CountOpenReviewsByUsers(ctx, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("[LeastOpenReviewsStrategy | SelectReviewers]: %w", err)
+	}
+
+	return ls.SelectReviewersByLoad(candidates, count, openReviews), nil
+}
+
+func (ls *leastOpenReviewsStrategy) SelectReviewersByLoad(candidates []string, count int, load map[string]int) []string {
+	// перемешаем кандидатов, чтобы при равной загрузке выбор был случайным
+	shuffled := shuffleCandidates(candidates)
+
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return load[shuffled[i]] < load[shuffled[j]]
+	})
+
+	return shuffled[:min(count, len(shuffled))]
+}
+
+// вспомогательная функция возвращает перемешанную копию списка кандидатов
+func shuffleCandidates(candidates []string) []string {
+	shuffled := make([]string, len(candidates))
+	copy(shuffled, candidates)
+
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+)
+
+//go:generate mockgen -source=role_service.go -destination=../../mocks/role_service.go -package=mocks
+type RoleService interface {
+	/* метод выдает пользователю роль admin или team_lead команды, заменяя выданную ранее.
+	team_lead выдается только участнику этой команды. Принимает на вход модель enteties.GrantRole, возвращает модель enteties.UserRole*/
+	GrantRole(ctx context.Context, req *enteties.GrantRole) (*enteties.UserRole, error)
+
+	/* идемпотентный метод отзывает выданную роль, пользователь становится member. Принимает
+	на вход модель enteties.RevokeRole, возвращает модель enteties.UserRole*/
+	RevokeRole(ctx context.Context, req *enteties.RevokeRole) (*enteties.UserRole, error)
+
+	/* метод возвращает выданные роли. Принимает на вход название команды, при непустом
+	названии возвращаются только руководители этой команды. Возвращает модель enteties.UserRoles*/
+	ListRoles(ctx context.Context, teamName string) (*enteties.UserRoles, error)
+}
+
+type roleService struct {
+	TxManager  repository.TxManager
+	UserRepo   repository.UserRepository
+	TeamRepo   repository.TeamRepository
+	RoleRepo   repository.RoleRepository
+	OutboxRepo repository.OutboxRepository
+}
+
+func NewRoleService(txManager repository.TxManager, userRepo repository.UserRepository, teamRepo repository.TeamRepository,
+	roleRepo repository.RoleRepository, outboxRepo repository.OutboxRepository) *roleService {
+	return &roleService{
+		TxManager:  txManager,
+		UserRepo:   userRepo,
+		TeamRepo:   teamRepo,
+		RoleRepo:   roleRepo,
+		OutboxRepo: outboxRepo,
+	}
+}
+
+func (rs *roleService) GrantRole(ctx context.Context, req *enteties.GrantRole) (*enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleService.GrantRole")
+	defer span.End()
+
+	var role *enteties.UserRole
+
+	// роль и событие о ее выдаче фиксируются вместе
+	err := rs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		exists, err := rs.UserRepo.UserExists(ctx, req.UserID)
+		if err != nil {
+			return fmt.Errorf("[RoleService | GrantRole]: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("[RoleService | GrantRole]: %w", ErrorUserNotFound)
+		}
+
+		if req.Role == enteties.RoleTeamLead {
+			exists, err = rs.TeamRepo.TeamExists(ctx, req.TeamName)
+			if err != nil {
+				return fmt.Errorf("[RoleService | GrantRole]: %w", err)
+			}
+			if !exists {
+				return fmt.Errorf("[RoleService | GrantRole]: %w", ErrorTeamNotFound)
+			}
+
+			// руководителем можно назначить только участника команды
+			teamName, err := rs.UserRepo.GetUserTeamName(ctx, req.UserID)
+			if err != nil {
+				return fmt.Errorf("[RoleService | GrantRole]: %w", err)
+			}
+			if teamName != req.TeamName {
+				return fmt.Errorf("[RoleService | GrantRole]: %w", ErrorUserNotInTeam)
+			}
+		}
+
+		role, err = rs.RoleRepo.SetRole(ctx, &enteties.UserRole{
+			UserID:    req.UserID,
+			Role:      req.Role,
+			TeamName:  req.TeamName,
+			GrantedBy: ActorFromContext(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("[RoleService | GrantRole]: %w", err)
+		}
+
+		err = addOutboxEvent(ctx, rs.OutboxRepo, enteties.OutboxEventRoleGranted, role)
+		if err != nil {
+			return fmt.Errorf("[RoleService | GrantRole]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (rs *roleService) RevokeRole(ctx context.Context, req *enteties.RevokeRole) (*enteties.UserRole, error) {
+	ctx, span := tracing.Start(ctx, "RoleService.RevokeRole")
+	defer span.End()
+
+	role := &enteties.UserRole{UserID: req.UserID, Role: enteties.RoleMember}
+
+	err := rs.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		exists, err := rs.UserRepo.UserExists(ctx, req.UserID)
+		if err != nil {
+			return fmt.Errorf("[RoleService | RevokeRole]: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("[RoleService | RevokeRole]: %w", ErrorUserNotFound)
+		}
+
+		deleted, err := rs.RoleRepo.DeleteRole(ctx, req.UserID)
+		if err != nil {
+			return fmt.Errorf("[RoleService | RevokeRole]: %w", err)
+		}
+
+		// повторный отзыв состояние не меняет и событие не пишет
+		if !deleted {
+			return nil
+		}
+
+		err = addOutboxEvent(ctx, rs.OutboxRepo, enteties.OutboxEventRoleRevoked, role)
+		if err != nil {
+			return fmt.Errorf("[RoleService | RevokeRole]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// вспомогательная функция отзывает роль team_lead у пользователей, которые покинули команду или
+// деактивированы, и пишет событие об отзыве каждой роли. Вызывается в транзакции изменения состава
+func revokeTeamLeadRoles(ctx context.Context, roleRepo repository.RoleRepository, outboxRepo repository.OutboxRepository,
+	usersID []string) error {
+	revoked, err := roleRepo.DeleteTeamLeadRoles(ctx, usersID)
+	if err != nil {
+		return fmt.Errorf("[revokeTeamLeadRoles]: %w", err)
+	}
+
+	for _, userID := range revoked {
+		err = addOutboxEvent(ctx, outboxRepo, enteties.OutboxEventRoleRevoked,
+			&enteties.UserRole{UserID: userID, Role: enteties.RoleMember})
+		if err != nil {
+			return fmt.Errorf("[revokeTeamLeadRoles]: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (rs *roleService) ListRoles(ctx context.Context, teamName string) (*enteties.UserRoles, error) {
+	ctx, span := tracing.Start(ctx, "RoleService.ListRoles")
+	defer span.End()
+
+	if teamName != "" {
+		exists, err := rs.TeamRepo.TeamExists(ctx, teamName)
+		if err != nil {
+			return nil, fmt.Errorf("[RoleService | ListRoles]: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("[RoleService | ListRoles]: %w", ErrorTeamNotFound)
+		}
+	}
+
+	roles, err := rs.RoleRepo.ListRoles(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("[RoleService | ListRoles]: %w", err)
+	}
+
+	return &enteties.UserRoles{Roles: roles}, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleService_GrantTeamLead(t *testing.T) {
+	ctx := WithSubject(context.Background(), Subject{Admin: true})
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2")
+	createTestTeam(t, s, "frontend", "f1")
+
+	// руководителем можно назначить только участника команды
+	_, err := s.role.GrantRole(ctx, &enteties.GrantRole{UserID: "f1", Role: enteties.RoleTeamLead, TeamName: "backend"})
+	require.ErrorIs(t, err, ErrorUserNotInTeam)
+
+	_, err = s.team.RemoveMembers(ctx, &enteties.RemoveTeamMembers{TeamName: "backend", UsersID: []string{"u2"}})
+	require.NoError(t, err)
+	_, err = s.role.GrantRole(ctx, &enteties.GrantRole{UserID: "u2", Role: enteties.RoleTeamLead, TeamName: "backend"})
+	require.ErrorIs(t, err, ErrorUserNotInTeam)
+
+	lead, err := s.role.GrantRole(ctx, &enteties.GrantRole{UserID: "u1", Role: enteties.RoleTeamLead, TeamName: "backend"})
+	require.NoError(t, err)
+	assert.Equal(t, "backend", lead.TeamName)
+
+	roles, err := s.role.ListRoles(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, roles.Roles, 1)
+	assert.Equal(t, "u1", roles.Roles[0].UserID)
+}
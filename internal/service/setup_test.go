@@ -0,0 +1,170 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// сервисы теста поверх in-memory хранилища или Postgres
+type testServices struct {
+	user    UserService
+	team    TeamService
+	pr      PRService
+	webhook *webhookService
+	role    RoleService
+	outbox  repository.OutboxRepository
+	metrics *recordedMetrics
+}
+
+// реализация Metrics, которая запоминает учтенные события
+type recordedMetrics struct {
+	mu          sync.Mutex
+	created     int
+	assigned    int
+	reassigned  map[string]int
+	noCandidate int
+	merged      int
+}
+
+func (rm *recordedMetrics) PRCreated() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.created++
+}
+
+func (rm *recordedMetrics) ReviewersAssigned(count int) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.assigned += count
+}
+
+func (rm *recordedMetrics) ReviewersReassigned(reason string, count int) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.reassigned[reason] += count
+}
+
+func (rm *recordedMetrics) NoCandidate() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.noCandidate++
+}
+
+func (rm *recordedMetrics) PRMerged() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.merged++
+}
+
+// сервисы поверх in-memory хранилища, без базы данных
+func newMemoryServices(t *testing.T) *testServices {
+	return newMemoryServicesWithApprovals(t, 0)
+}
+
+// сервисы поверх in-memory хранилища, мердж требует requiredApprovals одобрений
+func newMemoryServicesWithApprovals(t *testing.T, requiredApprovals int) *testServices {
+	storage := repository.NewMemoryStorage()
+
+	return newTestServices(t, testRepositories{
+		txManager: storage,
+		user:      repository.NewUserMemoryRepository(storage),
+		team:      repository.NewTeamMemoryRepository(storage),
+		pr:        repository.NewPRMemoryRepository(storage),
+		outbox:    repository.NewOutboxMemoryRepository(storage),
+		role:      repository.NewRoleMemoryRepository(storage),
+		webhook:   repository.NewWebhookMemoryRepository(storage),
+	}, requiredApprovals)
+}
+
+// репозитории хранилища, поверх которых собираются сервисы теста
+type testRepositories struct {
+	txManager repository.TxManager
+	user      repository.UserRepository
+	team      repository.TeamRepository
+	pr        repository.PRRepository
+	outbox    repository.OutboxRepository
+	role      repository.RoleRepository
+	webhook   repository.WebhookRepository
+}
+
+func newTestServices(t *testing.T, repos testRepositories, requiredApprovals int) *testServices {
+	policy := NewRolePolicy(repos.role)
+
+	metrics := &recordedMetrics{reassigned: make(map[string]int)}
+
+	strategy, err := NewReviewerStrategy(ReviewerStrategyLeastOpenReviews, repos.pr)
+	require.NoError(t, err)
+
+	webhook := NewWebhookService(repos.team, repos.webhook, WebhookOptions{
+		MaxAttempts: 3,
+		Timeout:     time.Second,
+	})
+	t.Cleanup(func() {
+		_ = webhook.Shutdown(context.Background())
+	})
+
+	return &testServices{
+		user: NewUserService(repos.txManager, repos.user, repos.pr, repos.outbox, repos.role, policy),
+		team: NewTeamService(repos.txManager, repos.user, repos.team, repos.pr, repos.outbox, repos.role, policy,
+			strategy, 2, metrics),
+		pr: NewPRService(repos.txManager, repos.user, repos.team, repos.pr, repos.outbox, policy, strategy, 2,
+			requiredApprovals, metrics),
+		webhook: webhook,
+		role:    NewRoleService(repos.txManager, repos.user, repos.team, repos.role, repos.outbox),
+		outbox:  repos.outbox,
+		metrics: metrics,
+	}
+}
+
+// доставляет события webhook из outbox так же, как диспетчер: неудачный пакет повторяется без
+// задержки, пока подписчики не примут события или не будут исчерпаны попытки
+func dispatchWebhooks(t *testing.T, s *testServices) {
+	ctx := context.Background()
+
+	for range 10 {
+		events, err := s.outbox.ClaimEvents(ctx, 100, time.Minute)
+		require.NoError(t, err)
+
+		if len(events) == 0 {
+			return
+		}
+
+		ids := make([]int64, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.EventID)
+		}
+
+		err = s.webhook.Publish(ctx, events)
+		if err != nil {
+			require.NoError(t, s.outbox.MarkFailed(ctx, ids, 0, err.Error()))
+			continue
+		}
+
+		require.NoError(t, s.outbox.MarkDelivered(ctx, ids))
+	}
+
+	t.Fatal("webhook events are not dispatched")
+}
+
+func createTestTeam(t *testing.T, s *testServices, teamName string, usersID ...string) {
+	members := make([]enteties.TeamMember, 0, len(usersID))
+	for _, userID := range usersID {
+		members = append(members, enteties.TeamMember{
+			UserID:   userID,
+			UserName: "name_" + userID,
+			IsActive: true,
+		})
+	}
+
+	_, err := s.team.CreateTeam(context.Background(), &enteties.Team{
+		TeamName: teamName,
+		Members:  members,
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"avito_intern/internal/tracing"
+	"context"
+	"fmt"
+)
+
+//go:generate mockgen -source=stats_service.go -destination=../../mocks/stats_service.go -package=mocks
+type StatsService interface {
+	/* метод возвращает статистику ревью по пользователям: количество назначений, OPEN и MERGED
+	ревью и переназначений с пользователя. Принимает на вход модель enteties.ReviewerStatsFilter
+	(все фильтры необязательные), возвращает модель enteties.ReviewerStats*/
+	GetReviewerStats(ctx context.Context, filter *enteties.ReviewerStatsFilter) (*enteties.ReviewerStats, error)
+
+	/* метод возвращает количество pull request по статусам, а так же медиану и 90-й перцентиль
+	времени до мерджа с группировкой по команде, автору или неделе. Принимает на вход модель
+	enteties.PullRequestStatsFilter, возвращает модель enteties.PullRequestStats*/
+	GetPullRequestStats(ctx context.Context, filter *enteties.PullRequestStatsFilter) (*enteties.PullRequestStats, error)
+}
+
+type statsService struct {
+	TeamRepo  repository.TeamRepository
+	StatsRepo repository.StatsRepository
+}
+
+func NewStatsService(teamRepo repository.TeamRepository, statsRepo repository.StatsRepository) *statsService {
+	return &statsService{
+		TeamRepo:  teamRepo,
+		StatsRepo: statsRepo,
+	}
+}
+
+func (ss *statsService) GetReviewerStats(ctx context.Context, filter *enteties.ReviewerStatsFilter) (*enteties.ReviewerStats, error) {
+	ctx, span := tracing.Start(ctx, "StatsService.GetReviewerStats")
+	defer span.End()
+
+	err := ss.checkTeamFilter(ctx, filter.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsService | GetReviewerStats]: %w", err)
+	}
+
+	reviewers, err := ss.StatsRepo.GetReviewerStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsService | GetReviewerStats]: %w", err)
+	}
+
+	return &enteties.ReviewerStats{
+		ReviewerStatsFilter: *filter,
+		Reviewers:           reviewers,
+	}, nil
+}
+
+func (ss *statsService) GetPullRequestStats(ctx context.Context, filter *enteties.PullRequestStatsFilter) (*enteties.PullRequestStats, error) {
+	ctx, span := tracing.Start(ctx, "StatsService.GetPullRequestStats")
+	defer span.End()
+
+	err := ss.checkTeamFilter(ctx, filter.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsService | GetPullRequestStats]: %w", err)
+	}
+
+	groups, err := ss.StatsRepo.GetPullRequestStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[StatsService | GetPullRequestStats]: %w", err)
+	}
+
+	// все статусы выводим явно, даже если pull request в таком статусе нет
+	statusCounts := make(map[enteties.PullRequestStatus]int, len(enteties.PullRequestStatuses))
+	for _, status := range enteties.PullRequestStatuses {
+		statusCounts[status] = 0
+	}
+
+	for _, group := range groups {
+		for _, status := range enteties.PullRequestStatuses {
+			if _, ok := group.StatusCounts[status]; !ok {
+				group.StatusCounts[status] = 0
+			}
+		}
+
+		for status, count := range group.StatusCounts {
+			statusCounts[status] += count
+		}
+	}
+
+	return &enteties.PullRequestStats{
+		PullRequestStatsFilter: *filter,
+		StatusCounts:           statusCounts,
+		Groups:                 groups,
+	}, nil
+}
+
+// вспомогательный метод проверяет существование команды, если задан фильтр по команде
+func (ss *statsService) checkTeamFilter(ctx context.Context, teamName string) error {
+	if teamName == "" {
+		return nil
+	}
+
+	exists, err := ss.TeamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("[StatsService | checkTeamFilter]: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("[StatsService | checkTeamFilter]: %w", ErrorTeamNotFound)
+	}
+
+	return nil
+}
@@ -3,18 +3,34 @@ package service
 import (
 	"avito_intern/internal/enteties"
 	"avito_intern/internal/repository"
+	"avito_intern/internal/tracing"
 	"context"
 	"errors"
 	"fmt"
-
-	"github.com/jackc/pgx/v5"
 )
 
 var (
-	ErrorTeamExists   = errors.New("team already exists")
-	ErrorTeamNotFound = errors.New("team not found")
+	ErrorTeamExists          = errors.New("team already exists")
+	ErrorTeamNotFound        = errors.New("team not found")
+	ErrorInvalidTeamSettings = errors.New("min reviewers greater than max reviewers")
+	ErrorUserNotInTeam       = errors.New("user is not a member of the team")
+	ErrorTeamHasOpenPRs      = errors.New("team members have open pull requests or reviews")
 )
 
+// TeamHasOpenPRsError возвращается DeleteTeam в режиме refuse и перечисляет OPEN pull request,
+// которые мешают удалению. Проверяется через errors.Is(err, ErrorTeamHasOpenPRs)
+type TeamHasOpenPRsError struct {
+	PRs []string
+}
+
+func (e *TeamHasOpenPRsError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrorTeamHasOpenPRs, e.PRs)
+}
+
+func (e *TeamHasOpenPRsError) Unwrap() error {
+	return ErrorTeamHasOpenPRs
+}
+
 //go:generate mockgen -source=team_service.go -destination=../../mocks/team_service.go -package=mocks
 type TeamService interface {
 	/* метод создает запись о новой команде в таблице teams, а так же создает записи
@@ -25,23 +41,83 @@ type TeamService interface {
 	/* метод возвращает инфо о команде в виде модели enteties.Team.
 	Принимает на вход название команды */
 	GetTeam(ctx context.Context, teamName string) (*enteties.Team, error)
+
+	/* метод возвращает настройки команды в виде модели enteties.TeamSettings. Если настройки
+	не заданы, возвращаются настройки по умолчанию. Принимает на вход название команды */
+	GetTeamSettings(ctx context.Context, teamName string) (*enteties.TeamSettings, error)
+
+	/* метод изменяет настройки команды. Принимает на вход модель enteties.UpdateTeamSettings,
+	возвращает сохраненные настройки в виде модели enteties.TeamSettings */
+	UpdateTeamSettings(ctx context.Context, req *enteties.UpdateTeamSettings) (*enteties.TeamSettings, error)
+
+	/* метод добавляет новых пользователей в существующую команду. Принимает на вход модель
+	enteties.AddTeamMembers, возвращает модель команды enteties.Team с новым составом */
+	AddMembers(ctx context.Context, req *enteties.AddTeamMembers) (*enteties.Team, error)
+
+	/* метод исключает пользователей из команды, их OPEN ревью переназначаются на оставшихся
+	участников, роль team_lead отзывается. Принимает на вход модель enteties.RemoveTeamMembers, возвращает модель
+	enteties.RemoveTeamMembersResponce */
+	RemoveMembers(ctx context.Context, req *enteties.RemoveTeamMembers) (*enteties.RemoveTeamMembersResponce, error)
+
+	/* метод переводит пользователя в другую команду, его OPEN ревью переназначаются на
+	участников прежней команды, роль team_lead отзывается. Принимает на вход модель enteties.MoveTeamMember, возвращает
+	модель enteties.MoveTeamMemberResponce */
+	MoveMember(ctx context.Context, req *enteties.MoveTeamMember) (*enteties.MoveTeamMemberResponce, error)
+
+	/* метод удаляет команду. Участники остаются в базе без команды, чтобы сохранить историю
+	pull request. В режиме refuse (по умолчанию) удаление запрещено, если у участников есть
+	OPEN pull request или OPEN ревью, в режиме reassign OPEN ревью переназначаются на
+	пользователей других команд, в режиме archive OPEN pull request участников переводятся
+	в ARCHIVED, а участники снимаются с остальных ревью. Принимает на вход модель
+	enteties.DeleteTeam, возвращает модель enteties.DeleteTeamResponce */
+	DeleteTeam(ctx context.Context, req *enteties.DeleteTeam) (*enteties.DeleteTeamResponce, error)
+
+	/* метод деактивирует список пользователей в одной транзакции и переназначает их OPEN ревью
+	на активных сокомандников по тем же правилам, что и ReassignPR, роль team_lead отзывается.
+	Принимает на вход модель enteties.DeactivateUsers, возвращает отчет по каждому pull request
+	в виде модели enteties.DeactivateUsersResponce */
+	DeactivateUsers(ctx context.Context, req *enteties.DeactivateUsers) (*enteties.DeactivateUsersResponce, error)
 }
 
 type teamService struct {
-	Db       *pgx.Conn
-	UserRepo repository.UserRepository
-	TeamRepo repository.TeamRepository
+	TxManager             repository.TxManager
+	UserRepo              repository.UserRepository
+	TeamRepo              repository.TeamRepository
+	PRRepo                repository.PRRepository
+	OutboxRepo            repository.OutboxRepository
+	RoleRepo              repository.RoleRepository
+	Policy                Policy
+	Strategy              ReviewerStrategy
+	DefaultReviewersCount int
+	Metrics               Metrics
 }
 
-func NewTeamService(db *pgx.Conn, userRepo repository.UserRepository, teamRepo repository.TeamRepository) *teamService {
+func NewTeamService(txManager repository.TxManager, userRepo repository.UserRepository, teamRepo repository.TeamRepository,
+	prRepo repository.PRRepository, outboxRepo repository.OutboxRepository, roleRepo repository.RoleRepository,
+	policy Policy, strategy ReviewerStrategy, defaultReviewersCount int, metrics Metrics) *teamService {
 	return &teamService{
-		Db:       db,
-		UserRepo: userRepo,
-		TeamRepo: teamRepo,
+		TxManager:             txManager,
+		UserRepo:              userRepo,
+		TeamRepo:              teamRepo,
+		PRRepo:                prRepo,
+		OutboxRepo:            outboxRepo,
+		RoleRepo:              roleRepo,
+		Policy:                policy,
+		Strategy:              strategy,
+		DefaultReviewersCount: defaultReviewersCount,
+		Metrics:               metricsOrNop(metrics),
 	}
 }
 
 func (ts *teamService) CreateTeam(ctx context.Context, team *enteties.Team) (*enteties.Team, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.CreateTeam")
+	defer span.End()
+
+	// проверим права инициатора на создание команды
+	err := ts.Policy.Authorize(ctx, ActionCreateTeam, Resource{TeamName: team.TeamName})
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+	}
 
 	// проверим, существует ли уже команда с таким именем
 	exists, err := ts.TeamRepo.TeamExists(ctx, team.TeamName)
@@ -53,94 +129,684 @@ func (ts *teamService) CreateTeam(ctx context.Context, team *enteties.Team) (*en
 		return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", ErrorTeamExists)
 	}
 
-	tx, err := ts.Db.Begin(ctx)
+	// репозитории получают транзакцию через контекст
+	err = ts.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// созадим запись в таблице team
+		_, err = ts.TeamRepo.CreateTeam(ctx, team.TeamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+		}
+
+		// создадим записи о пользователях команды
+		err = ts.createMembers(ctx, team.TeamName, team.Members)
+		if err != nil {
+			return fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+		}
+
+		err = addOutboxEvent(ctx, ts.OutboxRepo, enteties.OutboxEventTeamCreated, team)
+		if err != nil {
+			return fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback(ctx)
 
-	// добавим tx в контекст
-	ctx = repository.WithTx(ctx, tx)
+	return team, nil
+}
+
+func (ts *teamService) GetTeam(ctx context.Context, teamName string) (*enteties.Team, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.GetTeam")
+	defer span.End()
 
-	// созадим запись в таблице team
-	_, err = ts.TeamRepo.CreateTeam(ctx, team.TeamName)
+	// проверим, что инициатор может управлять командой
+	err := ts.Policy.Authorize(ctx, ActionManageTeam, Resource{TeamName: teamName})
 	if err != nil {
-		return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", err)
+	}
+
+	// проверим существование команды
+	exists, err := ts.TeamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", ErrorTeamNotFound)
+	}
+
+	var teamMembersPtrs []*enteties.TeamMember
+
+	// репозитории получают транзакцию через контекст
+	err = ts.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// получим всех teamMembers по teamName
+		teamMembersPtrs, err = ts.UserRepo.GetTeamMembersByTeamName(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService | GetTeam]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	teamMembers := make([]enteties.TeamMember, len(teamMembersPtrs))
+	for ind, tm := range teamMembersPtrs {
+		teamMembers[ind] = *tm
+	}
+
+	return &enteties.Team{
+		TeamName: teamName,
+		Members:  teamMembers,
+	}, nil
+}
+
+func (ts *teamService) GetTeamSettings(ctx context.Context, teamName string) (*enteties.TeamSettings, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.GetTeamSettings")
+	defer span.End()
+
+	// проверим, что инициатор может управлять командой
+	err := ts.Policy.Authorize(ctx, ActionManageTeam, Resource{TeamName: teamName})
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | GetTeamSettings]: %w", err)
+	}
+
+	// проверим существование команды
+	exists, err := ts.TeamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | GetTeamSettings]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | GetTeamSettings]: %w", ErrorTeamNotFound)
+	}
+
+	settings, err := getTeamSettingsOrDefault(ctx, ts.TeamRepo, teamName, ts.DefaultReviewersCount)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | GetTeamSettings]: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (ts *teamService) UpdateTeamSettings(ctx context.Context, req *enteties.UpdateTeamSettings) (*enteties.TeamSettings, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.UpdateTeamSettings")
+	defer span.End()
+
+	// проверим, что инициатор может управлять командой
+	err := ts.Policy.Authorize(ctx, ActionManageTeam, Resource{TeamName: req.TeamName})
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | UpdateTeamSettings]: %w", err)
 	}
 
-	for _, tm := range team.Members {
+	// минимальное количество ревьюеров не может быть больше максимального
+	if *req.MinReviewers > *req.MaxReviewers {
+		return nil, fmt.Errorf("[TeamService | UpdateTeamSettings]: %w", ErrorInvalidTeamSettings)
+	}
+
+	// проверим существование команды
+	exists, err := ts.TeamRepo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | UpdateTeamSettings]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | UpdateTeamSettings]: %w", ErrorTeamNotFound)
+	}
+
+	settings, err := ts.TeamRepo.SetTeamSettings(ctx, &enteties.TeamSettings{
+		TeamName:     req.TeamName,
+		MinReviewers: *req.MinReviewers,
+		MaxReviewers: *req.MaxReviewers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | UpdateTeamSettings]: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (ts *teamService) AddMembers(ctx context.Context, req *enteties.AddTeamMembers) (*enteties.Team, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.AddMembers")
+	defer span.End()
+
+	// проверим, что инициатор может управлять командой
+	err := ts.Policy.Authorize(ctx, ActionManageTeam, Resource{TeamName: req.TeamName})
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | AddMembers]: %w", err)
+	}
+
+	// проверим существование команды
+	exists, err := ts.TeamRepo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | AddMembers]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | AddMembers]: %w", ErrorTeamNotFound)
+	}
+
+	var team *enteties.Team
+
+	// репозитории получают транзакцию через контекст
+	err = ts.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err = ts.createMembers(ctx, req.TeamName, req.Members)
+		if err != nil {
+			return fmt.Errorf("[TeamService | AddMembers]: %w", err)
+		}
+
+		team, err = ts.getTeamMembers(ctx, req.TeamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService | AddMembers]: %w", err)
+		}
+
+		err = addOutboxEvent(ctx, ts.OutboxRepo, enteties.OutboxEventTeamMembersAdded, team)
+		if err != nil {
+			return fmt.Errorf("[TeamService | AddMembers]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+func (ts *teamService) RemoveMembers(ctx context.Context, req *enteties.RemoveTeamMembers) (*enteties.RemoveTeamMembersResponce, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.RemoveMembers")
+	defer span.End()
+
+	// проверим, что инициатор может управлять командой
+	err := ts.Policy.Authorize(ctx, ActionManageTeam, Resource{TeamName: req.TeamName})
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+	}
+
+	// проверим существование команды
+	exists, err := ts.TeamRepo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | RemoveMembers]: %w", ErrorTeamNotFound)
+	}
+
+	var result *enteties.RemoveTeamMembersResponce
+
+	// репозитории получают транзакцию через контекст
+	err = ts.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, userID := range req.UsersID {
+
+			// проверим, что пользователь состоит в команде
+			err = ts.checkUserInTeam(ctx, userID, req.TeamName)
+			if err != nil {
+				return fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+			}
+
+			err = ts.UserRepo.RemoveUserFromTeam(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+			}
+		}
+
+		// исключенный руководитель больше не управляет командой
+		err = revokeTeamLeadRoles(ctx, ts.RoleRepo, ts.OutboxRepo, req.UsersID)
+		if err != nil {
+			return fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+		}
+
+		// переназначим открытые ревью исключенных пользователей на оставшихся участников
+		teamMembers, err := ts.UserRepo.GetTeamMembersByTeamName(ctx, req.TeamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+		}
+
+		candidatesByUser := make(map[string][]*enteties.TeamMember, len(req.UsersID))
+		for _, userID := range req.UsersID {
+			candidatesByUser[userID] = teamMembers
+		}
+
+		reassigned, err := reassignOpenReviewsBatch(ctx, ts.PRRepo, ts.Strategy, candidatesByUser,
+			enteties.AssignmentReasonRemovedFromTeam)
+		if err != nil {
+			return fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+		}
+
+		team, err := ts.getTeamMembers(ctx, req.TeamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+		}
+
+		result = &enteties.RemoveTeamMembersResponce{
+			Team:       *team,
+			Reassigned: reassigned,
+		}
+
+		err = addOutboxEvent(ctx, ts.OutboxRepo, enteties.OutboxEventTeamMembersRemoved, result)
+		if err != nil {
+			return fmt.Errorf("[TeamService | RemoveMembers]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordReassigned(ts.Metrics, result.Reassigned, enteties.AssignmentReasonRemovedFromTeam)
+
+	return result, nil
+}
+
+func (ts *teamService) MoveMember(ctx context.Context, req *enteties.MoveTeamMember) (*enteties.MoveTeamMemberResponce, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.MoveMember")
+	defer span.End()
+
+	// проверим существование пользователя
+	exists, err := ts.UserRepo.UserExists(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | MoveMember]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | MoveMember]: %w", ErrorUserNotFound)
+	}
+
+	// проверим существование команды, в которую переводим
+	exists, err = ts.TeamRepo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | MoveMember]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | MoveMember]: %w", ErrorTeamNotFound)
+	}
+
+	var result *enteties.MoveTeamMemberResponce
+
+	// репозитории получают транзакцию через контекст
+	err = ts.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// найдем текущую команду пользователя
+		oldTeamName, err := ts.UserRepo.GetUserTeamName(ctx, req.UserID)
+		if err != nil {
+			return fmt.Errorf("[TeamService | MoveMember]: %w", err)
+		}
+
+		reassigned := make([]enteties.ReviewerReplacement, 0)
+
+		// если пользователь уже в этой команде, ничего не меняем
+		if oldTeamName != req.TeamName {
+			err = ts.UserRepo.SetUserTeam(ctx, req.UserID, req.TeamName)
+			if err != nil {
+				return fmt.Errorf("[TeamService | MoveMember]: %w", err)
+			}
+
+			// руководитель прежней команды не переносит роль в новую
+			err = revokeTeamLeadRoles(ctx, ts.RoleRepo, ts.OutboxRepo, []string{req.UserID})
+			if err != nil {
+				return fmt.Errorf("[TeamService | MoveMember]: %w", err)
+			}
+
+			// переназначим открытые ревью пользователя на участников прежней команды
+			if oldTeamName != "" {
+				teamMembers, err := ts.UserRepo.GetTeamMembersByTeamName(ctx, oldTeamName)
+				if err != nil {
+					return fmt.Errorf("[TeamService | MoveMember]: %w", err)
+				}
+
+				reassigned, err = reassignOpenReviews(ctx, ts.PRRepo, ts.Strategy, req.UserID, teamMembers,
+					enteties.AssignmentReasonMovedToTeam)
+				if err != nil {
+					return fmt.Errorf("[TeamService | MoveMember]: %w", err)
+				}
+			}
+		}
+
+		user, err := ts.UserRepo.GetUser(ctx, req.UserID)
+		if err != nil {
+			return fmt.Errorf("[TeamService | MoveMember]: %w", err)
+		}
+
+		result = &enteties.MoveTeamMemberResponce{
+			User:       *user,
+			Reassigned: reassigned,
+		}
+
+		// пользователь уже был в этой команде, состояние не изменилось
+		if oldTeamName == req.TeamName {
+			return nil
+		}
+
+		err = addOutboxEvent(ctx, ts.OutboxRepo, enteties.OutboxEventTeamMemberMoved, result)
+		if err != nil {
+			return fmt.Errorf("[TeamService | MoveMember]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordReassigned(ts.Metrics, result.Reassigned, enteties.AssignmentReasonMovedToTeam)
+
+	return result, nil
+}
+
+func (ts *teamService) DeleteTeam(ctx context.Context, req *enteties.DeleteTeam) (*enteties.DeleteTeamResponce, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.DeleteTeam")
+	defer span.End()
+
+	mode := req.Mode
+	if mode == "" {
+		mode = enteties.DeleteTeamModeRefuse
+	}
+
+	// проверим существование команды
+	exists, err := ts.TeamRepo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[TeamService | DeleteTeam]: %w", ErrorTeamNotFound)
+	}
+
+	var resp *enteties.DeleteTeamResponce
+
+	// репозитории получают транзакцию через контекст
+	err = ts.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		teamMembers, err := ts.UserRepo.GetTeamMembersByTeamName(ctx, req.TeamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+		}
+
+		// OPEN pull request, авторы которых состоят в команде
+		authoredPRs, err := ts.PRRepo.GetOpenPRsByTeamAuthors(ctx, req.TeamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+		}
+
+		resp = &enteties.DeleteTeamResponce{
+			TeamName:       req.TeamName,
+			Mode:           mode,
+			RemovedMembers: make([]string, 0, len(teamMembers)),
+			ArchivedPRs:    make([]string, 0),
+		}
+
+		// кандидаты на замену участников в ревью (в режиме archive участники снимаются без замены)
+		var candidates []*enteties.TeamMember
+
+		switch mode {
+		case enteties.DeleteTeamModeRefuse:
+			openPRs, err := ts.collectOpenPRs(ctx, teamMembers, authoredPRs)
+			if err != nil {
+				return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+			}
+
+			if len(openPRs) > 0 {
+				return fmt.Errorf("[TeamService | DeleteTeam]: %w", &TeamHasOpenPRsError{PRs: openPRs})
+			}
+		case enteties.DeleteTeamModeArchive:
+			for _, pr := range authoredPRs {
+				err = ts.PRRepo.SetPRStatus(ctx, pr.PullRequestID, enteties.PullRequestStatusArchived)
+				if err != nil {
+					return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+				}
+
+				resp.ArchivedPRs = append(resp.ArchivedPRs, pr.PullRequestID)
+			}
+		case enteties.DeleteTeamModeReassign:
+			candidates, err = ts.UserRepo.GetActiveUsersOutsideTeam(ctx, req.TeamName)
+			if err != nil {
+				return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+			}
+		}
+
+		// переназначим (или снимем) оставшиеся OPEN ревью участников
+		candidatesByUser := make(map[string][]*enteties.TeamMember, len(teamMembers))
+		for _, tm := range teamMembers {
+			candidatesByUser[tm.UserID] = candidates
+		}
+
+		resp.Reassigned, err = reassignOpenReviewsBatch(ctx, ts.PRRepo, ts.Strategy, candidatesByUser,
+			enteties.AssignmentReasonTeamDeleted)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+		}
+
+		for _, tm := range teamMembers {
+			// отвяжем участника от команды, чтобы каскадное удаление не затронуло его pull request
+			err = ts.UserRepo.RemoveUserFromTeam(ctx, tm.UserID)
+			if err != nil {
+				return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+			}
+
+			resp.RemovedMembers = append(resp.RemovedMembers, tm.UserID)
+		}
+
+		err = ts.TeamRepo.DeleteTeam(ctx, req.TeamName)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+		}
+
+		err = addOutboxEvent(ctx, ts.OutboxRepo, enteties.OutboxEventTeamDeleted, resp)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeleteTeam]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordReassigned(ts.Metrics, resp.Reassigned, enteties.AssignmentReasonTeamDeleted)
+
+	return resp, nil
+}
+
+func (ts *teamService) DeactivateUsers(ctx context.Context, req *enteties.DeactivateUsers) (*enteties.DeactivateUsersResponce, error) {
+	ctx, span := tracing.Start(ctx, "TeamService.DeactivateUsers")
+	defer span.End()
+
+	// проверим существование всех пользователей
+	users, err := ts.UserRepo.GetUsers(ctx, req.UsersID)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | DeactivateUsers]: %w", err)
+	}
+
+	found := make(map[string]*enteties.User, len(users))
+	for _, user := range users {
+		found[user.UserID] = user
+	}
+
+	for _, userID := range req.UsersID {
+		if _, ok := found[userID]; !ok {
+			return nil, fmt.Errorf("[TeamService | DeactivateUsers]: %w: %s", ErrorUserNotFound, userID)
+		}
+	}
+
+	var resp *enteties.DeactivateUsersResponce
+
+	// репозитории получают транзакцию через контекст
+	err = ts.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err = ts.UserRepo.SetUsersStatusBatch(ctx, req.UsersID, false)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeactivateUsers]: %w", err)
+		}
+
+		// деактивированный руководитель больше не управляет командой
+		err = revokeTeamLeadRoles(ctx, ts.RoleRepo, ts.OutboxRepo, req.UsersID)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeactivateUsers]: %w", err)
+		}
+
+		// участников каждой команды получим один раз (деактивированные уже не будут кандидатами)
+		membersByTeam := make(map[string][]*enteties.TeamMember)
+		candidatesByUser := make(map[string][]*enteties.TeamMember, len(users))
+		for _, user := range users {
+			teamMembers, ok := membersByTeam[user.TeamName]
+			if !ok && user.TeamName != "" {
+				teamMembers, err = ts.UserRepo.GetTeamMembersByTeamName(ctx, user.TeamName)
+				if err != nil {
+					return fmt.Errorf("[TeamService | DeactivateUsers]: %w", err)
+				}
+
+				membersByTeam[user.TeamName] = teamMembers
+			}
+
+			candidatesByUser[user.UserID] = teamMembers
+		}
+
+		replacements, err := reassignOpenReviewsBatch(ctx, ts.PRRepo, ts.Strategy, candidatesByUser,
+			enteties.AssignmentReasonUserDeactivated)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeactivateUsers]: %w", err)
+		}
+
+		reassigned, withoutCandidate := splitReplacements(replacements)
+
+		deactivated := make([]string, 0, len(users))
+		for _, user := range users {
+			deactivated = append(deactivated, user.UserID)
+		}
+
+		resp = &enteties.DeactivateUsersResponce{
+			DeactivatedUsers: deactivated,
+			Reassigned:       reassigned,
+			WithoutCandidate: withoutCandidate,
+		}
+
+		err = addOutboxEvent(ctx, ts.OutboxRepo, enteties.OutboxEventUsersDeactivated, resp)
+		if err != nil {
+			return fmt.Errorf("[TeamService | DeactivateUsers]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordReassigned(ts.Metrics, resp.Reassigned, enteties.AssignmentReasonUserDeactivated)
+
+	return resp, nil
+}
+
+// вспомогательный метод возвращает pull_request_id всех OPEN pull request, авторами или
+// ревьюерами которых являются участники команды
+func (ts *teamService) collectOpenPRs(ctx context.Context, teamMembers []*enteties.TeamMember,
+	authoredPRs []*enteties.PullRequest) ([]string, error) {
+
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+
+	add := func(prs []*enteties.PullRequest) {
+		for _, pr := range prs {
+			if !seen[pr.PullRequestID] {
+				seen[pr.PullRequestID] = true
+				result = append(result, pr.PullRequestID)
+			}
+		}
+	}
+
+	add(authoredPRs)
+
+	if len(teamMembers) == 0 {
+		return result, nil
+	}
+
+	usersID := make([]string, 0, len(teamMembers))
+	for _, tm := range teamMembers {
+		usersID = append(usersID, tm.UserID)
+	}
+
+	reviewPRs, err := ts.PRRepo.GetOpenPRsByReviewers(ctx, usersID)
+	if err != nil {
+		return nil, fmt.Errorf("[TeamService | collectOpenPRs]: %w", err)
+	}
+
+	add(reviewPRs)
+
+	return result, nil
+}
+
+// вспомогательный метод создает пользователей команды, проверяя уникальность user_id и username.
+// Должен вызываться внутри транзакции (tx в контексте)
+func (ts *teamService) createMembers(ctx context.Context, teamName string, members []enteties.TeamMember) error {
+	for _, tm := range members {
 		user := &enteties.User{
 			UserID:   tm.UserID,
 			UserName: tm.UserName,
-			TeamName: team.TeamName,
+			TeamName: teamName,
 			IsActive: tm.IsActive,
 		}
 
 		// проверим, существует ли пользователь с таким id
 		exists, err := ts.UserRepo.UserExists(ctx, user.UserID)
 		if err != nil {
-			return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+			return fmt.Errorf("[TeamService | createMembers]: %w", err)
 		}
 
 		if exists {
-			return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", ErrorUserAlreadyExists)
+			return fmt.Errorf("[TeamService | createMembers]: %w", ErrorUserAlreadyExists)
 		}
 
 		// проверим, существует ли пользователь с таким username
 		exists, err = ts.UserRepo.UserExistsByUsername(ctx, user.UserName)
 		if err != nil {
-			return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+			return fmt.Errorf("[TeamService | createMembers]: %w", err)
 		}
 
 		if exists {
-			return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", ErrorUserAlreadyExistsByUserName)
+			return fmt.Errorf("[TeamService | createMembers]: %w", ErrorUserAlreadyExistsByUserName)
 		}
 
 		// вставим пользователя в таблицу
 		_, err = ts.UserRepo.CreateUser(ctx, user)
 		if err != nil {
-			return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", err)
+			return fmt.Errorf("[TeamService | createMembers]: %w", err)
 		}
 	}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("[TeamService| CreateTeam]: %w", err)
-	}
-
-	return team, nil
+	return nil
 }
 
-func (ts *teamService) GetTeam(ctx context.Context, teamName string) (*enteties.Team, error) {
-
-	// проверим существование команды
-	exists, err := ts.TeamRepo.TeamExists(ctx, teamName)
+// вспомогательный метод проверяет, что пользователь существует и состоит в команде teamName
+func (ts *teamService) checkUserInTeam(ctx context.Context, userID, teamName string) error {
+	exists, err := ts.UserRepo.UserExists(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", err)
+		return fmt.Errorf("[TeamService | checkUserInTeam]: %w", err)
 	}
 
 	if !exists {
-		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", ErrorTeamNotFound)
+		return fmt.Errorf("[TeamService | checkUserInTeam]: %w", ErrorUserNotFound)
 	}
 
-	tx, err := ts.Db.Begin(ctx)
+	userTeamName, err := ts.UserRepo.GetUserTeamName(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", err)
+		return fmt.Errorf("[TeamService | checkUserInTeam]: %w", err)
 	}
-	defer tx.Rollback(ctx)
-
-	// добавим tx в контекст
-	ctx = repository.WithTx(ctx, tx)
 
-	// получим всех teamMembers по teamName
-	teamMembersPtrs, err := ts.UserRepo.GetTeamMembersByTeamName(ctx, teamName)
-	if err != nil {
-		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", err)
+	if userTeamName != teamName {
+		return fmt.Errorf("[TeamService | checkUserInTeam]: %w", ErrorUserNotInTeam)
 	}
 
-	err = tx.Commit(ctx)
+	return nil
+}
+
+// вспомогательный метод возвращает команду с текущим составом участников
+func (ts *teamService) getTeamMembers(ctx context.Context, teamName string) (*enteties.Team, error) {
+	teamMembersPtrs, err := ts.UserRepo.GetTeamMembersByTeamName(ctx, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("[TeamService | GetTeam]: %w", err)
+		return nil, fmt.Errorf("[TeamService | getTeamMembers]: %w", err)
 	}
 
 	teamMembers := make([]enteties.TeamMember, len(teamMembersPtrs))
@@ -153,3 +819,24 @@ func (ts *teamService) GetTeam(ctx context.Context, teamName string) (*enteties.
 		Members:  teamMembers,
 	}, nil
 }
+
+// вспомогательная функция возвращает настройки команды, а если они не заданы - настройки
+// по умолчанию (от 0 до defaultReviewersCount ревьюеров)
+func getTeamSettingsOrDefault(ctx context.Context, teamRepo repository.TeamRepository, teamName string,
+	defaultReviewersCount int) (*enteties.TeamSettings, error) {
+
+	settings, ok, err := teamRepo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("[getTeamSettingsOrDefault]: %w", err)
+	}
+
+	if !ok {
+		return &enteties.TeamSettings{
+			TeamName:     teamName,
+			MinReviewers: 0,
+			MaxReviewers: defaultReviewersCount,
+		}, nil
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamService_DeactivateUsers(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3")
+
+	pr, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+	// замены нет: единственный активный участник - автор
+	resp, err := s.team.DeactivateUsers(ctx, &enteties.DeactivateUsers{UsersID: []string{"u2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, resp.DeactivatedUsers)
+	assert.Empty(t, resp.Reassigned)
+	assert.Equal(t, []enteties.ReviewerReplacement{{PullRequestID: "pr1", OldUserID: "u2"}}, resp.WithoutCandidate)
+
+	team, err := s.team.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	for _, member := range team.Members {
+		assert.Equal(t, member.UserID != "u2", member.IsActive, member.UserID)
+	}
+
+	reviews, err := s.user.GetReviews(ctx, &enteties.UserReviewsFilter{UserID: "u2"})
+	require.NoError(t, err)
+	assert.Empty(t, reviews.PullRequests)
+}
+
+func TestTeamService_DeleteTeamRefuse(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3")
+	createTestTeam(t, s, "frontend", "f1", "f2", "f3")
+
+	for i, authorID := range []string{"u1", "u2", "f1"} {
+		_, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+			PullRequestID:   fmt.Sprintf("pr%d", i+1),
+			PullRequestName: "name",
+			AuthorID:        authorID,
+		})
+		require.NoError(t, err)
+	}
+
+	// ошибка перечисляет pull request команды, которые мешают удалению
+	_, err := s.team.DeleteTeam(ctx, &enteties.DeleteTeam{TeamName: "backend"})
+	require.ErrorIs(t, err, ErrorTeamHasOpenPRs)
+
+	var openPRsErr *TeamHasOpenPRsError
+	require.ErrorAs(t, err, &openPRsErr)
+	assert.ElementsMatch(t, []string{"pr1", "pr2"}, openPRsErr.PRs)
+
+	team, err := s.team.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 3)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"avito_intern/internal/tracing"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	s := newMemoryServices(t)
+
+	// вызовы вне трассировки запроса спанов не создают
+	createTestTeam(t, s, "backend", "u1", "u2")
+	require.Empty(t, recorder.Ended())
+
+	ctx, request := tracing.Tracer().Start(context.Background(), "POST /users/setIsActive")
+	_, err := s.user.SetIsActive(ctx, "u2", false)
+	require.NoError(t, err)
+	request.End()
+
+	spans := recorder.Ended()
+	require.NotEmpty(t, spans)
+
+	var serviceSpan sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		assert.Equal(t, request.SpanContext().TraceID(), span.SpanContext().TraceID(), span.Name())
+		if span.Name() == "UserService.SetIsActive" {
+			serviceSpan = span
+		}
+	}
+	require.NotNil(t, serviceSpan)
+	assert.Equal(t, request.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+
+	// методы репозиториев - потомки спана сервиса
+	repoSpans := 0
+	for _, span := range spans {
+		if strings.HasPrefix(span.Name(), "UserRepository.") || strings.HasPrefix(span.Name(), "OutboxRepository.") {
+			assert.Equal(t, serviceSpan.SpanContext().SpanID(), span.Parent().SpanID(), span.Name())
+			repoSpans++
+		}
+	}
+	assert.Positive(t, repoSpans)
+}
@@ -3,11 +3,10 @@ package service
 import (
 	"avito_intern/internal/enteties"
 	"avito_intern/internal/repository"
+	"avito_intern/internal/tracing"
 	"context"
 	"errors"
 	"fmt"
-
-	"github.com/jackc/pgx/v5"
 )
 
 var (
@@ -23,25 +22,40 @@ type UserService interface {
 	SetIsActive(ctx context.Context, userID string, status bool) (*enteties.User, error)
 
 	/* метод возвращает pull request' ы, где пользователь назачен ревьюером в формате
-	модели enteties.UserReviewers. Принимает на вход user_id*/
-	GetReviews(ctx context.Context, userID string) (*enteties.UserReviews, error)
+	модели enteties.UserReviewers. Без фильтра по статусу закрытые pull request не возвращаются,
+	черновики - только при IncludeDrafts. Pull request отдаются страницами, NextCursor в ответе
+	указывает на следующую страницу. Принимает на вход модель enteties.UserReviewsFilter*/
+	GetReviews(ctx context.Context, filter *enteties.UserReviewsFilter) (*enteties.UserReviews, error)
+
+	/* метод изменяет username пользователя. Принимает на вход user_id и новый username,
+	возвращает модель enteties.User*/
+	SetUsername(ctx context.Context, userID, userName string) (*enteties.User, error)
 }
 
 type userService struct {
-	Db       *pgx.Conn
-	UserRepo repository.UserRepository
-	PRRepo   repository.PRRepository
+	TxManager  repository.TxManager
+	UserRepo   repository.UserRepository
+	PRRepo     repository.PRRepository
+	OutboxRepo repository.OutboxRepository
+	RoleRepo   repository.RoleRepository
+	Policy     Policy
 }
 
-func NewUserService(db *pgx.Conn, userRepo repository.UserRepository, prRepo repository.PRRepository) *userService {
+func NewUserService(txManager repository.TxManager, userRepo repository.UserRepository, prRepo repository.PRRepository,
+	outboxRepo repository.OutboxRepository, roleRepo repository.RoleRepository, policy Policy) *userService {
 	return &userService{
-		Db:       db,
-		UserRepo: userRepo,
-		PRRepo:   prRepo,
+		TxManager:  txManager,
+		UserRepo:   userRepo,
+		PRRepo:     prRepo,
+		OutboxRepo: outboxRepo,
+		RoleRepo:   roleRepo,
+		Policy:     policy,
 	}
 }
 
 func (us *userService) SetIsActive(ctx context.Context, userID string, status bool) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.SetIsActive")
+	defer span.End()
 
 	// проверяем существование пользователя
 	exists, err := us.UserRepo.UserExists(ctx, userID)
@@ -53,16 +67,55 @@ func (us *userService) SetIsActive(ctx context.Context, userID string, status bo
 		return nil, fmt.Errorf("[UserService | setIsActive]: %w", ErrorUserNotFound)
 	}
 
-	// если пользователь есть, меняем статус
-	userResp, err := us.UserRepo.SetUserStatus(ctx, userID, status)
+	// руководитель может менять статус только участников своей команды
+	teamName, err := us.UserRepo.GetUserTeamName(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[UserService | setIsActive]: %w", err)
+	}
+
+	err = us.Policy.Authorize(ctx, ActionSetIsActive, Resource{TeamName: teamName, UserID: userID})
 	if err != nil {
 		return nil, fmt.Errorf("[UserService | setIsActive]: %w", err)
 	}
 
+	var userResp *enteties.User
+
+	// изменение статуса и событие с инициатором изменения фиксируются вместе
+	err = us.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// если пользователь есть, меняем статус
+		userResp, err = us.UserRepo.SetUserStatus(ctx, userID, status)
+		if err != nil {
+			return fmt.Errorf("[UserService | setIsActive]: %w", err)
+		}
+
+		// деактивированный руководитель больше не управляет командой
+		if !status {
+			err = revokeTeamLeadRoles(ctx, us.RoleRepo, us.OutboxRepo, []string{userID})
+			if err != nil {
+				return fmt.Errorf("[UserService | setIsActive]: %w", err)
+			}
+		}
+
+		err = addOutboxEvent(ctx, us.OutboxRepo, enteties.OutboxEventUserStatusChanged, userResp)
+		if err != nil {
+			return fmt.Errorf("[UserService | setIsActive]: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return userResp, nil
 }
 
-func (us *userService) GetReviews(ctx context.Context, userID string) (*enteties.UserReviews, error) {
+func (us *userService) GetReviews(ctx context.Context, filter *enteties.UserReviewsFilter) (*enteties.UserReviews, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetReviews")
+	defer span.End()
+
+	userID := filter.UserID
+
 	// проверяем существование пользователя
 	exists, err := us.UserRepo.UserExists(ctx, userID)
 	if err != nil {
@@ -73,34 +126,83 @@ func (us *userService) GetReviews(ctx context.Context, userID string) (*enteties
 		return nil, fmt.Errorf("[UserService | GetReviews]: %w", ErrorUserNotFound)
 	}
 
-	tx, err := us.Db.Begin(ctx)
+	after, err := decodeCursor(filter.Cursor)
 	if err != nil {
 		return nil, fmt.Errorf("[UserService | GetReviews]: %w", err)
 	}
-	defer tx.Rollback(ctx)
 
-	// добавим tx в контекст
-	ctx = repository.WithTx(ctx, tx)
+	limit := pageLimit(filter.Limit)
+
+	var result *enteties.UserReviews
+
+	// репозитории получают транзакцию через контекст
+	err = us.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// читаем на один pull request больше, чтобы понять, есть ли следующая страница
+		shortPRptrs, err := us.PRRepo.GetReviewsByUser(ctx, filter, after, limit+1)
+		if err != nil {
+			return fmt.Errorf("[UserService | GetReviews]: %w", err)
+		}
+
+		page, nextCursor := cutPage(shortPRptrs, limit)
+
+		shortPRs := make([]enteties.PullRequestShort, 0, len(page))
+		for _, pr := range page {
+			shortPRs = append(shortPRs, *pr)
+		}
 
-	shortPRptrs, err := us.PRRepo.GetAllPRByUserID(ctx, userID)
+		result = &enteties.UserReviews{
+			UserID:       userID,
+			PullRequests: shortPRs,
+			NextCursor:   nextCursor,
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("[UserService | GetReviews]: %w", err)
+		return nil, err
+	}
 
+	return result, nil
+}
+
+func (us *userService) SetUsername(ctx context.Context, userID, userName string) (*enteties.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.SetUsername")
+	defer span.End()
+
+	// проверяем существование пользователя
+	exists, err := us.UserRepo.UserExists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[UserService | SetUsername]: %w", err)
 	}
 
-	shortPRs := make([]enteties.PullRequestShort, len(shortPRptrs))
+	if !exists {
+		return nil, fmt.Errorf("[UserService | SetUsername]: %w", ErrorUserNotFound)
+	}
 
-	for ind, pr := range shortPRptrs {
-		shortPRs[ind] = *pr
+	user, err := us.UserRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[UserService | SetUsername]: %w", err)
 	}
 
-	err = tx.Commit(ctx)
+	// username не изменился
+	if user.UserName == userName {
+		return user, nil
+	}
+
+	// username должен быть уникальным
+	exists, err = us.UserRepo.UserExistsByUsername(ctx, userName)
 	if err != nil {
-		return nil, fmt.Errorf("[UserService | GetReviews]: %w", err)
+		return nil, fmt.Errorf("[UserService | SetUsername]: %w", err)
+	}
+
+	if exists {
+		return nil, fmt.Errorf("[UserService | SetUsername]: %w", ErrorUserAlreadyExistsByUserName)
 	}
 
-	return &enteties.UserReviews{
-		UserID:       userID,
-		PullRequests: shortPRs,
-	}, nil
+	userResp, err := us.UserRepo.SetUsername(ctx, userID, userName)
+	if err != nil {
+		return nil, fmt.Errorf("[UserService | SetUsername]: %w", err)
+	}
+
+	return userResp, nil
 }
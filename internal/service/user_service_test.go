@@ -0,0 +1,63 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_ReviewsPagination(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	// в команде два кандидата, поэтому оба назначаются на каждый pull request
+	createTestTeam(t, s, "backend", "u1", "u2", "u3")
+
+	for _, prID := range []string{"pr1", "pr2", "pr3", "pr4", "pr5"} {
+		_, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+			PullRequestID:   prID,
+			PullRequestName: "name",
+			AuthorID:        "u1",
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	_, err = s.pr.ClosePR(ctx, &enteties.ClosePullRequest{PullRequestID: "pr2"})
+	require.NoError(t, err)
+
+	// проходит все страницы и возвращает идентификаторы pull request по порядку
+	collect := func(filter enteties.UserReviewsFilter) []string {
+		result := make([]string, 0)
+		for {
+			page, err := s.user.GetReviews(ctx, &filter)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page.PullRequests), filter.Limit)
+
+			for _, pr := range page.PullRequests {
+				require.NotNil(t, pr.CreatedAt)
+				result = append(result, pr.PullRequestID)
+			}
+
+			if page.NextCursor == "" {
+				return result
+			}
+			filter.Cursor = page.NextCursor
+		}
+	}
+
+	// по умолчанию новые первыми, закрытые не возвращаются
+	assert.Equal(t, []string{"pr5", "pr4", "pr3", "pr1"}, collect(enteties.UserReviewsFilter{UserID: "u2", Limit: 2}))
+	assert.Equal(t, []string{"pr1", "pr3", "pr4", "pr5"}, collect(enteties.UserReviewsFilter{UserID: "u2", Limit: 3, Sort: enteties.SortOrderAsc}))
+
+	assert.Equal(t, []string{"pr2"}, collect(enteties.UserReviewsFilter{UserID: "u3", Limit: 1, Status: enteties.PullRequestStatusClosed}))
+	assert.Equal(t, []string{"pr1"}, collect(enteties.UserReviewsFilter{UserID: "u3", Limit: 1, Status: enteties.PullRequestStatusMerged}))
+	assert.Empty(t, collect(enteties.UserReviewsFilter{UserID: "u3", Limit: 1, AuthorID: "u2"}))
+
+	_, err = s.user.GetReviews(ctx, &enteties.UserReviewsFilter{UserID: "u2", Cursor: "broken"})
+	assert.ErrorIs(t, err, ErrorInvalidCursor)
+}
@@ -0,0 +1,480 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"avito_intern/internal/repository"
+	"avito_intern/internal/tracing"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorWebhookNotFound      = errors.New("webhook subscription not found")
+	ErrorWebhookServiceClosed = errors.New("webhook service is shut down")
+)
+
+// заголовки запроса с событием: тип и id события и HMAC-SHA256 подпись тела в виде sha256=<hex>
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookEventIDHeader   = "X-Webhook-ID"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// размер журнала доставок в ответе по умолчанию
+const defaultDeliveriesLimit = 50
+
+//go:generate mockgen -source=webhook_service.go -destination=../../mocks/webhook_service.go -package=mocks
+type WebhookService interface {
+	/* метод создает подписку команды на события pull request. Принимает на вход модель
+	enteties.CreateWebhookSubscription, возвращает модель enteties.WebhookSubscription*/
+	CreateSubscription(ctx context.Context, req *enteties.CreateWebhookSubscription) (*enteties.WebhookSubscription, error)
+
+	/* метод удаляет подписку вместе с журналом доставок. Принимает на вход модель
+	enteties.DeleteWebhookSubscription*/
+	DeleteSubscription(ctx context.Context, req *enteties.DeleteWebhookSubscription) error
+
+	/* метод возвращает подписки команды. Принимает на вход название команды, возвращает
+	модель enteties.TeamWebhooks*/
+	GetSubscriptions(ctx context.Context, teamName string) (*enteties.TeamWebhooks, error)
+
+	/* метод возвращает последние попытки доставки событий подписчику, новые первыми.
+	Принимает на вход id подписки и количество записей (0 - по умолчанию), возвращает модель
+	enteties.WebhookDeliveries*/
+	GetDeliveries(ctx context.Context, subscriptionID int64, limit int) (*enteties.WebhookDeliveries, error)
+
+	/* метод доставляет подписчикам события webhook из outbox, события других типов пропускает.
+	Вызывается диспетчером outbox как получатель событий: при ошибке пакет отправляется повторно*/
+	Publish(ctx context.Context, events []enteties.OutboxEvent) error
+
+	/* метод перестает принимать новые пакеты и дожидается завершения начатых доставок. Если ctx
+	завершится раньше, начатые доставки прерываются, их события повторит outbox*/
+	Shutdown(ctx context.Context) error
+}
+
+// WebhookOptions настройки доставки событий: количество попыток (повторы выполняет диспетчер
+// outbox с задержкой OUTBOX_RETRY_*) и таймаут запроса
+type WebhookOptions struct {
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+type webhookService struct {
+	TeamRepo    repository.TeamRepository
+	WebhookRepo repository.WebhookRepository
+	Client      *http.Client
+	Options     WebhookOptions
+
+	// контекст доставок отменяется, если остановка сервиса не дождалась их завершения
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// closed и wg.Add меняются под mu, поэтому после закрытия Shutdown не ждет новых доставок
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewWebhookService(teamRepo repository.TeamRepository, webhookRepo repository.WebhookRepository,
+	options WebhookOptions) *webhookService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+
+	return &webhookService{
+		TeamRepo:    teamRepo,
+		WebhookRepo: webhookRepo,
+		Client:      &http.Client{Timeout: options.Timeout},
+		Options:     options,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (ws *webhookService) CreateSubscription(ctx context.Context, req *enteties.CreateWebhookSubscription) (*enteties.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateSubscription")
+	defer span.End()
+
+	// проверим существование команды
+	exists, err := ws.TeamRepo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookService | CreateSubscription]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[WebhookService | CreateSubscription]: %w", ErrorTeamNotFound)
+	}
+
+	// повторяющиеся типы событий сохраняются один раз
+	eventTypes := make([]enteties.WebhookEventType, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	subscription, err := ws.WebhookRepo.CreateSubscription(ctx, &enteties.CreateWebhookSubscription{
+		TeamName:   req.TeamName,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookService | CreateSubscription]: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (ws *webhookService) DeleteSubscription(ctx context.Context, req *enteties.DeleteWebhookSubscription) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteSubscription")
+	defer span.End()
+
+	exists, err := ws.WebhookRepo.SubscriptionExists(ctx, req.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("[WebhookService | DeleteSubscription]: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("[WebhookService | DeleteSubscription]: %w", ErrorWebhookNotFound)
+	}
+
+	err = ws.WebhookRepo.DeleteSubscription(ctx, req.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("[WebhookService | DeleteSubscription]: %w", err)
+	}
+
+	return nil
+}
+
+func (ws *webhookService) GetSubscriptions(ctx context.Context, teamName string) (*enteties.TeamWebhooks, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetSubscriptions")
+	defer span.End()
+
+	// проверим существование команды
+	exists, err := ws.TeamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookService | GetSubscriptions]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[WebhookService | GetSubscriptions]: %w", ErrorTeamNotFound)
+	}
+
+	subscriptions, err := ws.WebhookRepo.GetTeamSubscriptions(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookService | GetSubscriptions]: %w", err)
+	}
+
+	return &enteties.TeamWebhooks{
+		TeamName:      teamName,
+		Subscriptions: subscriptions,
+	}, nil
+}
+
+func (ws *webhookService) GetDeliveries(ctx context.Context, subscriptionID int64, limit int) (*enteties.WebhookDeliveries, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	exists, err := ws.WebhookRepo.SubscriptionExists(ctx, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookService | GetDeliveries]: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("[WebhookService | GetDeliveries]: %w", ErrorWebhookNotFound)
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	deliveries, err := ws.WebhookRepo.GetDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[WebhookService | GetDeliveries]: %w", err)
+	}
+
+	return &enteties.WebhookDeliveries{
+		SubscriptionID: subscriptionID,
+		Deliveries:     deliveries,
+	}, nil
+}
+
+// Publish доставляет события webhook.event подписчикам команд. Каждый подписчик получает одну
+// попытку за вызов, подписчики, принявшие событие в прошлых попытках, пропускаются. Если доставка
+// не удалась и попытки не исчерпаны, возвращается ошибка, и диспетчер outbox повторит пакет
+func (ws *webhookService) Publish(ctx context.Context, events []enteties.OutboxEvent) error {
+	ws.mu.Lock()
+	if ws.closed {
+		ws.mu.Unlock()
+		return fmt.Errorf("[WebhookService | Publish]: %w", ErrorWebhookServiceClosed)
+	}
+	ws.wg.Add(1)
+	ws.mu.Unlock()
+	defer ws.wg.Done()
+
+	ctx, span := tracing.Start(ctx, "WebhookService.Publish")
+	defer span.End()
+
+	// доставки прерываются и при отмене пакета, и при остановке сервиса
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(ws.ctx, cancel)
+	defer stop()
+
+	subscriptions := make(map[string][]enteties.WebhookSubscription)
+	deliveries := make([]webhookDelivery, 0)
+
+	for _, outboxEvent := range events {
+		if outboxEvent.EventType != enteties.OutboxEventWebhook {
+			continue
+		}
+
+		var event enteties.WebhookEvent
+		err := json.Unmarshal(outboxEvent.Payload, &event)
+		if err != nil {
+			// повтор не исправит событие, поэтому оно пропускается
+			slog.Error("failed decode webhook event", "error", err, "outbox_event_id", outboxEvent.EventID)
+			continue
+		}
+
+		teamSubscriptions, ok := subscriptions[event.TeamName]
+		if !ok {
+			teamSubscriptions, err = ws.WebhookRepo.GetTeamSubscriptions(ctx, event.TeamName)
+			if err != nil {
+				return fmt.Errorf("[WebhookService | Publish]: %w", err)
+			}
+
+			subscriptions[event.TeamName] = teamSubscriptions
+		}
+
+		// при повторе пакета пропускаем подписчиков, которые уже приняли событие
+		delivered := []int64{}
+		if outboxEvent.Attempt > 1 {
+			delivered, err = ws.WebhookRepo.GetDeliveredSubscriptions(ctx, event.EventID)
+			if err != nil {
+				return fmt.Errorf("[WebhookService | Publish]: %w", err)
+			}
+		}
+
+		for _, subscription := range teamSubscriptions {
+			if !slices.Contains(subscription.EventTypes, event.EventType) ||
+				slices.Contains(delivered, subscription.SubscriptionID) {
+				continue
+			}
+
+			deliveries = append(deliveries, webhookDelivery{
+				subscription: subscription,
+				event:        event,
+				body:         outboxEvent.Payload,
+				attempt:      outboxEvent.Attempt,
+			})
+		}
+	}
+
+	// подписчики не ждут друг друга, таймаут ограничивает каждую доставку
+	var (
+		wg          sync.WaitGroup
+		errMu       sync.Mutex
+		deliveryErr error
+	)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := ws.deliver(ctx, delivery)
+			if err != nil {
+				errMu.Lock()
+				deliveryErr = errors.Join(deliveryErr, err)
+				errMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if deliveryErr != nil {
+		return fmt.Errorf("[WebhookService | Publish]: %w", deliveryErr)
+	}
+
+	return nil
+}
+
+func (ws *webhookService) Shutdown(ctx context.Context) error {
+	ws.mu.Lock()
+	ws.closed = true
+	ws.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		ws.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		ws.cancel()
+		return nil
+	case <-ctx.Done():
+		ws.cancel()
+		<-done
+		return fmt.Errorf("[WebhookService | Shutdown]: %w", ctx.Err())
+	}
+}
+
+// доставка события одному подписчику
+type webhookDelivery struct {
+	subscription enteties.WebhookSubscription
+	event        enteties.WebhookEvent
+	body         []byte
+	attempt      int
+}
+
+// вспомогательный метод выполняет попытку доставки и записывает ее в журнал. Возвращает ошибку,
+// если доставка не удалась и попытки еще не исчерпаны
+func (ws *webhookService) deliver(ctx context.Context, d webhookDelivery) error {
+	statusCode, err := ws.send(ctx, d.subscription, d.event, d.body)
+
+	delivery := enteties.WebhookDelivery{
+		SubscriptionID: d.subscription.SubscriptionID,
+		EventID:        d.event.EventID,
+		EventType:      d.event.EventType,
+		Attempt:        d.attempt,
+		StatusCode:     statusCode,
+		Success:        err == nil,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	// журнал пишется и при отмене доставки, иначе повтор не узнает о принятых событиях
+	if logErr := ws.WebhookRepo.AddDelivery(context.WithoutCancel(ctx), &delivery); logErr != nil {
+		slog.Error("failed save webhook delivery", "error", logErr, "delivery", delivery)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if d.attempt >= ws.Options.MaxAttempts {
+		slog.Error("webhook delivery failed", "error", err, "subscription_id", d.subscription.SubscriptionID,
+			"event_id", d.event.EventID, "attempts", d.attempt)
+		return nil
+	}
+
+	return err
+}
+
+// вспомогательный метод выполняет одну попытку доставки, успешной считается ответ 2xx.
+// Возвращает код ответа (0, если ответ не получен)
+func (ws *webhookService) send(ctx context.Context, subscription enteties.WebhookSubscription, event enteties.WebhookEvent,
+	body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("[WebhookService | send]: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.EventType))
+	req.Header.Set(WebhookEventIDHeader, event.EventID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, body))
+
+	resp, err := ws.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("[WebhookService | send]: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// дочитываем ответ, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("[WebhookService | send]: unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload возвращает значение заголовка X-Webhook-Signature: HMAC-SHA256 тела
+// запроса с секретом подписки. Получатель считает подпись так же и сравнивает через hmac.Equal
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// вспомогательная функция записывает события для подписчиков webhook в outbox. Вызывается в
+// транзакции изменения, поэтому события доставляются, только если изменение зафиксировано
+func addWebhookEvents(ctx context.Context, outboxRepo repository.OutboxRepository, events []enteties.WebhookEvent) error {
+	for _, event := range events {
+		// pull request автора без команды некому отправлять
+		if event.TeamName == "" {
+			continue
+		}
+
+		err := addOutboxEvent(ctx, outboxRepo, enteties.OutboxEventWebhook, event)
+		if err != nil {
+			return fmt.Errorf("[addWebhookEvents]: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// вспомогательная функция формирует событие о pull request для подписчиков команды автора
+func newWebhookEvent(ctx context.Context, eventType enteties.WebhookEventType, teamName string,
+	pr *enteties.PullRequest) enteties.WebhookEvent {
+	payload := *pr
+	payload.AssignedReviewers = append([]string{}, pr.AssignedReviewers...)
+	payload.Reviews = append([]enteties.ReviewerReview(nil), pr.Reviews...)
+
+	return enteties.WebhookEvent{
+		EventID:     rand.Text(),
+		EventType:   eventType,
+		TeamName:    teamName,
+		Actor:       ActorFromContext(ctx),
+		OccurredAt:  time.Now().UTC(),
+		PullRequest: payload,
+	}
+}
+
+// вспомогательная функция формирует события reviewer.assigned по назначенным ревьюерам
+func reviewerAssignedEvents(ctx context.Context, teamName string, pr *enteties.PullRequest,
+	reviewers []string) []enteties.WebhookEvent {
+	events := make([]enteties.WebhookEvent, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		event := newWebhookEvent(ctx, enteties.WebhookEventReviewerAssigned, teamName, pr)
+		event.ReviewerID = reviewer
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// вспомогательная функция формирует события reviewer.reassigned по заменам ревьюеров
+func reviewerReassignedEvents(ctx context.Context, teamName string, pr *enteties.PullRequest,
+	replacements []enteties.ReviewerReplacement) []enteties.WebhookEvent {
+	events := make([]enteties.WebhookEvent, 0, len(replacements))
+	for _, r := range replacements {
+		event := newWebhookEvent(ctx, enteties.WebhookEventReviewerReassigned, teamName, pr)
+		event.OldReviewerID = r.OldUserID
+		event.NewReviewerID = r.ReplacedBy
+		events = append(events, event)
+	}
+
+	return events
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"avito_intern/internal/enteties"
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+// получатель событий: проверяет подпись и сохраняет принятые события. Первые failures
+// запросов получают 500
+type webhookReceiver struct {
+	mu       sync.Mutex
+	failures int
+	events   []enteties.WebhookEvent
+	invalid  int
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	signature := r.Header.Get(WebhookSignatureHeader)
+	if !hmac.Equal([]byte(signature), []byte(SignWebhookPayload(testWebhookSecret, body))) {
+		wr.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if wr.failures > 0 {
+		wr.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var event enteties.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || string(event.EventType) != r.Header.Get(WebhookEventHeader) {
+		wr.invalid++
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	wr.events = append(wr.events, event)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (wr *webhookReceiver) received() []enteties.WebhookEvent {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	return append([]enteties.WebhookEvent{}, wr.events...)
+}
+
+func TestWebhook_Delivery(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3", "u4")
+	createTestTeam(t, s, "frontend", "u5", "u6")
+
+	prEvents := &webhookReceiver{}
+	prServer := httptest.NewServer(prEvents)
+	defer prServer.Close()
+
+	// первые две попытки доставки завершатся ошибкой
+	reassignEvents := &webhookReceiver{failures: 2}
+	reassignServer := httptest.NewServer(reassignEvents)
+	defer reassignServer.Close()
+
+	otherTeam := &webhookReceiver{}
+	otherServer := httptest.NewServer(otherTeam)
+	defer otherServer.Close()
+
+	prSubscription, err := s.webhook.CreateSubscription(ctx, &enteties.CreateWebhookSubscription{
+		TeamName: "backend",
+		URL:      prServer.URL,
+		Secret:   testWebhookSecret,
+		EventTypes: []enteties.WebhookEventType{
+			enteties.WebhookEventPRCreated,
+			enteties.WebhookEventReviewerAssigned,
+			enteties.WebhookEventPRMerged,
+			enteties.WebhookEventPRMerged,
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, prSubscription.EventTypes, 3)
+
+	reassignSubscription, err := s.webhook.CreateSubscription(ctx, &enteties.CreateWebhookSubscription{
+		TeamName:   "backend",
+		URL:        reassignServer.URL,
+		Secret:     testWebhookSecret,
+		EventTypes: []enteties.WebhookEventType{enteties.WebhookEventReviewerReassigned},
+	})
+	require.NoError(t, err)
+
+	_, err = s.webhook.CreateSubscription(ctx, &enteties.CreateWebhookSubscription{
+		TeamName:   "frontend",
+		URL:        otherServer.URL,
+		Secret:     testWebhookSecret,
+		EventTypes: []enteties.WebhookEventType{enteties.WebhookEventPRCreated},
+	})
+	require.NoError(t, err)
+
+	pr, err := s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	reassigned, err := s.pr.ReassignPR(WithActor(ctx, "u1"), &enteties.ReassignPullRequest{
+		PullRequestID: "pr1",
+		OldUserID:     pr.AssignedReviewers[0],
+	})
+	require.NoError(t, err)
+
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+
+	// повторный мердж события не отправляет
+	_, err = s.pr.MergePR(ctx, &enteties.MergePullRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+
+	dispatchWebhooks(t, s)
+
+	received := prEvents.received()
+	require.Len(t, received, 4)
+
+	types := make(map[enteties.WebhookEventType]int)
+	assigned := make([]string, 0)
+	for _, event := range received {
+		types[event.EventType]++
+		assert.Equal(t, "backend", event.TeamName)
+		assert.Equal(t, "pr1", event.PullRequest.PullRequestID)
+		assert.NotEmpty(t, event.EventID)
+
+		if event.EventType == enteties.WebhookEventReviewerAssigned {
+			assigned = append(assigned, event.ReviewerID)
+		}
+	}
+	assert.Equal(t, map[enteties.WebhookEventType]int{
+		enteties.WebhookEventPRCreated:        1,
+		enteties.WebhookEventReviewerAssigned: 2,
+		enteties.WebhookEventPRMerged:         1,
+	}, types)
+	assert.ElementsMatch(t, pr.AssignedReviewers, assigned)
+
+	received = reassignEvents.received()
+	require.Len(t, received, 1)
+	assert.Equal(t, enteties.WebhookEventReviewerReassigned, received[0].EventType)
+	assert.Equal(t, pr.AssignedReviewers[0], received[0].OldReviewerID)
+	assert.Equal(t, reassigned.ReplacedBy, received[0].NewReviewerID)
+	assert.Equal(t, "u1", received[0].Actor)
+
+	assert.Empty(t, otherTeam.received())
+	assert.Zero(t, prEvents.invalid+reassignEvents.invalid+otherTeam.invalid)
+
+	// журнал хранит каждую попытку, новые первыми
+	deliveries, err := s.webhook.GetDeliveries(ctx, reassignSubscription.SubscriptionID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries.Deliveries, 3)
+	assert.True(t, deliveries.Deliveries[0].Success)
+	assert.Equal(t, 3, deliveries.Deliveries[0].Attempt)
+	assert.Equal(t, http.StatusNoContent, deliveries.Deliveries[0].StatusCode)
+	assert.False(t, deliveries.Deliveries[2].Success)
+	assert.Equal(t, 1, deliveries.Deliveries[2].Attempt)
+	assert.Equal(t, http.StatusInternalServerError, deliveries.Deliveries[2].StatusCode)
+	assert.NotEmpty(t, deliveries.Deliveries[2].Error)
+}
+
+func TestWebhook_GiveUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3")
+
+	receiver := &webhookReceiver{failures: 10}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	subscription, err := s.webhook.CreateSubscription(ctx, &enteties.CreateWebhookSubscription{
+		TeamName:   "backend",
+		URL:        server.URL,
+		Secret:     testWebhookSecret,
+		EventTypes: []enteties.WebhookEventType{enteties.WebhookEventPRCreated},
+	})
+	require.NoError(t, err)
+
+	_, err = s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	dispatchWebhooks(t, s)
+
+	deliveries, err := s.webhook.GetDeliveries(ctx, subscription.SubscriptionID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries.Deliveries, s.webhook.Options.MaxAttempts)
+	for _, delivery := range deliveries.Deliveries {
+		assert.False(t, delivery.Success)
+	}
+	assert.Empty(t, receiver.received())
+
+	err = s.webhook.DeleteSubscription(ctx, &enteties.DeleteWebhookSubscription{SubscriptionID: subscription.SubscriptionID})
+	require.NoError(t, err)
+
+	_, err = s.webhook.GetDeliveries(ctx, subscription.SubscriptionID, 0)
+	assert.ErrorIs(t, err, ErrorWebhookNotFound)
+
+	err = s.webhook.DeleteSubscription(ctx, &enteties.DeleteWebhookSubscription{SubscriptionID: subscription.SubscriptionID})
+	assert.ErrorIs(t, err, ErrorWebhookNotFound)
+
+	_, err = s.webhook.CreateSubscription(ctx, &enteties.CreateWebhookSubscription{
+		TeamName:   "unknown",
+		URL:        server.URL,
+		Secret:     testWebhookSecret,
+		EventTypes: []enteties.WebhookEventType{enteties.WebhookEventPRCreated},
+	})
+	assert.ErrorIs(t, err, ErrorTeamNotFound)
+}
+
+func TestWebhook_Shutdown(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices(t)
+
+	createTestTeam(t, s, "backend", "u1", "u2", "u3")
+
+	// получатель не отвечает, пока запрос не отменят (отмену сервер замечает после чтения тела)
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	subscription, err := s.webhook.CreateSubscription(ctx, &enteties.CreateWebhookSubscription{
+		TeamName:   "backend",
+		URL:        server.URL,
+		Secret:     testWebhookSecret,
+		EventTypes: []enteties.WebhookEventType{enteties.WebhookEventPRCreated},
+	})
+	require.NoError(t, err)
+
+	_, err = s.pr.CreatePR(ctx, &enteties.CreatePullRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "name",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	events, err := s.outbox.ClaimEvents(ctx, 100, time.Minute)
+	require.NoError(t, err)
+
+	published := make(chan error, 1)
+	go func() {
+		published <- s.webhook.Publish(ctx, events)
+	}()
+	<-started
+
+	// остановка не дождалась доставки: доставка прерывается, событие повторит outbox
+	shutdownCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	err = s.webhook.Shutdown(shutdownCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Error(t, <-published)
+
+	deliveries, err := s.webhook.GetDeliveries(ctx, subscription.SubscriptionID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries.Deliveries, 1)
+	assert.False(t, deliveries.Deliveries[0].Success)
+
+	// после остановки новые пакеты не принимаются
+	err = s.webhook.Publish(ctx, events)
+	assert.ErrorIs(t, err, ErrorWebhookServiceClosed)
+}
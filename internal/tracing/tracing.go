@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"avito_intern/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ErrorUnknownTracingExporter = errors.New("unknown tracing exporter")
+
+// имя инструментирования, под которым сервис создает спаны
+const instrumentationName = "avito_intern"
+
+type spanContextKey struct{}
+
+// SpanKey ключ контекста, по которому middleware сохраняет спан HTTP запроса. Обработчики передают
+// в сервисы контекст запроса fasthttp, в который можно положить значение только по ключу
+var SpanKey = spanContextKey{}
+
+// Tracer возвращает трассировщик зарегистрированного провайдера (без провайдера спаны не записываются)
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start начинает дочерний спан метода сервиса или репозитория. Родитель берется из контекста, в том
+// числе спан HTTP запроса по SpanKey. Без родителя спан не создается, поэтому фоновые задачи
+// (опрос outbox, сбор метрик) не порождают отдельных трассировок
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.SpanContext().IsValid() {
+		span, ok := ctx.Value(SpanKey).(trace.Span)
+		if !ok {
+			return ctx, parent
+		}
+		ctx = trace.ContextWithSpan(ctx, span)
+	}
+
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// NewProvider создает провайдер спанов с экспортером из настроек TRACING_*, регистрирует его
+// глобально вместе с пропагатором W3C Trace Context и возвращает для остановки приложения.
+// При TRACING_EXPORTER=none возвращает nil
+func NewProvider(ctx context.Context, cfg *config.Config) (*sdktrace.TracerProvider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch cfg.Tracing.Exporter {
+	case config.TracingExporterNone:
+		return nil, nil
+	case config.TracingExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Tracing.OTLPEndpoint)}
+		if cfg.Tracing.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case config.TracingExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case config.TracingExporterFile:
+		exporter, err = newFileExporter(cfg.Tracing.FilePath)
+	default:
+		return nil, fmt.Errorf("[NewProvider]: %w: %s", ErrorUnknownTracingExporter, cfg.Tracing.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[NewProvider]: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", cfg.Tracing.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("[NewProvider]: %w", err)
+	}
+
+	// решение о записи из traceparent вызывающего важнее доли трассировок
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider, nil
+}
+
+// экспортер пишет спаны в файл по одному JSON объекту на строку и закрывает файл при остановке
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file io.Closer
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &fileExporter{
+		SpanExporter: exporter,
+		file:         file,
+	}, nil
+}
+
+func (fe *fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(fe.SpanExporter.Shutdown(ctx), fe.file.Close())
+}
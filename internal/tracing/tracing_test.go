@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"avito_intern/internal/config"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// вспомогательная функция регистрирует провайдер, который запоминает завершенные спаны
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	return recorder
+}
+
+// вспомогательная функция возвращает значение атрибута спана
+func spanAttr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if string(attr.Key) == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestStart(t *testing.T) {
+	recorder := recordSpans(t)
+
+	// без родителя спан не создается
+	ctx, span := Start(context.Background(), "Repository.Background")
+	span.End()
+	assert.False(t, span.SpanContext().IsValid())
+	assert.Equal(t, context.Background(), ctx)
+	assert.Empty(t, recorder.Ended())
+
+	// родитель - спан HTTP запроса, сохраненный по SpanKey
+	_, request := Tracer().Start(context.Background(), "POST /pullRequest/create")
+	ctx = context.WithValue(context.Background(), SpanKey, request)
+
+	ctx, service := Start(ctx, "PRService.CreatePR")
+	_, repo := Start(ctx, "PRRepository.CreatePR")
+	repo.End()
+	service.End()
+	request.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "PRRepository.CreatePR", spans[0].Name())
+	assert.Equal(t, service.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, "PRService.CreatePR", spans[1].Name())
+	assert.Equal(t, request.SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.Equal(t, request.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := recordSpans(t)
+	qt := NewQueryTracer()
+
+	// запрос вне трассировки не записывается
+	ctx := qt.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	assert.Empty(t, recorder.Ended())
+
+	parent, span := Tracer().Start(context.Background(), "UserRepository.GetUser")
+	defer span.End()
+
+	ctx = qt.TraceQueryStart(parent, nil, pgx.TraceQueryStartData{SQL: "\n\tselect user_id FROM users WHERE user_id = $1", Args: []any{"u1"}})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	ctx = qt.TraceQueryStart(parent, nil, pgx.TraceQueryStartData{SQL: "UPDATE users SET is_active = $1"})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("deadlock detected")})
+
+	batch := &pgx.Batch{}
+	batch.Queue("INSERT INTO assigned_reviewers (pull_request_id,user_id) VALUES ($1,$2)", "pr1", "u2")
+	batch.Queue("INSERT INTO assigned_reviewers (pull_request_id,user_id) VALUES ($1,$2)", "pr1", "u3")
+
+	ctx = qt.TraceBatchStart(parent, nil, pgx.TraceBatchStartData{Batch: batch})
+	for _, query := range batch.QueuedQueries {
+		qt.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{SQL: query.SQL})
+	}
+	qt.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	// отсутствие строк не ошибка, аргументы в спан не попадают
+	assert.Equal(t, "SELECT", spans[0].Name())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, "\n\tselect user_id FROM users WHERE user_id = $1", spanAttr(spans[0], "db.statement").AsString())
+	assert.Equal(t, "postgresql", spanAttr(spans[0], "db.system").AsString())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Len(t, spans[0].Attributes(), 2)
+
+	assert.Equal(t, "UPDATE", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "deadlock detected", spans[1].Status().Description)
+
+	assert.Equal(t, "BATCH", spans[2].Name())
+	assert.Equal(t, int64(2), spanAttr(spans[2], "db.batch.size").AsInt64())
+	require.Len(t, spans[2].Events(), 2)
+	assert.Equal(t, "INSERT INTO assigned_reviewers (pull_request_id,user_id) VALUES ($1,$2)",
+		spans[2].Events()[0].Attributes[0].Value.AsString())
+}
+
+func TestNewProvider(t *testing.T) {
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	cfg := &config.Config{}
+
+	cfg.Tracing.Exporter = config.TracingExporterNone
+	provider, err := NewProvider(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	cfg.Tracing.Exporter = "jaeger"
+	_, err = NewProvider(context.Background(), cfg)
+	assert.ErrorIs(t, err, ErrorUnknownTracingExporter)
+
+	// file пишет спаны в JSON, по объекту на строку
+	cfg.Tracing.Exporter = config.TracingExporterFile
+	cfg.Tracing.FilePath = filepath.Join(t.TempDir(), "traces.jsonl")
+	cfg.Tracing.ServiceName = "pr-reviewer"
+	cfg.Tracing.SampleRatio = 1
+
+	provider, err = NewProvider(context.Background(), cfg)
+	require.NoError(t, err)
+
+	parent, span := Tracer().Start(context.Background(), "POST /team/add")
+	_, child := Start(parent, "TeamService.CreateTeam")
+	child.End()
+	span.End()
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(cfg.Tracing.FilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"TeamService.CreateTeam"`)
+	assert.Contains(t, string(data), `"Name":"POST /team/add"`)
+	assert.Contains(t, string(data), `"Value":"pr-reviewer"`)
+}
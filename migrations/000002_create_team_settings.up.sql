@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_name VARCHAR(255) PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    min_reviewers INT NOT NULL CHECK (min_reviewers >= 0),
+    max_reviewers INT NOT NULL CHECK (max_reviewers >= min_reviewers)
+);
+
+COMMIT;
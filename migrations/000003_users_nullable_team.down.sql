@@ -0,0 +1,14 @@
+BEGIN;
+
+-- пользователи без команды связаны с историей pull request (авторы, ревьюеры), удалять их нельзя:
+-- откат прерывается, пока им не назначена команда
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE team_name IS NULL) THEN
+        RAISE EXCEPTION 'users without team exist, assign them to a team before rolling back';
+    END IF;
+END $$;
+
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
+
+COMMIT;
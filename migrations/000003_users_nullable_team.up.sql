@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+-- пользователь, удаленный из команды, остается в базе без команды, чтобы сохранить историю pull request
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN TRANSACTION;
+
+-- история назначений ревьюеров: ASSIGN (new_user_id), REASSIGN (old_user_id -> new_user_id),
+-- UNASSIGN (old_user_id). actor - инициатор действия (user_id или system)
+CREATE TABLE IF NOT EXISTS assignment_events (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(100) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('ASSIGN', 'REASSIGN', 'UNASSIGN')),
+    old_user_id VARCHAR(100) REFERENCES users(user_id) ON DELETE CASCADE,
+    new_user_id VARCHAR(100) REFERENCES users(user_id) ON DELETE CASCADE,
+    actor VARCHAR(100) NOT NULL DEFAULT 'system',
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_assignment_events_pr_id ON assignment_events(pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_assignment_events_old_user_id ON assignment_events(old_user_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+-- черновики pull request хранятся без ревьюеров до перевода в готовые к ревью
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS is_draft BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
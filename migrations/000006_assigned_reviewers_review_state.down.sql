@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE assigned_reviewers DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE assigned_reviewers DROP COLUMN IF EXISTS review_state;
+
+COMMIT;
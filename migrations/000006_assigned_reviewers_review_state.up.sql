@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+-- решение ревьюера по pull request, при замене ревьюера сбрасывается в PENDING
+ALTER TABLE assigned_reviewers ADD COLUMN IF NOT EXISTS review_state VARCHAR(50) NOT NULL DEFAULT 'PENDING'
+    CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED'));
+ALTER TABLE assigned_reviewers ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+-- keyset пагинация списков pull request идет по (created_at, pull_request_id)
+CREATE INDEX IF NOT EXISTS idx_pr_created_at_id ON pull_requests(created_at, pull_request_id);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+-- выданные роли пользователей, пользователь без записи имеет роль member. team_lead руководит
+-- одной командой и теряет роль вместе с удалением команды
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id VARCHAR(100) PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'team_lead')),
+    team_name VARCHAR(255) REFERENCES teams(team_name) ON DELETE CASCADE,
+    granted_by VARCHAR(100) NOT NULL,
+    granted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((role = 'team_lead') = (team_name IS NOT NULL))
+);
+CREATE INDEX IF NOT EXISTS idx_user_roles_team_name ON user_roles(team_name);
+
+COMMIT;
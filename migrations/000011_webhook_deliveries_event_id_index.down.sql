@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_event_id;
+
+COMMIT;
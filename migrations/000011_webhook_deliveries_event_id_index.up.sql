@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+-- при повторной отправке события из outbox пропускаются подписки, которые его уже приняли
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries(event_id);
+
+COMMIT;
@@ -35,6 +35,21 @@ func (m *MockPRService) EXPECT() *MockPRServiceMockRecorder {
 	return m.recorder
 }
 
+// ClosePR mocks base method.
+func (m *MockPRService) ClosePR(ctx context.Context, closeReq *enteties.ClosePullRequest) (*enteties.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePR", ctx, closeReq)
+	ret0, _ := ret[0].(*enteties.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePR indicates an expected call of ClosePR.
+func (mr *MockPRServiceMockRecorder) ClosePR(ctx, closeReq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePR", reflect.TypeOf((*MockPRService)(nil).ClosePR), ctx, closeReq)
+}
+
 // CreatePR mocks base method.
 func (m *MockPRService) CreatePR(ctx context.Context, pr *enteties.CreatePullRequest) (*enteties.PullRequest, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +65,66 @@ func (mr *MockPRServiceMockRecorder) CreatePR(ctx, pr interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePR", reflect.TypeOf((*MockPRService)(nil).CreatePR), ctx, pr)
 }
 
+// GetHistory mocks base method.
+func (m *MockPRService) GetHistory(ctx context.Context, prID string) (*enteties.PullRequestHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, prID)
+	ret0, _ := ret[0].(*enteties.PullRequestHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockPRServiceMockRecorder) GetHistory(ctx, prID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockPRService)(nil).GetHistory), ctx, prID)
+}
+
+// GetPR mocks base method.
+func (m *MockPRService) GetPR(ctx context.Context, prID string) (*enteties.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPR", ctx, prID)
+	ret0, _ := ret[0].(*enteties.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPR indicates an expected call of GetPR.
+func (mr *MockPRServiceMockRecorder) GetPR(ctx, prID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPR", reflect.TypeOf((*MockPRService)(nil).GetPR), ctx, prID)
+}
+
+// ListPRs mocks base method.
+func (m *MockPRService) ListPRs(ctx context.Context, filter *enteties.PullRequestListFilter) (*enteties.PullRequestList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPRs", ctx, filter)
+	ret0, _ := ret[0].(*enteties.PullRequestList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPRs indicates an expected call of ListPRs.
+func (mr *MockPRServiceMockRecorder) ListPRs(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPRs", reflect.TypeOf((*MockPRService)(nil).ListPRs), ctx, filter)
+}
+
+// MarkReady mocks base method.
+func (m *MockPRService) MarkReady(ctx context.Context, readyReq *enteties.MarkReadyPullRequest) (*enteties.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReady", ctx, readyReq)
+	ret0, _ := ret[0].(*enteties.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkReady indicates an expected call of MarkReady.
+func (mr *MockPRServiceMockRecorder) MarkReady(ctx, readyReq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReady", reflect.TypeOf((*MockPRService)(nil).MarkReady), ctx, readyReq)
+}
+
 // MergePR mocks base method.
 func (m *MockPRService) MergePR(ctx context.Context, mergeReq *enteties.MergePullRequest) (*enteties.PullRequest, error) {
 	m.ctrl.T.Helper()
@@ -79,3 +154,33 @@ func (mr *MockPRServiceMockRecorder) ReassignPR(ctx, resp interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignPR", reflect.TypeOf((*MockPRService)(nil).ReassignPR), ctx, resp)
 }
+
+// ReopenPR mocks base method.
+func (m *MockPRService) ReopenPR(ctx context.Context, reopenReq *enteties.ReopenPullRequest) (*enteties.ReopenPullRequestResponce, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenPR", ctx, reopenReq)
+	ret0, _ := ret[0].(*enteties.ReopenPullRequestResponce)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReopenPR indicates an expected call of ReopenPR.
+func (mr *MockPRServiceMockRecorder) ReopenPR(ctx, reopenReq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenPR", reflect.TypeOf((*MockPRService)(nil).ReopenPR), ctx, reopenReq)
+}
+
+// SubmitReview mocks base method.
+func (m *MockPRService) SubmitReview(ctx context.Context, reviewReq *enteties.ReviewPullRequest) (*enteties.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitReview", ctx, reviewReq)
+	ret0, _ := ret[0].(*enteties.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitReview indicates an expected call of SubmitReview.
+func (mr *MockPRServiceMockRecorder) SubmitReview(ctx, reviewReq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitReview", reflect.TypeOf((*MockPRService)(nil).SubmitReview), ctx, reviewReq)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: stats_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	enteties "avito_intern/internal/enteties"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStatsService is a mock of StatsService interface.
+type MockStatsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsServiceMockRecorder
+}
+
+// MockStatsServiceMockRecorder is the mock recorder for MockStatsService.
+type MockStatsServiceMockRecorder struct {
+	mock *MockStatsService
+}
+
+// NewMockStatsService creates a new mock instance.
+func NewMockStatsService(ctrl *gomock.Controller) *MockStatsService {
+	mock := &MockStatsService{ctrl: ctrl}
+	mock.recorder = &MockStatsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsService) EXPECT() *MockStatsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPullRequestStats mocks base method.
+func (m *MockStatsService) GetPullRequestStats(ctx context.Context, filter *enteties.PullRequestStatsFilter) (*enteties.PullRequestStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPullRequestStats", ctx, filter)
+	ret0, _ := ret[0].(*enteties.PullRequestStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPullRequestStats indicates an expected call of GetPullRequestStats.
+func (mr *MockStatsServiceMockRecorder) GetPullRequestStats(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullRequestStats", reflect.TypeOf((*MockStatsService)(nil).GetPullRequestStats), ctx, filter)
+}
+
+// GetReviewerStats mocks base method.
+func (m *MockStatsService) GetReviewerStats(ctx context.Context, filter *enteties.ReviewerStatsFilter) (*enteties.ReviewerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewerStats", ctx, filter)
+	ret0, _ := ret[0].(*enteties.ReviewerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewerStats indicates an expected call of GetReviewerStats.
+func (mr *MockStatsServiceMockRecorder) GetReviewerStats(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewerStats", reflect.TypeOf((*MockStatsService)(nil).GetReviewerStats), ctx, filter)
+}
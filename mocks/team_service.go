@@ -35,6 +35,21 @@ func (m *MockTeamService) EXPECT() *MockTeamServiceMockRecorder {
 	return m.recorder
 }
 
+// AddMembers mocks base method.
+func (m *MockTeamService) AddMembers(ctx context.Context, req *enteties.AddTeamMembers) (*enteties.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMembers", ctx, req)
+	ret0, _ := ret[0].(*enteties.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMembers indicates an expected call of AddMembers.
+func (mr *MockTeamServiceMockRecorder) AddMembers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMembers", reflect.TypeOf((*MockTeamService)(nil).AddMembers), ctx, req)
+}
+
 // CreateTeam mocks base method.
 func (m *MockTeamService) CreateTeam(ctx context.Context, team *enteties.Team) (*enteties.Team, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +65,36 @@ func (mr *MockTeamServiceMockRecorder) CreateTeam(ctx, team interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeam", reflect.TypeOf((*MockTeamService)(nil).CreateTeam), ctx, team)
 }
 
+// DeactivateUsers mocks base method.
+func (m *MockTeamService) DeactivateUsers(ctx context.Context, req *enteties.DeactivateUsers) (*enteties.DeactivateUsersResponce, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateUsers", ctx, req)
+	ret0, _ := ret[0].(*enteties.DeactivateUsersResponce)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateUsers indicates an expected call of DeactivateUsers.
+func (mr *MockTeamServiceMockRecorder) DeactivateUsers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUsers", reflect.TypeOf((*MockTeamService)(nil).DeactivateUsers), ctx, req)
+}
+
+// DeleteTeam mocks base method.
+func (m *MockTeamService) DeleteTeam(ctx context.Context, req *enteties.DeleteTeam) (*enteties.DeleteTeamResponce, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTeam", ctx, req)
+	ret0, _ := ret[0].(*enteties.DeleteTeamResponce)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTeam indicates an expected call of DeleteTeam.
+func (mr *MockTeamServiceMockRecorder) DeleteTeam(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTeam", reflect.TypeOf((*MockTeamService)(nil).DeleteTeam), ctx, req)
+}
+
 // GetTeam mocks base method.
 func (m *MockTeamService) GetTeam(ctx context.Context, teamName string) (*enteties.Team, error) {
 	m.ctrl.T.Helper()
@@ -64,3 +109,63 @@ func (mr *MockTeamServiceMockRecorder) GetTeam(ctx, teamName interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeam", reflect.TypeOf((*MockTeamService)(nil).GetTeam), ctx, teamName)
 }
+
+// GetTeamSettings mocks base method.
+func (m *MockTeamService) GetTeamSettings(ctx context.Context, teamName string) (*enteties.TeamSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamSettings", ctx, teamName)
+	ret0, _ := ret[0].(*enteties.TeamSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamSettings indicates an expected call of GetTeamSettings.
+func (mr *MockTeamServiceMockRecorder) GetTeamSettings(ctx, teamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamSettings", reflect.TypeOf((*MockTeamService)(nil).GetTeamSettings), ctx, teamName)
+}
+
+// MoveMember mocks base method.
+func (m *MockTeamService) MoveMember(ctx context.Context, req *enteties.MoveTeamMember) (*enteties.MoveTeamMemberResponce, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveMember", ctx, req)
+	ret0, _ := ret[0].(*enteties.MoveTeamMemberResponce)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveMember indicates an expected call of MoveMember.
+func (mr *MockTeamServiceMockRecorder) MoveMember(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveMember", reflect.TypeOf((*MockTeamService)(nil).MoveMember), ctx, req)
+}
+
+// RemoveMembers mocks base method.
+func (m *MockTeamService) RemoveMembers(ctx context.Context, req *enteties.RemoveTeamMembers) (*enteties.RemoveTeamMembersResponce, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMembers", ctx, req)
+	ret0, _ := ret[0].(*enteties.RemoveTeamMembersResponce)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMembers indicates an expected call of RemoveMembers.
+func (mr *MockTeamServiceMockRecorder) RemoveMembers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMembers", reflect.TypeOf((*MockTeamService)(nil).RemoveMembers), ctx, req)
+}
+
+// UpdateTeamSettings mocks base method.
+func (m *MockTeamService) UpdateTeamSettings(ctx context.Context, req *enteties.UpdateTeamSettings) (*enteties.TeamSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTeamSettings", ctx, req)
+	ret0, _ := ret[0].(*enteties.TeamSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTeamSettings indicates an expected call of UpdateTeamSettings.
+func (mr *MockTeamServiceMockRecorder) UpdateTeamSettings(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeamSettings", reflect.TypeOf((*MockTeamService)(nil).UpdateTeamSettings), ctx, req)
+}
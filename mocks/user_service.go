@@ -36,18 +36,18 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // GetReviews mocks base method.
-func (m *MockUserService) GetReviews(ctx context.Context, userID string) (*enteties.UserReviews, error) {
+func (m *MockUserService) GetReviews(ctx context.Context, filter *enteties.UserReviewsFilter) (*enteties.UserReviews, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReviews", ctx, userID)
+	ret := m.ctrl.Call(m, "GetReviews", ctx, filter)
 	ret0, _ := ret[0].(*enteties.UserReviews)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetReviews indicates an expected call of GetReviews.
-func (mr *MockUserServiceMockRecorder) GetReviews(ctx, userID interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) GetReviews(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockUserService)(nil).GetReviews), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockUserService)(nil).GetReviews), ctx, filter)
 }
 
 // SetIsActive mocks base method.
@@ -64,3 +64,18 @@ func (mr *MockUserServiceMockRecorder) SetIsActive(ctx, userID, status interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsActive", reflect.TypeOf((*MockUserService)(nil).SetIsActive), ctx, userID, status)
 }
+
+// SetUsername mocks base method.
+func (m *MockUserService) SetUsername(ctx context.Context, userID, userName string) (*enteties.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUsername", ctx, userID, userName)
+	ret0, _ := ret[0].(*enteties.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUsername indicates an expected call of SetUsername.
+func (mr *MockUserServiceMockRecorder) SetUsername(ctx, userID, userName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUsername", reflect.TypeOf((*MockUserService)(nil).SetUsername), ctx, userID, userName)
+}